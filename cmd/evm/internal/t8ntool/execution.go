@@ -211,6 +211,9 @@ func (pre *Prestate) Apply(vmConfig vm.Config, chainConfig *params.ChainConfig,
 		chainConfig.DAOForkBlock.Cmp(new(big.Int).SetUint64(pre.Env.Number)) == 0 {
 		misc.ApplyDAOHardFork(statedb)
 	}
+	if chainConfig.IsSmartDeFiTransition(new(big.Int).SetUint64(pre.Env.Number), pre.Env.ParentTimestamp, pre.Env.Timestamp) {
+		misc.ApplySmartDeFiHardFork(statedb, chainConfig)
+	}
 	evm := vm.NewEVM(vmContext, statedb, chainConfig, vmConfig)
 	if beaconRoot := pre.Env.ParentBeaconBlockRoot; beaconRoot != nil {
 		core.ProcessBeaconBlockRoot(*beaconRoot, evm)
@@ -93,6 +93,8 @@ var (
 		utils.LogHistoryFlag,
 		utils.LogNoHistoryFlag,
 		utils.LogExportCheckpointsFlag,
+		utils.FloorPriceIndexFlag,
+		utils.FloorPriceHistoryFlag,
 		utils.StateHistoryFlag,
 		utils.LightKDFFlag,
 		utils.EthRequiredBlocksFlag,
@@ -29,13 +29,16 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
 	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/urfave/cli/v2"
 )
 
@@ -112,6 +115,26 @@ will traverse the whole state from the given state root and will abort if any
 referenced trie node or contract code is missing. This command can be used for
 state integrity verification. The default checking target is the HEAD state.
 
+It's also usable without snapshot enabled.
+`,
+			},
+			{
+				Name:      "verify-backing",
+				Usage:     "Verify that the SmartDeFi backing pools are covered by the locked Smart coin",
+				ArgsUsage: "<root>",
+				Action:    verifyBacking,
+				Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth snapshot verify-backing <state-root>
+will check the SmartDeFi backing invariants in the state with the given root:
+the balance of the asset-backing precompile must equal the total backing of all
+pools, no pool may hold a negative value, and no account outside the token
+registry and the legacy tokens of the chain config may hold pool slots. The
+legacy pool slots are derived from the address, so they are only checked for
+accounts with a preimage. Every violation is reported, and the command exits
+with a non-zero status if any is found. The default checking target is the HEAD
+state.
+
 It's also usable without snapshot enabled.
 `,
 			},
@@ -380,6 +403,165 @@ func traverseState(ctx *cli.Context) error {
 	return nil
 }
 
+// verifyBacking checks the SmartDeFi backing invariants in the given state: the
+// pools of the registered and legacy tokens must be consistent and covered by
+// the balance of the precompile, and no other account may hold backing pool
+// slots. The state trie is walked to find the latter, as such pools are
+// invisible to the precompile.
+func verifyBacking(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, true)
+	defer chaindb.Close()
+
+	triedb := utils.MakeTrieDatabase(ctx, stack, chaindb, false, true, false)
+	defer triedb.Close()
+
+	headBlock := rawdb.ReadHeadBlock(chaindb)
+	if headBlock == nil {
+		log.Error("Failed to load head block")
+		return errors.New("no head block")
+	}
+	if ctx.NArg() > 1 {
+		log.Error("Too many arguments given")
+		return errors.New("too many arguments")
+	}
+	var (
+		root common.Hash
+		err  error
+	)
+	if ctx.NArg() == 1 {
+		root, err = parseRoot(ctx.Args().First())
+		if err != nil {
+			log.Error("Failed to resolve state root", "err", err)
+			return err
+		}
+		log.Info("Start verifying the backing", "root", root)
+	} else {
+		root = headBlock.Root()
+		log.Info("Start verifying the backing", "root", root, "number", headBlock.NumberU64())
+	}
+	statedb, err := state.New(root, state.NewDatabase(triedb, nil))
+	if err != nil {
+		log.Error("Failed to open state", "root", root, "err", err)
+		return err
+	}
+	// Tokens created before the registry are only known from the chain config
+	var legacy []common.Address
+	if config := rawdb.ReadChainConfig(chaindb, rawdb.ReadCanonicalHash(chaindb, 0)); config != nil {
+		legacy = config.SmartDeFiLegacyTokens
+	}
+	report := assetbacking.CheckSolvency(statedb, legacy)
+	issues := len(report.Issues)
+	for _, issue := range report.Issues {
+		log.Error("Backing invariant violated", "token", issue.Token, "reason", issue.Reason)
+	}
+
+	// Walk the accounts for pool slots outside the registered and legacy tokens
+	start := time.Now()
+	orphans, accounts, err := orphanedPoolSlots(triedb, root, report.Tokens)
+	if err != nil {
+		return err
+	}
+	for _, orphan := range orphans {
+		if orphan.account != nil {
+			log.Error("Orphaned backing pool slot", "account", *orphan.account, "slot", orphan.slot)
+		} else {
+			log.Error("Orphaned backing pool slot", "accounthash", orphan.hash, "slot", orphan.slot)
+		}
+	}
+	issues += len(orphans)
+
+	if issues > 0 {
+		log.Error("Backing is inconsistent", "tokens", len(report.Tokens), "backing", report.TotalBacking, "locked", report.Locked, "issues", issues)
+		return fmt.Errorf("found %d backing invariant violations", issues)
+	}
+	log.Info("Backing is consistent", "tokens", len(report.Tokens), "backing", report.TotalBacking, "locked", report.Locked, "accounts", accounts, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// orphanedPoolSlot is a backing pool slot held by an account which is not a
+// known token. The address of the account is nil if it has no preimage.
+type orphanedPoolSlot struct {
+	account *common.Address
+	hash    common.Hash
+	slot    common.Hash
+}
+
+// orphanedPoolSlots walks the accounts of the state with the given root and
+// returns the pool slots held by accounts other than the given tokens, along
+// with the number of accounts walked. Accounts are keyed by hash, so only the
+// namespaced slots, which are the same in every account, can be checked for
+// all of them. The legacy slots are derived from the address, and are checked
+// for accounts with a preimage.
+func orphanedPoolSlots(db *triedb.Database, root common.Hash, tokens []common.Address) ([]orphanedPoolSlot, int, error) {
+	known := make(map[common.Hash]bool, len(tokens))
+	for _, token := range tokens {
+		known[crypto.Keccak256Hash(token.Bytes())] = true
+	}
+	t, err := trie.NewStateTrie(trie.StateTrieID(root), db)
+	if err != nil {
+		log.Error("Failed to open trie", "root", root, "err", err)
+		return nil, 0, err
+	}
+	var (
+		orphans    []orphanedPoolSlot
+		accounts   int
+		lastReport time.Time
+		start      = time.Now()
+	)
+	acctIt, err := t.NodeIterator(nil)
+	if err != nil {
+		log.Error("Failed to open iterator", "root", root, "err", err)
+		return nil, 0, err
+	}
+	accIter := trie.NewIterator(acctIt)
+	for accIter.Next() {
+		accounts += 1
+		var acc types.StateAccount
+		if err := rlp.DecodeBytes(accIter.Value, &acc); err != nil {
+			log.Error("Invalid account encountered during traversal", "err", err)
+			return nil, 0, err
+		}
+		hash := common.BytesToHash(accIter.Key)
+		if acc.Root != types.EmptyRootHash && !known[hash] {
+			id := trie.StorageTrieID(root, hash, acc.Root)
+			storageTrie, err := trie.NewStateTrie(id, db)
+			if err != nil {
+				log.Error("Failed to open storage trie", "root", acc.Root, "err", err)
+				return nil, 0, err
+			}
+			var account *common.Address
+			slots := backingpool.PoolSlots(0)
+			if preimage := t.GetKey(accIter.Key); preimage != nil {
+				addr := common.BytesToAddress(preimage)
+				account = &addr
+				slots = append(slots, backingpool.LegacyPoolSlots(addr, 0)...)
+			}
+			for _, slot := range slots {
+				value, err := storageTrie.GetStorage(common.Address{}, slot.Bytes())
+				if err != nil {
+					log.Error("Failed to read storage", "root", acc.Root, "err", err)
+					return nil, 0, err
+				}
+				if len(value) != 0 {
+					orphans = append(orphans, orphanedPoolSlot{account: account, hash: hash, slot: slot})
+				}
+			}
+		}
+		if time.Since(lastReport) > time.Second*8 {
+			log.Info("Verifying the backing", "accounts", accounts, "elapsed", common.PrettyDuration(time.Since(start)))
+			lastReport = time.Now()
+		}
+	}
+	if accIter.Err != nil {
+		log.Error("Failed to traverse state trie", "root", root, "err", accIter.Err)
+		return nil, 0, accIter.Err
+	}
+	return orphans, accounts, nil
+}
+
 // traverseRawState is a helper function used for pruning verification.
 // Basically it just iterates the trie, ensure all nodes and associated
 // contract codes are present. It's basically identical to traverseState
@@ -0,0 +1,85 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/hashdb"
+	"github.com/holiman/uint256"
+)
+
+// Tests that pool slots outside the known tokens are found in both the
+// namespaced and the legacy layout.
+func TestOrphanedPoolSlots(t *testing.T) {
+	var (
+		token      = common.HexToAddress("0x70c1")
+		namespaced = common.HexToAddress("0xaa")
+		legacy     = common.HexToAddress("0xbb")
+		unrelated  = common.HexToAddress("0xcc")
+		value      = common.BigToHash(common.Big1)
+	)
+	tdb := triedb.NewDatabase(rawdb.NewMemoryDatabase(), &triedb.Config{Preimages: true, HashDB: hashdb.Defaults})
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(tdb, nil))
+	for _, addr := range []common.Address{token, namespaced, legacy, unrelated} {
+		statedb.AddBalance(addr, uint256.NewInt(1), tracing.BalanceChangeUnspecified)
+	}
+	// The known token holds a pool in both layouts, which is not orphaned
+	statedb.SetState(token, backingpool.PoolSlot(backingpool.SlotTotalSupply), value)
+	statedb.SetState(token, backingpool.LegacyPoolSlots(token, 0)[backingpool.SlotTotalSupply], value)
+
+	statedb.SetState(namespaced, backingpool.PoolSlot(backingpool.SlotTotalBacking), value)
+	statedb.SetState(legacy, backingpool.LegacyPoolSlots(legacy, 0)[backingpool.SlotTotalSupply], value)
+	statedb.SetState(unrelated, common.Hash{}, value)
+
+	root, err := statedb.Commit(0, false, false)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if err := tdb.Commit(root, false); err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+	orphans, accounts, err := orphanedPoolSlots(tdb, root, []common.Address{token})
+	if err != nil {
+		t.Fatalf("failed to scan for orphaned pools: %v", err)
+	}
+	if accounts != 4 {
+		t.Errorf("account count mismatch: have %d, want 4", accounts)
+	}
+	want := map[common.Address]common.Hash{
+		namespaced: backingpool.PoolSlot(backingpool.SlotTotalBacking),
+		legacy:     backingpool.LegacyPoolSlots(legacy, 0)[backingpool.SlotTotalSupply],
+	}
+	if len(orphans) != len(want) {
+		t.Fatalf("orphan count mismatch: have %d, want %d", len(orphans), len(want))
+	}
+	for _, orphan := range orphans {
+		if orphan.account == nil {
+			t.Fatalf("orphan %x: missing preimage", orphan.hash)
+		}
+		if slot, ok := want[*orphan.account]; !ok || slot != orphan.slot {
+			t.Errorf("unexpected orphan %x at slot %x", *orphan.account, orphan.slot)
+		}
+	}
+}
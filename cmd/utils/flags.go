@@ -318,6 +318,17 @@ var (
 		Usage:    "Do not maintain log search index",
 		Category: flags.StateCategory,
 	}
+	FloorPriceIndexFlag = &cli.BoolFlag{
+		Name:     "history.floorprice.enable",
+		Usage:    "Maintain an index of the floor-price history of SmartDeFi tokens",
+		Category: flags.StateCategory,
+	}
+	FloorPriceHistoryFlag = &cli.Uint64Flag{
+		Name:     "history.floorprice",
+		Usage:    "Number of recent blocks to maintain the floor-price history of SmartDeFi tokens for (0 = entire chain)",
+		Value:    ethconfig.Defaults.FloorPriceHistory,
+		Category: flags.StateCategory,
+	}
 	LogExportCheckpointsFlag = &cli.StringFlag{
 		Name:     "history.logs.export",
 		Usage:    "Export checkpoints to file in go source file format",
@@ -1726,6 +1737,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(LogNoHistoryFlag.Name) {
 		cfg.LogNoHistory = ctx.Bool(LogNoHistoryFlag.Name)
 	}
+	if ctx.IsSet(FloorPriceIndexFlag.Name) {
+		cfg.FloorPriceIndex = ctx.Bool(FloorPriceIndexFlag.Name)
+	}
+	if ctx.IsSet(FloorPriceHistoryFlag.Name) {
+		cfg.FloorPriceHistory = ctx.Uint64(FloorPriceHistoryFlag.Name)
+	}
 	if ctx.IsSet(LogSlowBlockFlag.Name) {
 		cfg.SlowBlockThreshold = ctx.Duration(LogSlowBlockFlag.Name)
 	}
@@ -0,0 +1,35 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package misc
+
+import (
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ApplySmartDeFiHardFork modifies the state database according to the SmartDeFi
+// hard-fork rules, moving the backing pools and fees of the tokens created
+// before the storage namespaces to the namespaced layout, and their backing
+// from the legacy address of the precompile to the current one. Tokens already
+// migrated are left untouched.
+func ApplySmartDeFiHardFork(statedb vm.StateDB, config *params.ChainConfig) {
+	for _, token := range config.SmartDeFiLegacyTokens {
+		assetbacking.MigrateLegacyStorage(statedb, token)
+	}
+	assetbacking.MigrateLegacyBacking(statedb)
+}
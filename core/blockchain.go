@@ -196,6 +196,14 @@ type BlockChainConfig struct {
 	// If the value is -1, indexing is disabled.
 	TxLookupLimit int64
 
+	// FloorPriceIndex enables the index of the floor-price history of SmartDeFi
+	// tokens, and FloorPriceLimit specifies the maximum number of blocks from
+	// head for which it is retained.
+	//
+	// If the limit is zero, the history of the entire chain is retained.
+	FloorPriceIndex bool
+	FloorPriceLimit uint64
+
 	// StateSizeTracking indicates whether the state size tracking is enabled.
 	StateSizeTracking bool
 
@@ -302,6 +310,7 @@ type BlockChain struct {
 	triedb        *triedb.Database                 // The database handler for maintaining trie nodes.
 	statedb       *state.CachingDB                 // State database to reuse between imports (contains state cache)
 	txIndexer     *txIndexer                       // Transaction indexer, might be nil if not enabled
+	fpIndexer     *floorPriceIndexer               // Floor-price indexer, might be nil if not enabled
 
 	hc               *HeaderChain
 	rmLogsFeed       event.Feed
@@ -535,6 +544,14 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 	if bc.cfg.TxLookupLimit >= 0 {
 		bc.txIndexer = newTxIndexer(uint64(bc.cfg.TxLookupLimit), bc)
 	}
+	// Start floor-price indexer if it's enabled, otherwise drop the tail of a
+	// previous index, whose checkpoints are no longer maintained.
+	if bc.cfg.FloorPriceIndex {
+		bc.fpIndexer = newFloorPriceIndexer(bc.cfg.FloorPriceLimit, bc)
+	} else if rawdb.ReadFloorPriceIndexTail(bc.db) != nil {
+		rawdb.DeleteFloorPriceIndexTail(bc.db)
+		log.Info("Disabled floor-price index")
+	}
 
 	// Start state size tracker
 	if bc.cfg.StateSizeTracking {
@@ -1144,6 +1161,11 @@ func (bc *BlockChain) SnapSyncCommitHead(hash common.Hash) error {
 	if bc.snaps != nil {
 		bc.snaps.Rebuild(root)
 	}
+	// The blocks up to the new head were not processed, so there are no
+	// floor-price checkpoints for them.
+	if bc.fpIndexer != nil {
+		bc.fpIndexer.skipTo(block.NumberU64() + 1)
+	}
 	log.Info("Committed new head block", "number", block.Number(), "hash", hash)
 	return nil
 }
@@ -1266,6 +1288,9 @@ func (bc *BlockChain) stopWithoutSaving() {
 	if bc.txIndexer != nil {
 		bc.txIndexer.close()
 	}
+	if bc.fpIndexer != nil {
+		bc.fpIndexer.close()
+	}
 	// Unsubscribe all subscriptions registered from blockchain.
 	bc.scope.Close()
 
@@ -1606,6 +1631,9 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	rawdb.WriteBlock(blockBatch, block)
 	rawdb.WriteReceipts(blockBatch, block.Hash(), block.NumberU64(), receipts)
 	rawdb.WritePreimages(blockBatch, statedb.Preimages())
+	if bc.fpIndexer != nil {
+		rawdb.WriteFloorPriceCheckpoints(blockBatch, block.NumberU64(), block.Hash(), floorPriceCheckpoints(receipts, statedb))
+	}
 	if err := blockBatch.Write(); err != nil {
 		log.Crit("Failed to write block into disk", "err", err)
 	}
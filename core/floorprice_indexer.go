@@ -0,0 +1,236 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// floorPricePruneSection is the number of blocks whose floor-price checkpoints
+// are pruned at once, between checks for termination.
+const floorPricePruneSection = 100_000
+
+// floorPriceIndexer is the module responsible for maintaining the floor-price
+// checkpoints of SmartDeFi tokens according to the configured range.
+//
+// Checkpoints are written along with the blocks they belong to, as the state
+// they are taken from is only guaranteed to be available at that time. The
+// indexer sets the tail from which the checkpoints are complete and prunes
+// the checkpoints of blocks which fall out of the configured range.
+type floorPriceIndexer struct {
+	// limit is the maximum number of blocks from head whose checkpoints
+	// are retained:
+	//  * 0: means the checkpoints of the entire chain are retained
+	//  * N: means the checkpoints of the latest N blocks [HEAD-N+1, HEAD]
+	//       are retained and all others are pruned.
+	limit uint64
+
+	// cutoff denotes the block number before which the chain segment should
+	// be pruned and not available locally.
+	cutoff uint64
+	db     ethdb.Database
+	skip   chan uint64
+	term   chan chan struct{}
+	closed chan struct{}
+}
+
+// newFloorPriceIndexer initializes the floor-price indexer.
+func newFloorPriceIndexer(limit uint64, chain *BlockChain) *floorPriceIndexer {
+	cutoff, _ := chain.HistoryPruningCutoff()
+	indexer := &floorPriceIndexer{
+		limit:  limit,
+		cutoff: cutoff,
+		db:     chain.db,
+		skip:   make(chan uint64),
+		term:   make(chan chan struct{}),
+		closed: make(chan struct{}),
+	}
+	indexer.repair(chain.CurrentBlock().Number.Uint64())
+
+	go indexer.loop(chain)
+
+	var msg string
+	if limit == 0 {
+		if indexer.cutoff == 0 {
+			msg = "entire chain"
+		} else {
+			msg = fmt.Sprintf("blocks since #%d", indexer.cutoff)
+		}
+	} else {
+		msg = fmt.Sprintf("last %d blocks", limit)
+	}
+	log.Info("Initialized floor-price indexer", "range", msg, "tail", *rawdb.ReadFloorPriceIndexTail(indexer.db))
+
+	return indexer
+}
+
+// repair ensures that the index tail is set and consistent with the chain
+// head. Checkpoints are only written for blocks processed from now on, so a
+// missing tail is set above the head, and stale checkpoints left behind by a
+// previously disabled index are removed.
+func (indexer *floorPriceIndexer) repair(head uint64) {
+	tail := rawdb.ReadFloorPriceIndexTail(indexer.db)
+	if tail == nil {
+		from := uint64(0)
+		if head != 0 {
+			from = head + 1
+		}
+		rawdb.DeleteFloorPriceCheckpoints(indexer.db, 0, from)
+		rawdb.WriteFloorPriceIndexTail(indexer.db, max(from, indexer.cutoff))
+		return
+	}
+	// The tail is above the chain head, which may occur when the chain is
+	// rewound. The blocks above the head are processed again, so move the
+	// tail down.
+	if *tail > head+1 {
+		rawdb.WriteFloorPriceIndexTail(indexer.db, head+1)
+		log.Warn("Rewound floor-price index tail", "head", head, "tail", *tail)
+	}
+}
+
+// run prunes the checkpoints below the configured range in a separate thread.
+// If the stop channel is closed, the task should terminate as soon as possible.
+// The done channel will be closed once the task is complete.
+func (indexer *floorPriceIndexer) run(head uint64, stop chan struct{}, done chan struct{}) {
+	defer func() { close(done) }()
+
+	from := indexer.cutoff
+	if indexer.limit != 0 && head >= indexer.limit {
+		from = max(from, head-indexer.limit+1)
+	}
+	tail := rawdb.ReadFloorPriceIndexTail(indexer.db)
+	for *tail < from {
+		next := min(from, *tail+floorPricePruneSection)
+		rawdb.DeleteFloorPriceCheckpoints(indexer.db, *tail, next)
+		rawdb.WriteFloorPriceIndexTail(indexer.db, next)
+		*tail = next
+
+		select {
+		case <-stop:
+			return
+		default:
+		}
+	}
+}
+
+// loop is the scheduler of the indexer, assigning pruning tasks depending on
+// the received chain events.
+func (indexer *floorPriceIndexer) loop(chain *BlockChain) {
+	defer close(indexer.closed)
+
+	var (
+		stop   chan struct{} // Non-nil if background routine is active
+		done   chan struct{} // Non-nil if background routine is active
+		headCh = make(chan ChainHeadEvent)
+		sub    = chain.SubscribeChainHeadEvent(headCh)
+	)
+	defer sub.Unsubscribe()
+
+	// Prune the checkpoints the configured range may have shrunk by
+	if head := chain.CurrentBlock().Number.Uint64(); head != 0 {
+		stop = make(chan struct{})
+		done = make(chan struct{})
+		go indexer.run(head, stop, done)
+	}
+	for {
+		select {
+		case h := <-headCh:
+			if done == nil {
+				stop = make(chan struct{})
+				done = make(chan struct{})
+				go indexer.run(h.Header.Number.Uint64(), stop, done)
+			}
+
+		case <-done:
+			stop = nil
+			done = nil
+
+		case number := <-indexer.skip:
+			if stop != nil {
+				close(stop)
+				<-done
+				stop = nil
+				done = nil
+			}
+			if tail := rawdb.ReadFloorPriceIndexTail(indexer.db); *tail < number {
+				rawdb.DeleteFloorPriceCheckpoints(indexer.db, *tail, number)
+				rawdb.WriteFloorPriceIndexTail(indexer.db, number)
+			}
+
+		case ch := <-indexer.term:
+			if stop != nil {
+				close(stop)
+			}
+			if done != nil {
+				log.Info("Waiting background floor-price indexer to exit")
+				<-done
+			}
+			close(ch)
+			return
+		}
+	}
+}
+
+// skipTo moves the index tail up to the given block, as the blocks below it
+// were inserted without being processed.
+func (indexer *floorPriceIndexer) skipTo(number uint64) {
+	select {
+	case indexer.skip <- number:
+	case <-indexer.closed:
+	}
+}
+
+// close shutdown the indexer. Safe to be called for multiple times.
+func (indexer *floorPriceIndexer) close() {
+	ch := make(chan struct{})
+	select {
+	case indexer.term <- ch:
+		<-ch
+	case <-indexer.closed:
+	}
+}
+
+// floorPriceCheckpoints returns the checkpoints of the backing pools changed in
+// a block, taken from the state after the block.
+func floorPriceCheckpoints(receipts []*types.Receipt, statedb *state.StateDB) []rawdb.FloorPriceCheckpoint {
+	var logs []*types.Log
+	for _, receipt := range receipts {
+		logs = append(logs, receipt.Logs...)
+	}
+	var checkpoints []rawdb.FloorPriceCheckpoint
+	for _, token := range assetbacking.ChangedPools(logs) {
+		pool := backingpool.GetBackingPool(statedb, token)
+		if pool == nil {
+			continue
+		}
+		checkpoints = append(checkpoints, rawdb.FloorPriceCheckpoint{
+			Token:             token,
+			TotalBacking:      pool.TotalBacking,
+			CirculatingSupply: new(big.Int).Sub(pool.TotalSupply, pool.BurnedSupply),
+		})
+	}
+	return checkpoints
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -28,6 +29,7 @@ func (g Genesis) MarshalJSON() ([]byte, error) {
 		Mixhash       common.Hash                                `json:"mixHash"`
 		Coinbase      common.Address                             `json:"coinbase"`
 		Alloc         map[common.UnprefixedAddress]types.Account `json:"alloc"      gencodec:"required"`
+		SmartDeFi     []assetbacking.GenesisToken                `json:"smartDeFi,omitempty"`
 		Number        math.HexOrDecimal64                        `json:"number"`
 		GasUsed       math.HexOrDecimal64                        `json:"gasUsed"`
 		ParentHash    common.Hash                                `json:"parentHash"`
@@ -50,6 +52,7 @@ func (g Genesis) MarshalJSON() ([]byte, error) {
 			enc.Alloc[common.UnprefixedAddress(k)] = v
 		}
 	}
+	enc.SmartDeFi = g.SmartDeFi
 	enc.Number = math.HexOrDecimal64(g.Number)
 	enc.GasUsed = math.HexOrDecimal64(g.GasUsed)
 	enc.ParentHash = g.ParentHash
@@ -71,6 +74,7 @@ func (g *Genesis) UnmarshalJSON(input []byte) error {
 		Mixhash       *common.Hash                               `json:"mixHash"`
 		Coinbase      *common.Address                            `json:"coinbase"`
 		Alloc         map[common.UnprefixedAddress]types.Account `json:"alloc"      gencodec:"required"`
+		SmartDeFi     []assetbacking.GenesisToken                `json:"smartDeFi,omitempty"`
 		Number        *math.HexOrDecimal64                       `json:"number"`
 		GasUsed       *math.HexOrDecimal64                       `json:"gasUsed"`
 		ParentHash    *common.Hash                               `json:"parentHash"`
@@ -115,6 +119,9 @@ func (g *Genesis) UnmarshalJSON(input []byte) error {
 	for k, v := range dec.Alloc {
 		g.Alloc[common.Address(k)] = v
 	}
+	if dec.SmartDeFi != nil {
+		g.SmartDeFi = dec.SmartDeFi
+	}
 	if dec.Number != nil {
 		g.Number = uint64(*dec.Number)
 	}
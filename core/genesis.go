@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
@@ -65,6 +66,10 @@ type Genesis struct {
 	Coinbase   common.Address      `json:"coinbase"`
 	Alloc      types.GenesisAlloc  `json:"alloc"      gencodec:"required"`
 
+	// SmartDeFi lists the SmartDeFi tokens created in the genesis state, next
+	// to the accounts of the alloc.
+	SmartDeFi []assetbacking.GenesisToken `json:"smartDeFi,omitempty"`
+
 	// These fields are used for consensus tests. Please don't use them
 	// in actual genesis blocks.
 	Number        uint64      `json:"number"`
@@ -459,7 +464,11 @@ func (g *Genesis) IsVerkle() bool {
 
 // ToBlock returns the genesis block according to genesis specification.
 func (g *Genesis) ToBlock() *types.Block {
-	root, err := hashAlloc(&g.Alloc, g.IsVerkle())
+	alloc, err := g.stateAlloc()
+	if err != nil {
+		panic(err)
+	}
+	root, err := hashAlloc(&alloc, g.IsVerkle())
 	if err != nil {
 		panic(err)
 	}
@@ -551,15 +560,21 @@ func (g *Genesis) Commit(db ethdb.Database, triedb *triedb.Database) (*types.Blo
 	if config.Clique != nil && len(g.ExtraData) < 32+crypto.SignatureLength {
 		return nil, errors.New("can't start clique chain without signers")
 	}
+	// The persisted state specification includes the SmartDeFi tokens, so the
+	// genesis state can be recovered from the alloc alone.
+	alloc, err := g.stateAlloc()
+	if err != nil {
+		return nil, err
+	}
 	// flush the data to disk and compute the state root
-	root, err := flushAlloc(&g.Alloc, triedb)
+	root, err := flushAlloc(&alloc, triedb)
 	if err != nil {
 		return nil, err
 	}
 	block := g.toBlockWithRoot(root)
 
 	// Marshal the genesis state specification and persist.
-	blob, err := json.Marshal(g.Alloc)
+	blob, err := json.Marshal(alloc)
 	if err != nil {
 		return nil, err
 	}
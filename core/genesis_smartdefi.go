@@ -0,0 +1,179 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
+	"github.com/holiman/uint256"
+)
+
+// errSmartDeFiNotScheduled is returned if the genesis specification allocates
+// SmartDeFi tokens on a chain which never activates the SmartDeFi precompile.
+var errSmartDeFiNotScheduled = errors.New("smartDeFi tokens require the smartDeFiTime fork to be scheduled")
+
+// ValidateSmartDeFi checks the smartDeFi section of the genesis specification.
+// Besides the tokens themselves, it ensures that the section can be expanded
+// into the genesis state without conflicting with the alloc.
+func (g *Genesis) ValidateSmartDeFi() error {
+	if len(g.SmartDeFi) == 0 {
+		return nil
+	}
+	if g.Config == nil {
+		return errGenesisNoConfig
+	}
+	_, err := g.stateAlloc()
+	return err
+}
+
+// stateAlloc returns the accounts of the genesis state: the alloc, extended by
+// the tokens of the smartDeFi section, their backing pools, the token registry
+// and the backing locked in the precompile. Storage the alloc seeds in the
+// precompile is kept if it does not collide with the section. The alloc is not
+// modified.
+func (g *Genesis) stateAlloc() (types.GenesisAlloc, error) {
+	if len(g.SmartDeFi) == 0 {
+		return g.Alloc, nil
+	}
+	if g.Config.SmartDeFiTime == nil {
+		return nil, errSmartDeFiNotScheduled
+	}
+	// The alloc may only seed storage of the precompile, such as the router
+	// admin. Its balance is the backing locked by the tokens and its nonce the
+	// number of tokens created, both of which the section defines.
+	precompile := assetbacking.PrecompileAddressBytes
+	seeded, ok := g.Alloc[precompile]
+	if ok && ((seeded.Balance != nil && seeded.Balance.Sign() != 0) || seeded.Nonce != 0 || len(seeded.Code) != 0) {
+		return nil, fmt.Errorf("alloc of the SmartDeFi precompile %s conflicts with the smartDeFi section", precompile.Hex())
+	}
+	state := &genesisAllocState{alloc: make(types.GenesisAlloc, len(g.Alloc))}
+	for addr, account := range g.Alloc {
+		if addr == precompile {
+			continue
+		}
+		account.Storage = maps.Clone(account.Storage)
+		state.alloc[addr] = account
+	}
+	tokens, err := assetbacking.ApplyGenesisTokens(state, g.SmartDeFi)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		if _, ok := g.Alloc[token]; ok {
+			return nil, fmt.Errorf("alloc of %s conflicts with the smartDeFi token at that address", token.Hex())
+		}
+	}
+	// Merge the seeded storage, which must not overlap the registry and pools
+	for key, value := range seeded.Storage {
+		if _, ok := state.alloc[precompile].Storage[key]; ok {
+			return nil, fmt.Errorf("alloc of the SmartDeFi precompile slot %s conflicts with the smartDeFi section", key.Hex())
+		}
+		state.SetState(precompile, key, value)
+	}
+	return state.alloc, nil
+}
+
+// genesisAllocState is the state the smartDeFi genesis section is expanded in,
+// recording all changes in a genesis alloc. Logs are dropped, as the genesis
+// block has no receipts.
+type genesisAllocState struct {
+	alloc types.GenesisAlloc
+}
+
+// update applies fn to the account at addr, creating the account if missing.
+// Accounts always get a balance, which the alloc requires.
+func (s *genesisAllocState) update(addr common.Address, fn func(account *types.Account)) {
+	account := s.alloc[addr]
+	if account.Balance == nil {
+		account.Balance = new(big.Int)
+	}
+	fn(&account)
+	s.alloc[addr] = account
+}
+
+func (s *genesisAllocState) GetState(addr common.Address, key common.Hash) common.Hash {
+	return s.alloc[addr].Storage[key]
+}
+
+func (s *genesisAllocState) SetState(addr common.Address, key common.Hash, value common.Hash) common.Hash {
+	prev := s.GetState(addr, key)
+	s.update(addr, func(account *types.Account) {
+		if account.Storage == nil {
+			account.Storage = make(map[common.Hash]common.Hash)
+		}
+		if value == (common.Hash{}) {
+			delete(account.Storage, key)
+		} else {
+			account.Storage[key] = value
+		}
+	})
+	return prev
+}
+
+func (s *genesisAllocState) GetBalance(addr common.Address) *uint256.Int {
+	if balance := s.alloc[addr].Balance; balance != nil {
+		return uint256.MustFromBig(balance)
+	}
+	return new(uint256.Int)
+}
+
+func (s *genesisAllocState) AddBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
+	prev := s.GetBalance(addr)
+	s.update(addr, func(account *types.Account) {
+		account.Balance = new(uint256.Int).Add(prev, amount).ToBig()
+	})
+	return *prev
+}
+
+func (s *genesisAllocState) SubBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
+	prev := s.GetBalance(addr)
+	s.update(addr, func(account *types.Account) {
+		account.Balance = new(uint256.Int).Sub(prev, amount).ToBig()
+	})
+	return *prev
+}
+
+func (s *genesisAllocState) GetCode(addr common.Address) []byte {
+	return s.alloc[addr].Code
+}
+
+func (s *genesisAllocState) GetCodeSize(addr common.Address) int {
+	return len(s.alloc[addr].Code)
+}
+
+func (s *genesisAllocState) SetCode(addr common.Address, code []byte, reason tracing.CodeChangeReason) []byte {
+	prev := s.GetCode(addr)
+	s.update(addr, func(account *types.Account) { account.Code = code })
+	return prev
+}
+
+func (s *genesisAllocState) GetNonce(addr common.Address) uint64 {
+	return s.alloc[addr].Nonce
+}
+
+func (s *genesisAllocState) SetNonce(addr common.Address, nonce uint64, reason tracing.NonceChangeReason) {
+	s.update(addr, func(account *types.Account) { account.Nonce = nonce })
+}
+
+func (s *genesisAllocState) AddLog(*types.Log) {}
@@ -0,0 +1,214 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+// Tests that the tokens of the smartDeFi genesis section are created in the
+// genesis state and persisted with the state specification.
+func TestGenesisSmartDeFi(t *testing.T) {
+	var (
+		owner   = common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+		funded  = common.HexToAddress("0xfeed")
+		config  = *params.MergedTestChainConfig
+		genesis Genesis
+	)
+	config.SmartDeFiTime = new(uint64)
+	config.OsakaTime = nil
+
+	spec := `{
+		"gasLimit": "0x1c9c380",
+		"difficulty": "0x0",
+		"alloc": {"000000000000000000000000000000000000feed": {"balance": "0x64"}},
+		"smartDeFi": [
+			{"name": "Backed", "symbol": "BKD", "owner": "0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e", "supply": "1000000", "backing": "1000",
+			 "fees": [10, 20, 30, 0, 0, 0, 40, 50, 60, 0, 0, 0]},
+			{"name": "Unbacked", "symbol": "UBK", "owner": "0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e", "supply": "500"}
+		]
+	}`
+	if err := json.Unmarshal([]byte(spec), &genesis); err != nil {
+		t.Fatalf("failed to decode genesis: %v", err)
+	}
+	genesis.Config = &config
+	if err := genesis.ValidateSmartDeFi(); err != nil {
+		t.Fatalf("invalid smartDeFi section: %v", err)
+	}
+	if len(genesis.Alloc) != 1 {
+		t.Fatalf("genesis alloc modified: have %d accounts, want 1", len(genesis.Alloc))
+	}
+
+	db := rawdb.NewMemoryDatabase()
+	tdb := triedb.NewDatabase(db, triedb.HashDefaults)
+	block := genesis.MustCommit(db, tdb)
+	if hash := genesis.ToBlock().Hash(); hash != block.Hash() {
+		t.Fatalf("genesis hash mismatch: have %x, want %x", hash, block.Hash())
+	}
+	statedb, err := state.New(block.Root(), state.NewDatabase(tdb, nil))
+	if err != nil {
+		t.Fatalf("failed to open genesis state: %v", err)
+	}
+
+	// The tokens are created as if their owner had created them in order
+	var tokens []common.Address
+	for i, backing := range []int64{1000, 0} {
+		token := assetbacking.TokenAt(statedb, uint64(i))
+		if !assetbacking.IsToken(statedb.GetCode(token)) {
+			t.Fatalf("token %d: missing code at %x", i, token)
+		}
+		pool := backingpool.GetBackingPool(statedb, token)
+		if pool == nil || pool.TotalBacking.Cmp(big.NewInt(backing)) != 0 {
+			t.Errorf("token %d: backing mismatch: have %+v, want %d", i, pool, backing)
+		}
+		if owned := assetbacking.TokenOwner(statedb, token); owned != owner {
+			t.Errorf("token %d: owner mismatch: have %x, want %x", i, owned, owner)
+		}
+		tokens = append(tokens, token)
+	}
+	if balance := assetbacking.TokenBalance(statedb, tokens[0], owner); balance.Cmp(big.NewInt(1_000_000)) != 0 {
+		t.Errorf("owner balance mismatch: have %d, want 1000000", balance)
+	}
+	precompile := assetbacking.PrecompileAddressBytes
+	if balance := statedb.GetBalance(precompile); balance.Uint64() != 1000 {
+		t.Errorf("locked backing mismatch: have %d, want 1000", balance)
+	}
+	if nonce := statedb.GetNonce(precompile); nonce != 1 {
+		t.Errorf("precompile nonce mismatch: have %d, want 1", nonce)
+	}
+	if balance := statedb.GetBalance(funded); balance.Uint64() != 100 {
+		t.Errorf("alloc balance mismatch: have %d, want 100", balance)
+	}
+
+	// The persisted state specification holds the expanded state
+	alloc, err := getGenesisState(db, block.Hash())
+	if err != nil {
+		t.Fatalf("failed to read genesis state: %v", err)
+	}
+	if root, _ := hashAlloc(&alloc, false); root != block.Root() {
+		t.Errorf("persisted genesis state root mismatch: have %x, want %x", root, block.Root())
+	}
+}
+
+// Tests that invalid smartDeFi genesis sections are rejected.
+func TestGenesisSmartDeFiInvalid(t *testing.T) {
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	token := assetbacking.GenesisToken{Name: "Token", Symbol: "TKN", Owner: owner, Supply: big.NewInt(1000)}
+
+	var fees [12]*big.Int
+	for i := range fees {
+		fees[i] = new(big.Int)
+	}
+	address, err := assetbacking.TokenAddress(owner, assetbacking.DefaultTokenSalt(0), assetbacking.TokenConfig{
+		Name:           token.Name,
+		Symbol:         token.Symbol,
+		TotalSupply:    token.Supply,
+		InitialBacking: new(big.Int),
+		Fees:           fees,
+		Owner:          owner,
+	})
+	if err != nil {
+		t.Fatalf("failed to derive token address: %v", err)
+	}
+	newGenesis := func(alloc types.GenesisAlloc, tokens ...assetbacking.GenesisToken) *Genesis {
+		config := *params.MergedTestChainConfig
+		config.SmartDeFiTime = new(uint64)
+		config.OsakaTime = nil
+		return &Genesis{Config: &config, Alloc: alloc, SmartDeFi: tokens}
+	}
+
+	unscheduled := newGenesis(nil, token)
+	unscheduled.Config.SmartDeFiTime = nil
+	if err := unscheduled.ValidateSmartDeFi(); !errors.Is(err, errSmartDeFiNotScheduled) {
+		t.Errorf("unscheduled fork: have %v, want %v", err, errSmartDeFiNotScheduled)
+	}
+	for name, genesis := range map[string]*Genesis{
+		"precompile alloc": newGenesis(types.GenesisAlloc{assetbacking.PrecompileAddressBytes: {Balance: big.NewInt(1)}}, token),
+		"precompile slot": newGenesis(types.GenesisAlloc{assetbacking.PrecompileAddressBytes: {
+			Balance: new(big.Int),
+			Storage: map[common.Hash]common.Hash{assetbacking.TokenCountSlot(): common.BigToHash(big.NewInt(5))},
+		}}, token),
+		"token alloc":    newGenesis(types.GenesisAlloc{address: {Balance: big.NewInt(1)}}, token),
+		"missing supply": newGenesis(nil, assetbacking.GenesisToken{Owner: owner}),
+	} {
+		if err := genesis.ValidateSmartDeFi(); err == nil {
+			t.Errorf("%s: invalid section accepted", name)
+		}
+	}
+	if err := newGenesis(nil, token).ValidateSmartDeFi(); err != nil {
+		t.Errorf("valid section rejected: %v", err)
+	}
+}
+
+// Tests that the alloc can seed the storage of the precompile, such as the
+// router admin, next to the tokens of the smartDeFi section.
+func TestGenesisSmartDeFiRouterAdmin(t *testing.T) {
+	var (
+		owner  = common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+		admin  = common.HexToAddress("0xad")
+		config = *params.MergedTestChainConfig
+	)
+	config.SmartDeFiTime = new(uint64)
+	config.OsakaTime = nil
+
+	genesis := &Genesis{
+		Config: &config,
+		Alloc: types.GenesisAlloc{
+			assetbacking.PrecompileAddressBytes: {
+				Balance: new(big.Int),
+				Storage: map[common.Hash]common.Hash{assetbacking.RouterAdminSlot(): common.BytesToHash(admin.Bytes())},
+			},
+		},
+		SmartDeFi: []assetbacking.GenesisToken{
+			{Name: "Backed", Symbol: "BKD", Owner: owner, Supply: big.NewInt(1000), Backing: big.NewInt(100)},
+		},
+	}
+	if err := genesis.ValidateSmartDeFi(); err != nil {
+		t.Fatalf("invalid smartDeFi section: %v", err)
+	}
+	db := rawdb.NewMemoryDatabase()
+	tdb := triedb.NewDatabase(db, triedb.HashDefaults)
+	block := genesis.MustCommit(db, tdb)
+	statedb, err := state.New(block.Root(), state.NewDatabase(tdb, nil))
+	if err != nil {
+		t.Fatalf("failed to open genesis state: %v", err)
+	}
+	if have := assetbacking.RouterAdmin(statedb); have != admin {
+		t.Errorf("router admin mismatch: have %x, want %x", have, admin)
+	}
+	if count := assetbacking.TokenCount(statedb); count != 1 {
+		t.Errorf("token count mismatch: have %d, want 1", count)
+	}
+	if balance := statedb.GetBalance(assetbacking.PrecompileAddressBytes); balance.Uint64() != 100 {
+		t.Errorf("locked backing mismatch: have %d, want 100", balance)
+	}
+	if len(genesis.Alloc[assetbacking.PrecompileAddressBytes].Storage) != 1 {
+		t.Errorf("genesis alloc modified")
+	}
+}
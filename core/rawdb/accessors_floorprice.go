@@ -0,0 +1,180 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// FloorPriceCheckpoint is the backing pool of a SmartDeFi token at the end of
+// a block in which the pool changed.
+type FloorPriceCheckpoint struct {
+	Token             common.Address
+	Number            uint64
+	Hash              common.Hash
+	TotalBacking      *big.Int
+	CirculatingSupply *big.Int
+}
+
+// storedFloorPriceCheckpoint is the database encoding of a checkpoint, whose
+// token and block are part of the key.
+type storedFloorPriceCheckpoint struct {
+	TotalBacking      *big.Int
+	CirculatingSupply *big.Int
+}
+
+// ReadFloorPriceIndexTail retrieves the number of the oldest block from which
+// the floor-price checkpoints are complete, nil if the index is not enabled.
+func ReadFloorPriceIndexTail(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(floorPriceIndexTailKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteFloorPriceIndexTail stores the number of the oldest block from which the
+// floor-price checkpoints are complete into database.
+func WriteFloorPriceIndexTail(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(floorPriceIndexTailKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store the floor-price index tail", "err", err)
+	}
+}
+
+// DeleteFloorPriceIndexTail deletes the number of the oldest block from which
+// the floor-price checkpoints are complete from database.
+func DeleteFloorPriceIndexTail(db ethdb.KeyValueWriter) {
+	if err := db.Delete(floorPriceIndexTailKey); err != nil {
+		log.Crit("Failed to delete the floor-price index tail", "err", err)
+	}
+}
+
+// WriteFloorPriceCheckpoints stores the checkpoints of the pools changed in the
+// given block. The block of the checkpoints is taken from the arguments.
+func WriteFloorPriceCheckpoints(db ethdb.KeyValueWriter, number uint64, hash common.Hash, checkpoints []FloorPriceCheckpoint) {
+	if len(checkpoints) == 0 {
+		return
+	}
+	tokens := make([]common.Address, len(checkpoints))
+	for i, checkpoint := range checkpoints {
+		data, err := rlp.EncodeToBytes(&storedFloorPriceCheckpoint{
+			TotalBacking:      checkpoint.TotalBacking,
+			CirculatingSupply: checkpoint.CirculatingSupply,
+		})
+		if err != nil {
+			log.Crit("Failed to encode floor-price checkpoint", "err", err)
+		}
+		if err := db.Put(floorPriceCheckpointKey(checkpoint.Token, number, hash), data); err != nil {
+			log.Crit("Failed to store floor-price checkpoint", "err", err)
+		}
+		tokens[i] = checkpoint.Token
+	}
+	data, err := rlp.EncodeToBytes(tokens)
+	if err != nil {
+		log.Crit("Failed to encode floor-price block entry", "err", err)
+	}
+	if err := db.Put(floorPriceBlockKey(number, hash), data); err != nil {
+		log.Crit("Failed to store floor-price block entry", "err", err)
+	}
+}
+
+// ReadFloorPriceCheckpoints retrieves the checkpoints of a token in canonical
+// blocks between from and to (both inclusive), in ascending block order. At
+// most limit checkpoints are returned if limit is positive.
+func ReadFloorPriceCheckpoints(db ethdb.Database, token common.Address, from, to uint64, limit int) []FloorPriceCheckpoint {
+	prefix := append(floorPriceCheckpointPrefix, token.Bytes()...)
+	it := NewKeyLengthIterator(db.NewIterator(prefix, encodeBlockNumber(from)), len(prefix)+8+common.HashLength)
+	defer it.Release()
+
+	var checkpoints []FloorPriceCheckpoint
+	for it.Next() {
+		var (
+			key    = it.Key()
+			number = binary.BigEndian.Uint64(key[len(prefix):])
+			hash   = common.BytesToHash(key[len(prefix)+8:])
+		)
+		if number > to {
+			break
+		}
+		if ReadCanonicalHash(db, number) != hash {
+			continue
+		}
+		var stored storedFloorPriceCheckpoint
+		if err := rlp.DecodeBytes(it.Value(), &stored); err != nil {
+			log.Error("Invalid floor-price checkpoint RLP", "token", token, "number", number, "hash", hash, "err", err)
+			continue
+		}
+		checkpoints = append(checkpoints, FloorPriceCheckpoint{
+			Token:             token,
+			Number:            number,
+			Hash:              hash,
+			TotalBacking:      stored.TotalBacking,
+			CirculatingSupply: stored.CirculatingSupply,
+		})
+		if limit > 0 && len(checkpoints) == limit {
+			break
+		}
+	}
+	return checkpoints
+}
+
+// DeleteFloorPriceCheckpoints deletes the checkpoints of all blocks between from
+// (inclusive) and to (exclusive), canonical or not.
+func DeleteFloorPriceCheckpoints(db ethdb.KeyValueStore, from, to uint64) {
+	if from >= to {
+		return
+	}
+	it := NewKeyLengthIterator(db.NewIterator(floorPriceBlockPrefix, encodeBlockNumber(from)), len(floorPriceBlockPrefix)+8+common.HashLength)
+	defer it.Release()
+
+	batch := db.NewBatch()
+	for it.Next() {
+		var (
+			key    = it.Key()
+			number = binary.BigEndian.Uint64(key[len(floorPriceBlockPrefix):])
+			hash   = common.BytesToHash(key[len(floorPriceBlockPrefix)+8:])
+		)
+		if number >= to {
+			break
+		}
+		var tokens []common.Address
+		if err := rlp.DecodeBytes(it.Value(), &tokens); err != nil {
+			log.Error("Invalid floor-price block entry RLP", "number", number, "hash", hash, "err", err)
+		}
+		for _, token := range tokens {
+			batch.Delete(floorPriceCheckpointKey(token, number, hash))
+		}
+		batch.Delete(key)
+
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed to delete floor-price checkpoints", "err", err)
+			}
+			batch.Reset()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to delete floor-price checkpoints", "err", err)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tests floor-price checkpoint storage, retrieval of the canonical ones and
+// pruning by block range.
+func TestFloorPriceCheckpoints(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	var (
+		tokenA = common.Address{0xa}
+		tokenB = common.Address{0xb}
+	)
+	checkpoint := func(token common.Address, backing int64) FloorPriceCheckpoint {
+		return FloorPriceCheckpoint{Token: token, TotalBacking: big.NewInt(backing), CirculatingSupply: big.NewInt(1000)}
+	}
+	// Blocks 1 to 5 are canonical, a side block competes with block 3
+	canonical := make(map[uint64]common.Hash)
+	for number := uint64(1); number <= 5; number++ {
+		hash := common.Hash{byte(number)}
+		canonical[number] = hash
+		WriteCanonicalHash(db, hash, number)
+		WriteFloorPriceCheckpoints(db, number, hash, []FloorPriceCheckpoint{checkpoint(tokenA, int64(number)), checkpoint(tokenB, 10*int64(number))})
+	}
+	WriteFloorPriceCheckpoints(db, 3, common.Hash{0xff}, []FloorPriceCheckpoint{checkpoint(tokenA, 300)})
+
+	check := func(token common.Address, from, to uint64, limit int, want ...uint64) {
+		t.Helper()
+		have := ReadFloorPriceCheckpoints(db, token, from, to, limit)
+		if len(have) != len(want) {
+			t.Fatalf("checkpoints of %x in [%d, %d]: have %d, want %d", token, from, to, len(have), len(want))
+		}
+		for i, number := range want {
+			backing := int64(number)
+			if token == tokenB {
+				backing *= 10
+			}
+			if have[i].Token != token || have[i].Number != number || have[i].Hash != canonical[number] ||
+				have[i].TotalBacking.Int64() != backing || have[i].CirculatingSupply.Int64() != 1000 {
+				t.Errorf("checkpoint %d of %x: have %+v, want block %d", i, token, have[i], number)
+			}
+		}
+	}
+	check(tokenA, 0, 10, 0, 1, 2, 3, 4, 5)
+	check(tokenB, 2, 4, 0, 2, 3, 4)
+	check(tokenA, 2, 10, 2, 2, 3)
+	check(common.Address{0xc}, 0, 10, 0)
+
+	// Pruning removes the checkpoints of all blocks in range, including side blocks
+	DeleteFloorPriceCheckpoints(db, 0, 4)
+	check(tokenA, 0, 10, 0, 4, 5)
+	check(tokenB, 0, 10, 0, 4, 5)
+	if has, _ := db.Has(floorPriceCheckpointKey(tokenA, 3, common.Hash{0xff})); has {
+		t.Error("side block checkpoint not pruned")
+	}
+
+	if tail := ReadFloorPriceIndexTail(db); tail != nil {
+		t.Fatalf("unexpected index tail %d", *tail)
+	}
+	WriteFloorPriceIndexTail(db, 4)
+	if tail := ReadFloorPriceIndexTail(db); tail == nil || *tail != 4 {
+		t.Fatalf("index tail mismatch: have %v, want 4", tail)
+	}
+	DeleteFloorPriceIndexTail(db)
+	if tail := ReadFloorPriceIndexTail(db); tail != nil {
+		t.Fatalf("index tail not deleted: %d", *tail)
+	}
+}
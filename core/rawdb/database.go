@@ -427,6 +427,7 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		filterMapRows      stat
 		filterMapLastBlock stat
 		filterMapBlockLV   stat
+		floorPrices        stat
 
 		// Path-mode archive data
 		stateIndex stat
@@ -517,6 +518,12 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			case bytes.HasPrefix(key, filterMapBlockLVPrefix) && len(key) == len(filterMapBlockLVPrefix)+8:
 				filterMapBlockLV.add(size)
 
+			// SmartDeFi floor-price index
+			case bytes.HasPrefix(key, floorPriceCheckpointPrefix) && len(key) == len(floorPriceCheckpointPrefix)+common.AddressLength+8+common.HashLength:
+				floorPrices.add(size)
+			case bytes.HasPrefix(key, floorPriceBlockPrefix) && len(key) == len(floorPriceBlockPrefix)+8+common.HashLength:
+				floorPrices.add(size)
+
 			// old log index (deprecated)
 			case bytes.HasPrefix(key, bloomBitsPrefix) && len(key) == (len(bloomBitsPrefix)+10+common.HashLength):
 				bloomBits.add(size)
@@ -616,6 +623,7 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		{"Key-Value store", "Log index filter-map rows", filterMapRows.sizeString(), filterMapRows.countString()},
 		{"Key-Value store", "Log index last-block-of-map", filterMapLastBlock.sizeString(), filterMapLastBlock.countString()},
 		{"Key-Value store", "Log index block-lv", filterMapBlockLV.sizeString(), filterMapBlockLV.countString()},
+		{"Key-Value store", "SmartDeFi floor-price index", floorPrices.sizeString(), floorPrices.countString()},
 		{"Key-Value store", "Log bloombits (deprecated)", bloomBits.sizeString(), bloomBits.countString()},
 		{"Key-Value store", "Contract codes", codes.sizeString(), codes.countString()},
 		{"Key-Value store", "Hash trie nodes", legacyTries.sizeString(), legacyTries.countString()},
@@ -669,7 +677,7 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 var knownMetadataKeys = [][]byte{
 	databaseVersionKey, headHeaderKey, headBlockKey, headFastBlockKey, headFinalizedBlockKey,
 	lastPivotKey, fastTrieProgressKey, snapshotDisabledKey, SnapshotRootKey, snapshotJournalKey,
-	snapshotGeneratorKey, snapshotRecoveryKey, txIndexTailKey, fastTxLookupLimitKey,
+	snapshotGeneratorKey, snapshotRecoveryKey, txIndexTailKey, floorPriceIndexTailKey, fastTxLookupLimitKey,
 	uncleanShutdownKey, badBlockKey, transitionStatusKey, skeletonSyncStatusKey,
 	persistentStateIDKey, trieJournalKey, snapshotSyncStatusKey, snapSyncStatusFlagKey,
 	filterMapsRangeKey, headStateHistoryIndexKey, VerkleTransitionStatePrefix,
@@ -87,6 +87,10 @@ var (
 	// txIndexTailKey tracks the oldest block whose transactions have been indexed.
 	txIndexTailKey = []byte("TransactionIndexTail")
 
+	// floorPriceIndexTailKey tracks the oldest block from which the floor-price
+	// checkpoints of SmartDeFi tokens are complete.
+	floorPriceIndexTailKey = []byte("FloorPriceIndexTail")
+
 	// fastTxLookupLimitKey tracks the transaction lookup limit during fast sync.
 	// This flag is deprecated, it's kept to avoid reporting errors when inspect
 	// database.
@@ -158,6 +162,11 @@ var (
 	filterMapLastBlockPrefix = []byte(filterMapsPrefix + "b") // filterMapLastBlockPrefix + mapIndex (uint32 big endian) -> block number (uint64 big endian)
 	filterMapBlockLVPrefix   = []byte(filterMapsPrefix + "p") // filterMapBlockLVPrefix + num (uint64 big endian) -> log value pointer (uint64 big endian)
 
+	// SmartDeFi floor-price index
+	floorPricePrefix           = "fp-"
+	floorPriceCheckpointPrefix = []byte(floorPricePrefix + "c") // floorPriceCheckpointPrefix + token + num (uint64 big endian) + hash -> floor-price checkpoint
+	floorPriceBlockPrefix      = []byte(floorPricePrefix + "b") // floorPriceBlockPrefix + num (uint64 big endian) + hash -> tokens with a checkpoint
+
 	// old log index
 	bloomBitsMetaPrefix = []byte("iB")
 
@@ -383,6 +392,16 @@ func filterMapBlockLVKey(number uint64) []byte {
 	return key
 }
 
+// floorPriceCheckpointKey = floorPriceCheckpointPrefix + token + num (uint64 big endian) + hash
+func floorPriceCheckpointKey(token common.Address, number uint64, hash common.Hash) []byte {
+	return append(append(append(floorPriceCheckpointPrefix, token.Bytes()...), encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// floorPriceBlockKey = floorPriceBlockPrefix + num (uint64 big endian) + hash
+func floorPriceBlockKey(number uint64, hash common.Hash) []byte {
+	return append(append(floorPriceBlockPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
 // accountHistoryIndexKey = StateHistoryAccountMetadataPrefix + addressHash
 func accountHistoryIndexKey(addressHash common.Hash) []byte {
 	return append(StateHistoryAccountMetadataPrefix, addressHash.Bytes()...)
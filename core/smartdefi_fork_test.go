@@ -0,0 +1,182 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TestSmartDeFiLegacyMigration checks that the storage of the legacy tokens
+// listed in the chain config is migrated in the first block of the SmartDeFi
+// fork, both when generating and when importing the chain.
+func TestSmartDeFiLegacyMigration(t *testing.T) {
+	var (
+		config   = *params.MergedTestChainConfig
+		token    = common.HexToAddress("0x7e7e")
+		supply   = big.NewInt(1_000_000)
+		forkTime = uint64(20) // Block 2
+	)
+	config.OsakaTime = nil
+	config.SmartDeFiTime = &forkTime
+	config.SmartDeFiLegacyTokens = []common.Address{token}
+
+	legacySupply := backingpool.LegacySlot(token, "SmartDeFi-BackingPool", backingpool.SlotTotalSupply)
+	gspec := &Genesis{
+		Config:  &config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Alloc: types.GenesisAlloc{
+			token: {Balance: new(big.Int), Storage: map[common.Hash]common.Hash{legacySupply: common.BigToHash(supply)}},
+			assetbacking.LegacyPrecompileAddressBytes: {Balance: big.NewInt(1000)},
+		},
+	}
+	engine := beacon.New(ethash.NewFaker())
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 3, nil)
+
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), gspec, engine, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+	for i, block := range blocks {
+		statedb, err := chain.StateAt(block.Root())
+		if err != nil {
+			t.Fatalf("block %d: failed to open state: %v", block.NumberU64(), err)
+		}
+		migrated := i >= 1
+		if have := statedb.GetState(token, legacySupply) == (common.Hash{}); have != migrated {
+			t.Errorf("block %d: legacy slot cleared: have %v, want %v", block.NumberU64(), have, migrated)
+		}
+		if have := statedb.GetState(token, backingpool.PoolSlot(backingpool.SlotTotalSupply)).Big(); (have.Cmp(supply) == 0) != migrated {
+			t.Errorf("block %d: namespaced supply %v, migrated %v", block.NumberU64(), have, migrated)
+		}
+		if pool := backingpool.GetBackingPool(statedb, token); pool == nil || pool.TotalSupply.Cmp(supply) != 0 {
+			t.Errorf("block %d: pool unreadable: %v", block.NumberU64(), pool)
+		}
+		// The backing locked at the legacy address moves to the precompile
+		locked := statedb.GetBalance(assetbacking.PrecompileAddressBytes).Uint64()
+		if have := locked == 1000; have != migrated {
+			t.Errorf("block %d: locked backing %d, migrated %v", block.NumberU64(), locked, migrated)
+		}
+		if legacy := statedb.GetBalance(assetbacking.LegacyPrecompileAddressBytes).Uint64(); legacy+locked != 1000 {
+			t.Errorf("block %d: legacy balance %d, want %d", block.NumberU64(), legacy, 1000-locked)
+		}
+	}
+}
+
+// TestSmartDeFiAcrossOsaka checks that the asset-backing precompile stays active
+// across the Osaka fork, next to P256VERIFY, and keeps its tokens.
+func TestSmartDeFiAcrossOsaka(t *testing.T) {
+	var (
+		config    = *params.MergedTestChainConfig
+		key, _    = crypto.GenerateKey()
+		sender    = crypto.PubkeyToAddress(key.PublicKey)
+		osakaTime = uint64(20) // Block 2
+		backing   = big.NewInt(1000)
+	)
+	config.OsakaTime = &osakaTime
+	config.SmartDeFiTime = new(uint64)
+
+	gspec := &Genesis{
+		Config:  &config,
+		BaseFee: big.NewInt(params.InitialBaseFee),
+		Alloc:   types.GenesisAlloc{sender: {Balance: big.NewInt(params.Ether)}},
+	}
+	fees := [12]*big.Int{}
+	for i := range fees {
+		fees[i] = new(big.Int)
+	}
+	input, err := assetbacking.EncodeCreateToken(assetbacking.TokenConfig{
+		Name:           "Backed",
+		Symbol:         "BKD",
+		TotalSupply:    big.NewInt(1_000_000),
+		InitialBacking: backing,
+		Fees:           fees,
+		Owner:          sender,
+	})
+	if err != nil {
+		t.Fatalf("failed to encode token creation: %v", err)
+	}
+	// Create a token in every block, before and after Osaka
+	engine := beacon.New(ethash.NewFaker())
+	signer := types.LatestSigner(&config)
+	_, blocks, receipts := GenerateChainWithGenesis(gspec, engine, 4, func(i int, gen *BlockGen) {
+		tx := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   config.ChainID,
+			Nonce:     gen.TxNonce(sender),
+			To:        &assetbacking.PrecompileAddressBytes,
+			Value:     backing,
+			Gas:       2_000_000,
+			GasFeeCap: gen.header.BaseFee,
+			Data:      input,
+		})
+		gen.AddTx(tx)
+	})
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), gspec, engine, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert block %d: %v", n, err)
+	}
+	for i, block := range blocks {
+		if receipts[i][0].Status != types.ReceiptStatusSuccessful {
+			t.Errorf("block %d: token creation failed", block.NumberU64())
+		}
+		rules := config.Rules(block.Number(), true, block.Time())
+		if osaka := block.Time() >= osakaTime; rules.IsOsaka != osaka {
+			t.Fatalf("block %d: osaka mismatch: have %v, want %v", block.NumberU64(), rules.IsOsaka, osaka)
+		}
+		precompiles := vm.ActivePrecompiledContracts(rules)
+		if precompiles[assetbacking.PrecompileAddressBytes] == nil {
+			t.Errorf("block %d: asset-backing precompile inactive", block.NumberU64())
+		}
+		if p256 := precompiles[assetbacking.LegacyPrecompileAddressBytes]; rules.IsOsaka && (p256 == nil || p256.Name() != "P256VERIFY") {
+			t.Errorf("block %d: P256VERIFY inactive at Osaka: %v", block.NumberU64(), p256)
+		}
+	}
+	statedb, err := chain.State()
+	if err != nil {
+		t.Fatalf("failed to open state: %v", err)
+	}
+	if count := assetbacking.TokenCount(statedb); count != 4 {
+		t.Fatalf("token count mismatch: have %d, want 4", count)
+	}
+	for i := uint64(0); i < 4; i++ {
+		if pool := backingpool.GetBackingPool(statedb, assetbacking.TokenAt(statedb, i)); pool == nil || pool.TotalBacking.Cmp(backing) != 0 {
+			t.Errorf("token %d: backing mismatch: %v", i, pool)
+		}
+	}
+	if locked := statedb.GetBalance(assetbacking.PrecompileAddressBytes); locked.Uint64() != 4000 {
+		t.Errorf("locked backing mismatch: have %d, want 4000", locked)
+	}
+}
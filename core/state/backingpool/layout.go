@@ -0,0 +1,109 @@
+// Package backingpool - storage layout of backing pools.
+package backingpool
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Storage namespaces
+//
+// Every field lives in an ERC-7201 namespace of the token account: a root slot
+// derived from the full keccak256 hash of the namespace id, followed by the
+// field offsets. Each token has its own account storage, so all tokens share
+// the same namespaces.
+const (
+	PoolNamespace = "smartdefi.storage.BackingPool"
+	FeeNamespace  = "smartdefi.storage.Fees"
+	LGENamespace  = "smartdefi.storage.LGE"
+
+	// RouterNamespace holds the router allow-list in the precompile account.
+	RouterNamespace = "smartdefi.storage.Routers"
+
+	// RegistryNamespace holds the registry of created tokens in the precompile
+	// account.
+	RegistryNamespace = "smartdefi.storage.Registry"
+)
+
+var poolRoot = NamespaceSlot(PoolNamespace)
+
+// NamespaceSlot returns the root slot of the storage namespace id, computed as
+// keccak256(keccak256(id) - 1) & ~0xff as defined by ERC-7201.
+func NamespaceSlot(id string) common.Hash {
+	inner := crypto.Keccak256Hash([]byte(id)).Big()
+	inner.Sub(inner, big.NewInt(1))
+
+	root := crypto.Keccak256Hash(common.BigToHash(inner).Bytes())
+	root[common.HashLength-1] = 0
+	return root
+}
+
+// FieldSlot returns the slot of the field at offset in the namespace rooted at root.
+func FieldSlot(root common.Hash, offset int) common.Hash {
+	slot := root.Big()
+	return common.BigToHash(slot.Add(slot, big.NewInt(int64(offset))))
+}
+
+// PoolSlot returns the slot of a pool field in the namespaced layout.
+func PoolSlot(offset int) common.Hash {
+	return FieldSlot(poolRoot, offset)
+}
+
+// LegacySlot returns the slot of a field in the layout used before the storage
+// namespaces, where the fields of a token followed a base slot derived from the
+// token address and tag modulo 1e10. Such slots could overlap those of other
+// fields and of other tokens.
+func LegacySlot(tokenAddress common.Address, tag string, offset int) common.Hash {
+	hash := crypto.Keccak256Hash(tokenAddress.Bytes(), []byte(tag))
+	base := new(big.Int).Mod(hash.Big(), big.NewInt(1e10))
+	return common.BigToHash(base.Add(base, big.NewInt(int64(offset))))
+}
+
+// legacyPoolSlot returns the slot function of a pool in the legacy layout.
+func legacyPoolSlot(tokenAddress common.Address) func(int) common.Hash {
+	return func(offset int) common.Hash {
+		return LegacySlot(tokenAddress, "SmartDeFi-BackingPool", offset)
+	}
+}
+
+// PoolSlots returns every slot a pool with the given number of backing assets
+// occupies in the token account.
+func PoolSlots(assets int) []common.Hash {
+	return poolSlots(PoolSlot, assets)
+}
+
+// LegacyPoolSlots returns every slot a pool with the given number of backing
+// assets occupies in the token account in the legacy layout.
+func LegacyPoolSlots(tokenAddress common.Address, assets int) []common.Hash {
+	return poolSlots(legacyPoolSlot(tokenAddress), assets)
+}
+
+func poolSlots(slot func(int) common.Hash, assets int) []common.Hash {
+	slots := make([]common.Hash, 0, SlotBackingAmounts+1+2*assets)
+	for offset := SlotTotalBacking; offset <= SlotBackingAmounts; offset++ {
+		slots = append(slots, slot(offset))
+	}
+	for i := 0; i < assets; i++ {
+		slots = append(slots, arrayElementSlot(slot(SlotBackingAssets), i), arrayElementSlot(slot(SlotBackingAmounts), i))
+	}
+	return slots
+}
+
+// MigrateLegacyBackingPool moves a pool stored in the legacy layout to the
+// namespaced layout, clearing its legacy slots. It reports whether a legacy
+// pool was found.
+func MigrateLegacyBackingPool(stateDB StateDBInterface, tokenAddress common.Address) bool {
+	legacy := legacyPoolSlot(tokenAddress)
+
+	pool := readBackingPool(stateDB, tokenAddress, legacy)
+	if pool == nil {
+		return false
+	}
+	for _, slot := range poolSlots(legacy, readArrayLength(stateDB, tokenAddress, legacy(SlotBackingAssets))) {
+		stateDB.SetState(tokenAddress, slot, common.Hash{})
+	}
+	SetBackingPool(stateDB, pool)
+	return true
+}
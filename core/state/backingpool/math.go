@@ -0,0 +1,38 @@
+// Package backingpool - fixed-point arithmetic of backing pools.
+package backingpool
+
+import (
+	"math/big"
+)
+
+// PricePrecision is the fixed-point scale of floor prices: a floor price is the
+// backing per 1e18 base units of a token.
+var PricePrecision = big.NewInt(1e18)
+
+// Rounding is the direction MulDiv rounds a result in
+//
+// Every calculation of a pool rounds in the pool's favor: amounts the pool pays
+// out or quotes to holders are rounded down, amounts owed to the pool are
+// rounded up. The dust a rounding leaves behind stays in the pool, where it
+// raises the floor price of the remaining holders.
+type Rounding int
+
+const (
+	RoundDown Rounding = iota // Round towards zero, for amounts paid by the pool
+	RoundUp                   // Round away from zero, for amounts owed to the pool
+)
+
+// MulDiv returns x * y / denominator rounded in the given direction, computed
+// at full precision. The operands must not be negative, and a zero denominator
+// yields zero, as a pool without circulating supply has no backing to share.
+func MulDiv(x, y, denominator *big.Int, rounding Rounding) *big.Int {
+	if denominator.Sign() == 0 {
+		return new(big.Int)
+	}
+	product := new(big.Int).Mul(x, y)
+	quotient, remainder := product.QuoRem(product, denominator, new(big.Int))
+	if rounding == RoundUp && remainder.Sign() != 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	return quotient
+}
@@ -0,0 +1,168 @@
+// Package backingpool - Tests for the fixed-point arithmetic of backing pools.
+package backingpool
+
+import (
+	"math/big"
+	"testing"
+	"testing/quick"
+)
+
+// TestMulDiv tests the rounding of MulDiv in both directions.
+func TestMulDiv(t *testing.T) {
+	huge := new(big.Int).Lsh(big.NewInt(1), 255)
+	tests := []struct {
+		x, y, denominator *big.Int
+		down, up          *big.Int
+	}{
+		{big.NewInt(10), big.NewInt(3), big.NewInt(5), big.NewInt(6), big.NewInt(6)},
+		{big.NewInt(10), big.NewInt(3), big.NewInt(4), big.NewInt(7), big.NewInt(8)},
+		{big.NewInt(1), big.NewInt(1), big.NewInt(3), big.NewInt(0), big.NewInt(1)},
+		{big.NewInt(0), big.NewInt(7), big.NewInt(3), big.NewInt(0), big.NewInt(0)},
+		{big.NewInt(7), big.NewInt(3), big.NewInt(0), big.NewInt(0), big.NewInt(0)},
+		// The intermediate product exceeds 256 bits
+		{huge, big.NewInt(6), big.NewInt(4), new(big.Int).Rsh(new(big.Int).Mul(huge, big.NewInt(3)), 1), new(big.Int).Rsh(new(big.Int).Mul(huge, big.NewInt(3)), 1)},
+		{huge, huge, new(big.Int).Add(huge, big.NewInt(1)), new(big.Int).Sub(huge, big.NewInt(1)), huge},
+	}
+	for i, test := range tests {
+		if have := MulDiv(test.x, test.y, test.denominator, RoundDown); have.Cmp(test.down) != 0 {
+			t.Errorf("Test %d: expected %s rounded down, got %s", i, test.down, have)
+		}
+		if have := MulDiv(test.x, test.y, test.denominator, RoundUp); have.Cmp(test.up) != 0 {
+			t.Errorf("Test %d: expected %s rounded up, got %s", i, test.up, have)
+		}
+	}
+
+	// The results enclose the exact quotient
+	enclose := func(x, y, denominator uint64) bool {
+		if denominator == 0 {
+			return true
+		}
+		var (
+			bx, by, bd = new(big.Int).SetUint64(x), new(big.Int).SetUint64(y), new(big.Int).SetUint64(denominator)
+			product    = new(big.Int).Mul(bx, by)
+			down       = MulDiv(bx, by, bd, RoundDown)
+			up         = MulDiv(bx, by, bd, RoundUp)
+		)
+		return new(big.Int).Mul(down, bd).Cmp(product) <= 0 &&
+			new(big.Int).Mul(up, bd).Cmp(product) >= 0 &&
+			new(big.Int).Sub(up, down).Cmp(big.NewInt(1)) <= 0
+	}
+	if err := quick.Check(enclose, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// poolOf returns a pool with the given backing and supply, scaled like token
+// amounts with 18 decimals when scale is set.
+func poolOf(backing, supply uint64, scale bool) *BackingPool {
+	pool := newTestPool()
+	pool.TotalBacking = new(big.Int).SetUint64(backing)
+	pool.TotalSupply = new(big.Int).SetUint64(supply)
+	pool.BurnedSupply = new(big.Int)
+	if scale {
+		pool.TotalBacking.Mul(pool.TotalBacking, PricePrecision)
+		pool.TotalSupply.Mul(pool.TotalSupply, PricePrecision)
+	}
+	pool.BackingAmounts = []*big.Int{new(big.Int).Set(pool.TotalBacking)}
+	return pool
+}
+
+// burn burns amount tokens of the pool, removing the backing they recover.
+func burn(pool *BackingPool, amount *big.Int) *big.Int {
+	recovered := pool.CalculateBackingForAmount(amount)
+	pool.BurnTokens(amount)
+	pool.RemoveBacking(recovered)
+	return recovered
+}
+
+// TestFloorPriceNeverFalls tests that burning tokens never lowers the floor
+// price, nor the exact backing per circulating token.
+func TestFloorPriceNeverFalls(t *testing.T) {
+	property := func(backing, supply, burned, amount uint64, scale bool) bool {
+		if supply == 0 {
+			return true
+		}
+		pool := poolOf(backing, supply, scale)
+		pool.BurnedSupply.SetUint64(burned % supply)
+		circulating := new(big.Int).Sub(pool.TotalSupply, pool.BurnedSupply)
+		toBurn := new(big.Int).Mod(new(big.Int).SetUint64(amount), new(big.Int).Add(circulating, big.NewInt(1)))
+
+		priceBefore, backingBefore := pool.CalculateFloorPrice(), new(big.Int).Set(pool.TotalBacking)
+		recovered := burn(pool, toBurn)
+
+		if recovered.Sign() < 0 || pool.TotalBacking.Sign() < 0 {
+			return false
+		}
+		// A burn of the whole circulating supply recovers the whole backing
+		remaining := new(big.Int).Sub(pool.TotalSupply, pool.BurnedSupply)
+		if remaining.Sign() == 0 {
+			return recovered.Cmp(backingBefore) <= 0
+		}
+		// backing' / remaining >= backing / circulating
+		if new(big.Int).Mul(pool.TotalBacking, circulating).Cmp(new(big.Int).Mul(backingBefore, remaining)) < 0 {
+			return false
+		}
+		return pool.CalculateFloorPrice().Cmp(priceBefore) >= 0
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestRecoveriesNeverExceedDeposits tests that any sequence of deposits and
+// burns recovers at most the deposited backing, and that a holder splitting a
+// burn into many small burns recovers at most the share of a single burn.
+func TestRecoveriesNeverExceedDeposits(t *testing.T) {
+	property := func(supply uint64, deposits []uint64, burns []uint64, scale bool) bool {
+		if supply == 0 {
+			return true
+		}
+		var (
+			pool      = poolOf(0, supply, scale)
+			deposited = new(big.Int)
+			recovered = new(big.Int)
+		)
+		for i := 0; i < max(len(deposits), len(burns)); i++ {
+			if i < len(deposits) {
+				amount := new(big.Int).SetUint64(deposits[i])
+				pool.AddBacking(amount)
+				deposited.Add(deposited, amount)
+			}
+			if i < len(burns) {
+				circulating := new(big.Int).Sub(pool.TotalSupply, pool.BurnedSupply)
+				amount := new(big.Int).Mod(new(big.Int).SetUint64(burns[i]), new(big.Int).Add(circulating, big.NewInt(1)))
+				recovered.Add(recovered, burn(pool, amount))
+			}
+			if recovered.Cmp(deposited) > 0 || pool.TotalBacking.Sign() < 0 {
+				return false
+			}
+		}
+		return new(big.Int).Add(recovered, pool.TotalBacking).Cmp(deposited) == 0
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+
+	// Many small burns recover no more than the share of their total
+	split := func(backing, supply uint64, burns []uint16) bool {
+		if supply == 0 {
+			return true
+		}
+		pool := poolOf(backing, supply, false)
+		var burned, recovered = new(big.Int), new(big.Int)
+		for _, amount := range burns {
+			circulating := new(big.Int).Sub(pool.TotalSupply, pool.BurnedSupply)
+			if circulating.Sign() == 0 {
+				break
+			}
+			toBurn := new(big.Int).Mod(big.NewInt(int64(amount)), new(big.Int).Add(circulating, big.NewInt(1)))
+			burned.Add(burned, toBurn)
+			recovered.Add(recovered, burn(pool, toBurn))
+		}
+		share := MulDiv(burned, new(big.Int).SetUint64(backing), new(big.Int).SetUint64(supply), RoundDown)
+		return recovered.Cmp(share) <= 0
+	}
+	if err := quick.Check(split, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+}
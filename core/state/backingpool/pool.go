@@ -1,7 +1,8 @@
-// Package backingpool manages protocol-level backing pool state
+// Package backingpool manages protocol-level backing pool state.
 package backingpool
 
 import (
+	"errors"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -9,148 +10,259 @@ import (
 )
 
 const (
-	// Storage slot prefixes for backing pool state
+	// Storage slot offsets of backing pool state within PoolNamespace
 	// Slot layout:
 	// slot[0] = totalBacking
 	// slot[1] = totalSupply
 	// slot[2] = burnedSupply
 	// slot[3] = backingAsset address
-	// slot[4+] = additional backing assets (multi-asset support)
-	
-	SlotTotalBacking  = 0
-	SlotTotalSupply   = 1
-	SlotBurnedSupply  = 2
-	SlotBackingAsset  = 3
-	SlotBackingAssets = 4 // Array start
+	// slot[4] = length of backingAssets, elements at keccak256(slot[4]) + i
+	// slot[5] = length of backingAmounts, elements at keccak256(slot[5]) + i
+
+	SlotTotalBacking   = 0
+	SlotTotalSupply    = 1
+	SlotBurnedSupply   = 2
+	SlotBackingAsset   = 3
+	SlotBackingAssets  = 4 // Array length
+	SlotBackingAmounts = 5 // Array length
+
+	// MaxBackingAssets is the number of backing assets a pool may hold. Element 0
+	// is always the primary backing asset (Smart coin); the remaining entries are
+	// reserved for assets admitted by a later fork.
+	MaxBackingAssets = 8
+)
+
+var (
+	// ErrTooManyAssets is returned when adding an asset to a full pool.
+	ErrTooManyAssets = errors.New("too many backing assets")
+
+	// ErrAssetExists is returned when adding an asset the pool already holds.
+	ErrAssetExists = errors.New("backing asset already exists")
 )
 
-// BackingPool represents the protocol-level backing pool for a token
+// BackingPool represents the protocol-level backing pool for a token.
 type BackingPool struct {
-	TokenAddress    common.Address
-	BackingAsset    common.Address
-	TotalBacking    *big.Int
-	TotalSupply     *big.Int
-	BurnedSupply    *big.Int
-	BackingAssets   []common.Address  // Multi-asset backing support
-	BackingAmounts  []*big.Int
+	TokenAddress   common.Address
+	BackingAsset   common.Address
+	TotalBacking   *big.Int
+	TotalSupply    *big.Int
+	BurnedSupply   *big.Int
+	BackingAssets  []common.Address // Multi-asset backing support
+	BackingAmounts []*big.Int
 }
 
-// StateDBInterface defines the interface needed for backing pool operations
-type StateDBInterface interface {
+// StateReader defines the interface needed for reading backing pools.
+type StateReader interface {
 	GetState(common.Address, common.Hash) common.Hash
-	SetState(common.Address, common.Hash, common.Hash)
 }
 
-// GetBackingPool retrieves backing pool state from the state database
-func GetBackingPool(stateDB StateDBInterface, tokenAddress common.Address) *BackingPool {
-	// Calculate storage slots for this token
-	// Using CREATE2-like deterministic slot calculation
-	slotBase := getSlotBase(tokenAddress)
-	
+// StateDBInterface defines the interface needed for backing pool operations.
+type StateDBInterface interface {
+	StateReader
+	SetState(common.Address, common.Hash, common.Hash) common.Hash
+}
+
+// GetBackingPool retrieves backing pool state from the state database.
+// Pools not yet moved by MigrateLegacyBackingPool are read from the legacy layout.
+func GetBackingPool(stateDB StateReader, tokenAddress common.Address) *BackingPool {
+	if pool := readBackingPool(stateDB, tokenAddress, PoolSlot); pool != nil {
+		return pool
+	}
+	return readBackingPool(stateDB, tokenAddress, legacyPoolSlot(tokenAddress))
+}
+
+// readBackingPool reads the pool of a token from the slots returned by slot.
+func readBackingPool(stateDB StateReader, tokenAddress common.Address, slot func(int) common.Hash) *BackingPool {
 	// Read state from slots
-	totalBackingHash := stateDB.GetState(tokenAddress, common.BigToHash(big.NewInt(slotBase+SlotTotalBacking)))
-	totalSupplyHash := stateDB.GetState(tokenAddress, common.BigToHash(big.NewInt(slotBase+SlotTotalSupply)))
-	
+	totalBackingHash := stateDB.GetState(tokenAddress, slot(SlotTotalBacking))
+	totalSupplyHash := stateDB.GetState(tokenAddress, slot(SlotTotalSupply))
+
 	// Check if pool exists (if both are zero, pool doesn't exist)
 	if totalBackingHash == (common.Hash{}) && totalSupplyHash == (common.Hash{}) {
 		return nil
 	}
-	
+
 	totalBacking := totalBackingHash.Big()
 	totalSupply := totalSupplyHash.Big()
-	burnedSupply := stateDB.GetState(tokenAddress, common.BigToHash(big.NewInt(slotBase+SlotBurnedSupply))).Big()
-	backingAssetBytes := stateDB.GetState(tokenAddress, common.BigToHash(big.NewInt(slotBase+SlotBackingAsset))).Bytes()
+	burnedSupply := stateDB.GetState(tokenAddress, slot(SlotBurnedSupply)).Big()
+	backingAssetBytes := stateDB.GetState(tokenAddress, slot(SlotBackingAsset)).Bytes()
 	backingAsset := common.BytesToAddress(backingAssetBytes[12:])
-	
-	// TODO: Read multi-asset backing arrays
-	
+
+	// Read multi-asset backing arrays
+	assetsSlot := slot(SlotBackingAssets)
+	amountsSlot := slot(SlotBackingAmounts)
+
+	length := readArrayLength(stateDB, tokenAddress, assetsSlot)
+	if length == 0 {
+		// Pools written before the arrays were persisted hold their whole
+		// backing in the primary asset
+		return &BackingPool{
+			TokenAddress:   tokenAddress,
+			BackingAsset:   backingAsset,
+			TotalBacking:   totalBacking,
+			TotalSupply:    totalSupply,
+			BurnedSupply:   burnedSupply,
+			BackingAssets:  []common.Address{backingAsset},
+			BackingAmounts: []*big.Int{new(big.Int).Set(totalBacking)},
+		}
+	}
+	backingAssets := make([]common.Address, length)
+	backingAmounts := make([]*big.Int, length)
+	for i := 0; i < length; i++ {
+		backingAssets[i] = common.BytesToAddress(stateDB.GetState(tokenAddress, arrayElementSlot(assetsSlot, i)).Bytes())
+		backingAmounts[i] = stateDB.GetState(tokenAddress, arrayElementSlot(amountsSlot, i)).Big()
+	}
+
 	return &BackingPool{
-		TokenAddress: tokenAddress,
-		BackingAsset: backingAsset,
-		TotalBacking: totalBacking,
-		TotalSupply:  totalSupply,
-		BurnedSupply: burnedSupply,
+		TokenAddress:   tokenAddress,
+		BackingAsset:   backingAsset,
+		TotalBacking:   totalBacking,
+		TotalSupply:    totalSupply,
+		BurnedSupply:   burnedSupply,
+		BackingAssets:  backingAssets,
+		BackingAmounts: backingAmounts,
 	}
 }
 
-// SetBackingPool writes backing pool state to the state database
+// SetBackingPool writes backing pool state to the state database.
 func SetBackingPool(stateDB StateDBInterface, pool *BackingPool) {
-	slotBase := getSlotBase(pool.TokenAddress)
-	
 	// Write state to slots
-	stateDB.SetState(pool.TokenAddress, 
-		common.BigToHash(big.NewInt(slotBase+SlotTotalBacking)), 
+	stateDB.SetState(pool.TokenAddress,
+		PoolSlot(SlotTotalBacking),
 		common.BigToHash(pool.TotalBacking))
-	
-	stateDB.SetState(pool.TokenAddress, 
-		common.BigToHash(big.NewInt(slotBase+SlotTotalSupply)), 
+
+	stateDB.SetState(pool.TokenAddress,
+		PoolSlot(SlotTotalSupply),
 		common.BigToHash(pool.TotalSupply))
-	
-	stateDB.SetState(pool.TokenAddress, 
-		common.BigToHash(big.NewInt(slotBase+SlotBurnedSupply)), 
+
+	stateDB.SetState(pool.TokenAddress,
+		PoolSlot(SlotBurnedSupply),
 		common.BigToHash(pool.BurnedSupply))
-	
+
 	// Write backing asset address (padded to 32 bytes)
 	backingAssetHash := common.BigToHash(new(big.Int).SetBytes(pool.BackingAsset.Bytes()))
-	stateDB.SetState(pool.TokenAddress, 
-		common.BigToHash(big.NewInt(slotBase+SlotBackingAsset)), 
+	stateDB.SetState(pool.TokenAddress,
+		PoolSlot(SlotBackingAsset),
 		backingAssetHash)
-	
-	// TODO: Write multi-asset backing arrays
+
+	// Write multi-asset backing arrays, clearing the elements of a longer
+	// previous array
+	assetsSlot := PoolSlot(SlotBackingAssets)
+	amountsSlot := PoolSlot(SlotBackingAmounts)
+
+	prevLength := readArrayLength(stateDB, pool.TokenAddress, assetsSlot)
+	length := len(pool.BackingAssets)
+	stateDB.SetState(pool.TokenAddress, assetsSlot, common.BigToHash(big.NewInt(int64(length))))
+	stateDB.SetState(pool.TokenAddress, amountsSlot, common.BigToHash(big.NewInt(int64(length))))
+
+	for i := 0; i < max(length, prevLength); i++ {
+		var asset, amount common.Hash
+		if i < length {
+			asset = common.BytesToHash(pool.BackingAssets[i].Bytes())
+			if i < len(pool.BackingAmounts) && pool.BackingAmounts[i] != nil {
+				amount = common.BigToHash(pool.BackingAmounts[i])
+			}
+		}
+		stateDB.SetState(pool.TokenAddress, arrayElementSlot(assetsSlot, i), asset)
+		stateDB.SetState(pool.TokenAddress, arrayElementSlot(amountsSlot, i), amount)
+	}
+}
+
+// readArrayLength reads the length of an array, capped at MaxBackingAssets.
+func readArrayLength(stateDB StateReader, tokenAddress common.Address, slot common.Hash) int {
+	length := stateDB.GetState(tokenAddress, slot).Big()
+	if length.Cmp(big.NewInt(MaxBackingAssets)) > 0 {
+		return MaxBackingAssets
+	}
+	return int(length.Int64())
+}
+
+// arrayElementSlot returns the slot of element i of the array whose length is
+// stored at slot.
+func arrayElementSlot(slot common.Hash, i int) common.Hash {
+	base := crypto.Keccak256Hash(slot.Bytes()).Big()
+	return common.BigToHash(base.Add(base, big.NewInt(int64(i))))
 }
 
-// CalculateFloorPrice calculates the floor price per token
+// CalculateFloorPrice calculates the floor price per token.
+// The price is rounded down, so it never quotes more backing than a burn recovers.
 func (p *BackingPool) CalculateFloorPrice() *big.Int {
 	if p.TotalSupply.Cmp(big.NewInt(0)) == 0 {
 		return big.NewInt(0)
 	}
-	
+
 	// Floor price = Total Backing / (Total Supply - Burned Supply)
 	circulatingSupply := new(big.Int).Sub(p.TotalSupply, p.BurnedSupply)
-	if circulatingSupply.Cmp(big.NewInt(0)) == 0 {
+	if circulatingSupply.Sign() <= 0 {
 		return big.NewInt(0)
 	}
-	
-	// Multiply by 1e18 for precision, then divide
-	floorPrice := new(big.Int).Mul(p.TotalBacking, big.NewInt(1e18))
-	floorPrice.Div(floorPrice, circulatingSupply)
-	
-	return floorPrice
+
+	// Scale by PricePrecision, then divide
+	return MulDiv(p.TotalBacking, PricePrecision, circulatingSupply, RoundDown)
 }
 
-// CalculateBackingForAmount calculates how much backing is available for a given token amount
+// CalculateBackingForAmount calculates how much backing is available for a given token amount.
+// The backing is rounded down, so the pool never pays out more than the share of
+// the amount and the backing per circulating token never falls.
 func (p *BackingPool) CalculateBackingForAmount(amount *big.Int) *big.Int {
 	if p.TotalSupply.Cmp(big.NewInt(0)) == 0 {
 		return big.NewInt(0)
 	}
-	
+
 	circulatingSupply := new(big.Int).Sub(p.TotalSupply, p.BurnedSupply)
-	if circulatingSupply.Cmp(big.NewInt(0)) == 0 {
+	if circulatingSupply.Sign() <= 0 {
 		return big.NewInt(0)
 	}
-	
+
 	// backing = (amount * totalBacking) / circulatingSupply
-	backing := new(big.Int).Mul(amount, p.TotalBacking)
-	backing.Div(backing, circulatingSupply)
-	
-	return backing
+	return MulDiv(amount, p.TotalBacking, circulatingSupply, RoundDown)
 }
 
-// AddBacking adds backing to the pool (from transaction fees)
+// AddBacking adds backing to the pool (from transaction fees).
+// The backing is held in the primary backing asset.
 func (p *BackingPool) AddBacking(amount *big.Int) {
 	p.TotalBacking.Add(p.TotalBacking, amount)
+	if balance := p.primaryAmount(); balance != nil {
+		balance.Add(balance, amount)
+	}
 }
 
-// BurnTokens burns tokens and updates the pool state
-func (p *BackingPool) BurnTokens(amount *big.Int) {
-	p.BurnedSupply.Add(p.BurnedSupply, amount)
+// RemoveBacking removes backing paid out of the pool from the primary backing asset.
+func (p *BackingPool) RemoveBacking(amount *big.Int) {
+	p.TotalBacking.Sub(p.TotalBacking, amount)
+	if balance := p.primaryAmount(); balance != nil {
+		balance.Sub(balance, amount)
+	}
 }
 
-// getSlotBase calculates the base storage slot for a token's backing pool
-func getSlotBase(tokenAddress common.Address) int64 {
-	// Use token address to deterministically calculate slot base
-	// This ensures each token has unique storage slots
-	hash := crypto.Keccak256Hash(tokenAddress.Bytes(), []byte("SmartDeFi-BackingPool"))
-	return new(big.Int).Mod(hash.Big(), big.NewInt(1e10)).Int64()
+// AddBackingAsset adds an asset with no backing to the pool. It is reserved for
+// the fork admitting backing assets beyond Smart coin.
+func (p *BackingPool) AddBackingAsset(asset common.Address) error {
+	for _, have := range p.BackingAssets {
+		if have == asset {
+			return ErrAssetExists
+		}
+	}
+	if len(p.BackingAssets) >= MaxBackingAssets {
+		return ErrTooManyAssets
+	}
+	p.BackingAssets = append(p.BackingAssets, asset)
+	p.BackingAmounts = append(p.BackingAmounts, new(big.Int))
+	return nil
 }
 
+// primaryAmount returns the amount held in the primary backing asset, if the
+// pool tracks it.
+func (p *BackingPool) primaryAmount() *big.Int {
+	for i, asset := range p.BackingAssets {
+		if asset == p.BackingAsset && i < len(p.BackingAmounts) {
+			return p.BackingAmounts[i]
+		}
+	}
+	return nil
+}
+
+// BurnTokens burns tokens and updates the pool state.
+func (p *BackingPool) BurnTokens(amount *big.Int) {
+	p.BurnedSupply.Add(p.BurnedSupply, amount)
+}
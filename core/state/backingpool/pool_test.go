@@ -0,0 +1,178 @@
+// Package backingpool - Tests for backing pool state management.
+package backingpool
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// mockStateDB is a map-backed implementation of StateDBInterface for testing.
+type mockStateDB map[common.Address]map[common.Hash]common.Hash
+
+func (m mockStateDB) GetState(addr common.Address, slot common.Hash) common.Hash {
+	return m[addr][slot]
+}
+
+func (m mockStateDB) SetState(addr common.Address, slot common.Hash, value common.Hash) common.Hash {
+	if m[addr] == nil {
+		m[addr] = make(map[common.Hash]common.Hash)
+	}
+	prev := m[addr][slot]
+	m[addr][slot] = value
+	return prev
+}
+
+// setLegacyBackingPool writes a pool the way it was written before the backing
+// arrays were persisted and the storage namespaces were introduced.
+func setLegacyBackingPool(stateDB StateDBInterface, pool *BackingPool) {
+	slot := legacyPoolSlot(pool.TokenAddress)
+
+	stateDB.SetState(pool.TokenAddress, slot(SlotTotalBacking), common.BigToHash(pool.TotalBacking))
+	stateDB.SetState(pool.TokenAddress, slot(SlotTotalSupply), common.BigToHash(pool.TotalSupply))
+	stateDB.SetState(pool.TokenAddress, slot(SlotBurnedSupply), common.BigToHash(pool.BurnedSupply))
+	stateDB.SetState(pool.TokenAddress, slot(SlotBackingAsset), common.BytesToHash(pool.BackingAsset.Bytes()))
+}
+
+func newTestPool() *BackingPool {
+	return &BackingPool{
+		TokenAddress:   common.HexToAddress("0x2222222222222222222222222222222222222222"),
+		BackingAsset:   common.Address{},
+		TotalBacking:   big.NewInt(1000),
+		TotalSupply:    big.NewInt(500),
+		BurnedSupply:   big.NewInt(20),
+		BackingAssets:  []common.Address{{}},
+		BackingAmounts: []*big.Int{big.NewInt(1000)},
+	}
+}
+
+// TestBackingPoolRoundTrip tests that pools written by SetBackingPool are read
+// back unchanged, including their backing arrays.
+func TestBackingPoolRoundTrip(t *testing.T) {
+	stateDB := make(mockStateDB)
+	pool := newTestPool()
+
+	// Round trip a single-asset pool
+	SetBackingPool(stateDB, pool)
+	if have := GetBackingPool(stateDB, pool.TokenAddress); !reflect.DeepEqual(have, pool) {
+		t.Errorf("Pool mismatch:\nhave %+v\nwant %+v", have, pool)
+	}
+
+	// Round trip a pool holding additional assets
+	for i := 1; i < MaxBackingAssets; i++ {
+		asset := common.BigToAddress(big.NewInt(int64(i)))
+		if err := pool.AddBackingAsset(asset); err != nil {
+			t.Fatalf("Failed to add asset %d: %v", i, err)
+		}
+		pool.BackingAmounts[i].SetInt64(int64(i * 100))
+	}
+	SetBackingPool(stateDB, pool)
+	if have := GetBackingPool(stateDB, pool.TokenAddress); !reflect.DeepEqual(have, pool) {
+		t.Errorf("Pool mismatch:\nhave %+v\nwant %+v", have, pool)
+	}
+
+	// Shrinking the arrays clears the dropped elements
+	pool.BackingAssets = pool.BackingAssets[:2]
+	pool.BackingAmounts = pool.BackingAmounts[:2]
+	SetBackingPool(stateDB, pool)
+	if have := GetBackingPool(stateDB, pool.TokenAddress); !reflect.DeepEqual(have, pool) {
+		t.Errorf("Pool mismatch:\nhave %+v\nwant %+v", have, pool)
+	}
+	assetsSlot := PoolSlot(SlotBackingAssets)
+	for i := 2; i < MaxBackingAssets; i++ {
+		if value := stateDB.GetState(pool.TokenAddress, arrayElementSlot(assetsSlot, i)); value != (common.Hash{}) {
+			t.Errorf("Expected element %d to be cleared, got %x", i, value)
+		}
+	}
+}
+
+// TestLegacyBackingPool tests that pools written before the backing arrays were
+// persisted are read with their whole backing in the primary asset, and are
+// upgraded when written back.
+func TestLegacyBackingPool(t *testing.T) {
+	stateDB := make(mockStateDB)
+	want := newTestPool()
+
+	legacy := newTestPool()
+	legacy.BackingAssets, legacy.BackingAmounts = nil, nil
+	setLegacyBackingPool(stateDB, legacy)
+
+	pool := GetBackingPool(stateDB, want.TokenAddress)
+	if !reflect.DeepEqual(pool, want) {
+		t.Fatalf("Pool mismatch:\nhave %+v\nwant %+v", pool, want)
+	}
+	pool.AddBacking(big.NewInt(50))
+	SetBackingPool(stateDB, pool)
+
+	want.AddBacking(big.NewInt(50))
+	if have := GetBackingPool(stateDB, want.TokenAddress); !reflect.DeepEqual(have, want) {
+		t.Errorf("Pool mismatch:\nhave %+v\nwant %+v", have, want)
+	}
+}
+
+// TestBackingAmounts tests that the backing arrays follow the pool's backing.
+func TestBackingAmounts(t *testing.T) {
+	pool := newTestPool()
+	if err := pool.AddBackingAsset(common.HexToAddress("0x01")); err != nil {
+		t.Fatalf("Failed to add asset: %v", err)
+	}
+	pool.AddBacking(big.NewInt(300))
+	pool.RemoveBacking(big.NewInt(100))
+
+	if pool.TotalBacking.Cmp(big.NewInt(1200)) != 0 {
+		t.Errorf("Expected total backing 1200, got %s", pool.TotalBacking)
+	}
+	if pool.BackingAmounts[0].Cmp(big.NewInt(1200)) != 0 || pool.BackingAmounts[1].Sign() != 0 {
+		t.Errorf("Unexpected backing amounts %v", pool.BackingAmounts)
+	}
+
+	// Assets can be added only once and up to the limit
+	if err := pool.AddBackingAsset(common.HexToAddress("0x01")); err != ErrAssetExists {
+		t.Errorf("Expected %v, got %v", ErrAssetExists, err)
+	}
+	for i := len(pool.BackingAssets); i < MaxBackingAssets; i++ {
+		if err := pool.AddBackingAsset(common.BigToAddress(big.NewInt(int64(i + 1)))); err != nil {
+			t.Fatalf("Failed to add asset: %v", err)
+		}
+	}
+	if err := pool.AddBackingAsset(common.HexToAddress("0xff")); err != ErrTooManyAssets {
+		t.Errorf("Expected %v, got %v", ErrTooManyAssets, err)
+	}
+}
+
+// TestMigrateLegacyBackingPool tests that a legacy pool is moved to the
+// namespaced layout without leaving any of its legacy slots behind.
+func TestMigrateLegacyBackingPool(t *testing.T) {
+	stateDB := make(mockStateDB)
+	want := newTestPool()
+
+	legacy := newTestPool()
+	legacy.BackingAssets, legacy.BackingAmounts = nil, nil
+	setLegacyBackingPool(stateDB, legacy)
+
+	if !MigrateLegacyBackingPool(stateDB, want.TokenAddress) {
+		t.Fatal("Expected legacy pool to be migrated")
+	}
+	if MigrateLegacyBackingPool(stateDB, want.TokenAddress) {
+		t.Error("Expected migrated pool not to be migrated again")
+	}
+	if have := readBackingPool(stateDB, want.TokenAddress, PoolSlot); !reflect.DeepEqual(have, want) {
+		t.Errorf("Pool mismatch:\nhave %+v\nwant %+v", have, want)
+	}
+	if have := readBackingPool(stateDB, want.TokenAddress, legacyPoolSlot(want.TokenAddress)); have != nil {
+		t.Errorf("Expected legacy pool to be cleared, got %+v", have)
+	}
+
+	// Only the namespaced slots remain in use
+	inUse := make(map[common.Hash]bool)
+	for _, slot := range PoolSlots(len(want.BackingAssets)) {
+		inUse[slot] = true
+	}
+	for slot, value := range stateDB[want.TokenAddress] {
+		if value != (common.Hash{}) && !inUse[slot] {
+			t.Errorf("Unexpected slot %x in use", slot)
+		}
+	}
+}
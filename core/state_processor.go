@@ -78,6 +78,11 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 	if config.DAOForkSupport && config.DAOForkBlock != nil && config.DAOForkBlock.Cmp(block.Number()) == 0 {
 		misc.ApplyDAOHardFork(tracingStateDB)
 	}
+	if len(config.SmartDeFiLegacyTokens) > 0 && config.IsSmartDeFi(blockNumber, block.Time()) {
+		if parent := p.chain.GetHeader(block.ParentHash(), block.NumberU64()-1); parent != nil && config.IsSmartDeFiTransition(blockNumber, parent.Time, block.Time()) {
+			misc.ApplySmartDeFiHardFork(tracingStateDB, config)
+		}
+	}
 	var (
 		context vm.BlockContext
 		signer  = types.MakeSigner(config, header.Number, header.Time)
@@ -274,6 +274,14 @@ const (
 	// BalanceChangeRevert is emitted when the balance is reverted back to a previous value due to call failure.
 	// It is only emitted when the tracer has opted in to use the journaling wrapper (WrapWithJournal).
 	BalanceChangeRevert BalanceChangeReason = 15
+
+	// BalanceChangeBackingLocked is Smart coin sent to the SmartDeFi asset-backing
+	// precompile and locked as the backing of a token.
+	BalanceChangeBackingLocked BalanceChangeReason = 16
+	// BalanceChangeBackingRecovered is Smart coin released from the backing held
+	// by the SmartDeFi asset-backing precompile, e.g. when tokens are burned to
+	// recover their backing or an LGE contribution is refunded.
+	BalanceChangeBackingRecovered BalanceChangeReason = 17
 )
 
 // GasChangeReason is used to indicate the reason for a gas change, useful
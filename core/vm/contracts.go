@@ -25,6 +25,7 @@ import (
 	"math"
 	"math/big"
 	"math/bits"
+	"slices"
 
 	"github.com/consensys/gnark-crypto/ecc"
 	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
@@ -120,10 +121,6 @@ var PrecompiledContractsCancun = PrecompiledContracts{
 	common.BytesToAddress([]byte{0x8}): &bn256PairingIstanbul{},
 	common.BytesToAddress([]byte{0x9}): &blake2F{},
 	common.BytesToAddress([]byte{0xa}): &kzgPointEvaluation{},
-
-	// SmartDeFi Asset Backing Precompile
-	// Address: 0x0000000000000000000000000000000000000100
-	common.HexToAddress("0x0000000000000000000000000000000000000100"): &assetbacking.Precompile{},
 }
 
 // PrecompiledContractsPrague contains the set of pre-compiled Ethereum
@@ -174,10 +171,12 @@ var PrecompiledContractsOsaka = PrecompiledContracts{
 	common.BytesToAddress([]byte{0x11}): &bls12381MapG2{},
 
 	common.BytesToAddress([]byte{0x1, 0x00}): &p256Verify{},
+}
 
-	// SmartDeFi Asset Backing Precompile
-	// Address: 0x0000000000000000000000000000000000000100
-	common.HexToAddress("0x0000000000000000000000000000000000000100"): &assetbacking.Precompile{},
+// PrecompiledContractsSmartDeFi contains the SmartDeFi precompiled contracts,
+// installed on top of the fork's set once the SmartDeFi fork is active.
+var PrecompiledContractsSmartDeFi = PrecompiledContracts{
+	assetbacking.PrecompileAddressBytes: &assetBacking{},
 }
 
 // PrecompiledContractsP256Verify contains the precompiled Ethereum
@@ -221,6 +220,17 @@ func init() {
 }
 
 func activePrecompiledContracts(rules params.Rules) PrecompiledContracts {
+	contracts := forkPrecompiledContracts(rules)
+	if rules.IsSmartDeFi {
+		contracts = maps.Clone(contracts)
+		maps.Copy(contracts, PrecompiledContractsSmartDeFi)
+	}
+	return contracts
+}
+
+// forkPrecompiledContracts returns the precompiled contracts defined by the
+// Ethereum fork active under the given rules.
+func forkPrecompiledContracts(rules params.Rules) PrecompiledContracts {
 	switch {
 	case rules.IsVerkle:
 		return PrecompiledContractsVerkle
@@ -248,6 +258,21 @@ func ActivePrecompiledContracts(rules params.Rules) PrecompiledContracts {
 
 // ActivePrecompiles returns the precompile addresses enabled with the current configuration.
 func ActivePrecompiles(rules params.Rules) []common.Address {
+	addrs := forkPrecompiles(rules)
+	if rules.IsSmartDeFi {
+		addrs = slices.Clone(addrs)
+		for addr := range PrecompiledContractsSmartDeFi {
+			if !slices.Contains(addrs, addr) {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	return addrs
+}
+
+// forkPrecompiles returns the precompile addresses defined by the Ethereum fork
+// active under the given rules.
+func forkPrecompiles(rules params.Rules) []common.Address {
 	switch {
 	case rules.IsOsaka:
 		return PrecompiledAddressesOsaka
@@ -284,35 +309,6 @@ func RunPrecompiledContract(p PrecompiledContract, input []byte, suppliedGas uin
 	return output, suppliedGas, err
 }
 
-// RunPrecompiledContractWithState runs a precompiled contract with StateDB access
-// This is used for stateful precompiles like SmartDeFi Asset Backing
-func RunPrecompiledContractWithState(p PrecompiledContract, input []byte, suppliedGas uint64, stateDB StateDB, logger *tracing.Hooks) (ret []byte, remainingGas uint64, err error) {
-	// Check if precompile supports StateDB (for stateful precompiles)
-	// Use type assertion to check for SetStateDB method
-	if stateful, ok := p.(interface{ SetStateDB(StateDB) }); ok {
-		stateful.SetStateDB(stateDB)
-	}
-	return RunPrecompiledContract(p, input, suppliedGas, logger)
-}
-
-// RunPrecompiledContractWithStateAndCaller runs a precompiled contract with StateDB and caller context
-// This is used for stateful precompiles that need caller information
-func RunPrecompiledContractWithStateAndCaller(p PrecompiledContract, input []byte, suppliedGas uint64, stateDB StateDB, caller common.Address, value *big.Int, logger *tracing.Hooks) (ret []byte, remainingGas uint64, err error) {
-	// Check if precompile supports StateDB (for stateful precompiles)
-	if stateful, ok := p.(interface{ SetStateDB(StateDB) }); ok {
-		stateful.SetStateDB(stateDB)
-	}
-	// Check if precompile supports SetCaller (for precompiles that need caller context)
-	if callable, ok := p.(interface{ SetCaller(common.Address) }); ok {
-		callable.SetCaller(caller)
-	}
-	// Check if precompile supports SetValue (for precompiles that need value context)
-	if valuable, ok := p.(interface{ SetValue(*big.Int) }); ok {
-		valuable.SetValue(value)
-	}
-	return RunPrecompiledContract(p, input, suppliedGas, logger)
-}
-
 // ecrecover implemented as a native contract.
 type ecrecover struct{}
 
@@ -0,0 +1,181 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
+	"github.com/holiman/uint256"
+)
+
+// PrecompileContext is the execution environment of a single call into a
+// stateful precompiled contract. It is assembled by the EVM for every call
+// and handed to the contract by value, so contracts never observe the context
+// of another call, even when the same instance is shared by concurrent EVMs.
+type PrecompileContext struct {
+	EVM      *EVM           // EVM executing the call
+	StateDB  StateDB        // State the call operates on
+	Caller   common.Address // Address of the caller (msg.sender)
+	Address  common.Address // Address of the precompiled contract
+	Value    *uint256.Int   // Value transferred with the call (msg.value)
+	ReadOnly bool           // Whether state modifications are disallowed
+	Depth    int            // Call depth the contract is executed at
+	Gas      uint64         // Gas left to the call after the contract's RequiredGas
+}
+
+// StatefulPrecompiledContract is a precompiled contract which, beyond its input,
+// needs access to the state and the call frame it is executed in. The contract
+// must not retain the context or keep any per-call data in its own fields.
+//
+// RequiredGas is charged before the contract runs. The contract may consume
+// further gas out of ctx.Gas while running, e.g. for its state accesses, and
+// returns the gas left.
+type StatefulPrecompiledContract interface {
+	PrecompiledContract
+	RunStateful(ctx PrecompileContext, input []byte) ([]byte, uint64, error) // RunStateful runs the contract within ctx
+}
+
+// RunStatefulPrecompiledContract runs and evaluates the output of a stateful
+// precompiled contract within the given call context.
+// It returns
+// - the returned bytes,
+// - the _remaining_ gas,
+// - any error that occurred
+func RunStatefulPrecompiledContract(p StatefulPrecompiledContract, ctx PrecompileContext, input []byte, suppliedGas uint64, logger *tracing.Hooks) (ret []byte, remainingGas uint64, err error) {
+	gasCost := p.RequiredGas(input)
+	if suppliedGas < gasCost {
+		return nil, 0, ErrOutOfGas
+	}
+	if logger != nil && logger.OnGasChange != nil {
+		logger.OnGasChange(suppliedGas, suppliedGas-gasCost, tracing.GasChangeCallPrecompiledContract)
+	}
+	ctx.Gas = suppliedGas - gasCost
+	return p.RunStateful(ctx, input)
+}
+
+// runPrecompiledContract runs the precompiled contract p on behalf of a call of
+// the given type, providing stateful contracts with the context of the current
+// call frame.
+//
+// Stateful contracts operate on their own storage and balance, which has no
+// meaning when their code is borrowed by another account. DELEGATECALL and
+// CALLCODE into a stateful contract are therefore rejected with a revert,
+// without charging the contract's gas.
+func (evm *EVM) runPrecompiledContract(p PrecompiledContract, typ OpCode, caller common.Address, addr common.Address, input []byte, gas uint64, value *uint256.Int) (ret []byte, remainingGas uint64, err error) {
+	sp, ok := p.(StatefulPrecompiledContract)
+	if !ok {
+		return RunPrecompiledContract(p, input, gas, evm.Config.Tracer)
+	}
+	if typ == DELEGATECALL || typ == CALLCODE {
+		return nil, gas, ErrExecutionReverted
+	}
+	if value == nil {
+		value = new(uint256.Int)
+	}
+	ctx := PrecompileContext{
+		EVM:      evm,
+		StateDB:  evm.StateDB,
+		Caller:   caller,
+		Address:  addr,
+		Value:    value,
+		ReadOnly: typ == STATICCALL || evm.readOnly,
+		Depth:    evm.depth,
+	}
+	return RunStatefulPrecompiledContract(sp, ctx, input, gas, evm.Config.Tracer)
+}
+
+// transfer moves the value of a call from caller to the callee addr, whose
+// precompiled contract is p, if any. Value sent to the asset-backing precompile
+// is locked as backing, which is reported to tracers as such.
+func (evm *EVM) transfer(p PrecompiledContract, caller common.Address, addr common.Address, value *uint256.Int) {
+	if _, ok := p.(*assetBacking); ok && !value.IsZero() {
+		evm.StateDB.SubBalance(caller, value, tracing.BalanceChangeBackingLocked)
+		evm.StateDB.AddBalance(addr, value, tracing.BalanceChangeBackingLocked)
+		return
+	}
+	evm.Context.Transfer(evm.StateDB, caller, addr, value)
+}
+
+// assetBacking adapts the SmartDeFi asset-backing precompile to the stateful
+// precompile interface. The precompile lives in its own package, which cannot
+// import vm, so the call frame is translated into the package's own context.
+type assetBacking struct {
+	assetbacking.Precompile
+}
+
+func (c *assetBacking) RunStateful(ctx PrecompileContext, input []byte) ([]byte, uint64, error) {
+	return runAssetBacking(c.Precompile.RunStateful, ctx, input)
+}
+
+// smartToken adapts the native implementation of the tokens created by the
+// asset-backing precompile to the stateful precompile interface. It is not
+// installed at a fixed address, but serves every account holding the token
+// code once the SmartDeFi fork is active, see isSmartToken.
+type smartToken struct {
+	assetbacking.Token
+}
+
+var smartTokenContract = &smartToken{}
+
+func (c *smartToken) RunStateful(ctx PrecompileContext, input []byte) ([]byte, uint64, error) {
+	return runAssetBacking(c.Token.RunStateful, ctx, input)
+}
+
+// isSmartToken reports whether addr is a token created by the asset-backing
+// precompile, to be run by smartTokenContract. The code of addr, as resolved by
+// the call, is passed in, so that calls to other accounts cost no extra lookup.
+// An account delegating to a token (EIP-7702) is not a token itself.
+func (evm *EVM) isSmartToken(addr common.Address, code []byte) bool {
+	if !evm.chainRules.IsSmartDeFi || !assetbacking.IsToken(code) {
+		return false
+	}
+	return !evm.chainRules.IsPrague || assetbacking.IsToken(evm.StateDB.GetCode(addr))
+}
+
+// runAssetBacking invokes run, a contract of the asset-backing package, within
+// the package's equivalent of ctx. The state accesses of the contract are
+// charged as they happen.
+func runAssetBacking(run func(assetbacking.CallContext, []byte) ([]byte, error), ctx PrecompileContext, input []byte) ([]byte, uint64, error) {
+	db := newMeteredStateDB(ctx)
+	ret, err := run(assetbacking.CallContext{
+		StateDB:     db,
+		Caller:      ctx.Caller,
+		Address:     ctx.Address,
+		Value:       ctx.Value,
+		ReadOnly:    ctx.ReadOnly,
+		Depth:       ctx.Depth,
+		BlockNumber: ctx.EVM.Context.BlockNumber.Uint64(),
+		Time:        ctx.EVM.Context.Time,
+	}, input)
+	if db.err != nil {
+		return nil, 0, db.err
+	}
+	// The package cannot reference the EVM's revert error, so translate it to
+	// make the EVM refund the remaining gas. Custom errors are returned as the
+	// revert data.
+	var revert *assetbacking.RevertError
+	if errors.As(err, &revert) {
+		ret = revert.Data()
+	}
+	if errors.Is(err, assetbacking.ErrExecutionReverted) {
+		err = ErrExecutionReverted
+	}
+	return ret, db.gas, err
+}
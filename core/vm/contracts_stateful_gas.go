@@ -0,0 +1,174 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// meteredStateDB charges a stateful precompiled contract for its state accesses
+// while it runs, at the prices the equivalent opcodes pay:
+//
+//   - storage reads and writes as SLOAD and SSTORE, including the EIP-2929
+//     access list and the EIP-2200 refunds,
+//   - account accesses as BALANCE, with the new account surcharge of a CALL
+//     when value is credited to an empty account,
+//   - deployed code as the code deposit of a CREATE,
+//   - logs as the LOG opcodes.
+//
+// Once the gas is exhausted the call fails with ErrOutOfGas, reverting all of
+// its changes, and the remaining accesses are not performed: reads return zero
+// values and writes are dropped. A contract iterating over state must stop at
+// the zero values, so its work stays bounded by the gas of the call.
+type meteredStateDB struct {
+	StateDB
+	gas            uint64 // Gas left to the call
+	clearingRefund uint64 // Refund for clearing a storage slot
+	tracer         *tracing.Hooks
+	err            error // ErrOutOfGas once the gas is exhausted
+}
+
+// newMeteredStateDB returns the state of ctx metered against the gas of ctx.
+func newMeteredStateDB(ctx PrecompileContext) *meteredStateDB {
+	refund := params.SstoreClearsScheduleRefundEIP2200
+	if ctx.EVM.chainRules.IsLondon {
+		refund = params.SstoreClearsScheduleRefundEIP3529
+	}
+	return &meteredStateDB{
+		StateDB:        ctx.StateDB,
+		gas:            ctx.Gas,
+		clearingRefund: refund,
+		tracer:         ctx.EVM.Config.Tracer,
+	}
+}
+
+// charge deducts cost from the gas left, reporting it to the tracer. It returns
+// false if the gas is exhausted, in which case the access must be skipped.
+func (db *meteredStateDB) charge(cost uint64) bool {
+	if db.err != nil {
+		return false
+	}
+	if db.gas < cost {
+		db.err = ErrOutOfGas
+		cost = db.gas
+	}
+	if db.tracer != nil && db.tracer.OnGasChange != nil {
+		db.tracer.OnGasChange(db.gas, db.gas-cost, tracing.GasChangeCallPrecompiledContract)
+	}
+	db.gas -= cost
+	return db.err == nil
+}
+
+// chargeAccount charges an access to addr, adding it to the access list. It
+// returns false if the gas is exhausted.
+func (db *meteredStateDB) chargeAccount(addr common.Address) bool {
+	if db.err != nil {
+		return false
+	}
+	if !db.StateDB.AddressInAccessList(addr) {
+		db.StateDB.AddAddressToAccessList(addr)
+		return db.charge(params.ColdAccountAccessCostEIP2929)
+	}
+	return db.charge(params.WarmStorageReadCostEIP2929)
+}
+
+func (db *meteredStateDB) GetState(addr common.Address, slot common.Hash) common.Hash {
+	if db.err != nil || !db.charge(sloadCostEIP2929(db.StateDB, addr, slot)) {
+		return common.Hash{}
+	}
+	return db.StateDB.GetState(addr, slot)
+}
+
+func (db *meteredStateDB) SetState(addr common.Address, slot common.Hash, value common.Hash) common.Hash {
+	// Like SSTORE, fail if no more than the reentrancy sentry is left
+	if db.err == nil && db.gas <= params.SstoreSentryGasEIP2200 {
+		db.charge(db.gas)
+		db.err = ErrOutOfGas
+	}
+	if db.err != nil || !db.charge(sstoreCostEIP2929(db.StateDB, addr, slot, value, db.clearingRefund)) {
+		return common.Hash{}
+	}
+	return db.StateDB.SetState(addr, slot, value)
+}
+
+func (db *meteredStateDB) GetBalance(addr common.Address) *uint256.Int {
+	if !db.chargeAccount(addr) {
+		return new(uint256.Int)
+	}
+	return db.StateDB.GetBalance(addr)
+}
+
+func (db *meteredStateDB) AddBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
+	if !db.chargeAccount(addr) {
+		return uint256.Int{}
+	}
+	if !amount.IsZero() && db.StateDB.Empty(addr) && !db.charge(params.CallNewAccountGas) {
+		return uint256.Int{}
+	}
+	return db.StateDB.AddBalance(addr, amount, reason)
+}
+
+func (db *meteredStateDB) SubBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
+	if !db.chargeAccount(addr) {
+		return uint256.Int{}
+	}
+	return db.StateDB.SubBalance(addr, amount, reason)
+}
+
+func (db *meteredStateDB) GetCode(addr common.Address) []byte {
+	if !db.chargeAccount(addr) {
+		return nil
+	}
+	return db.StateDB.GetCode(addr)
+}
+
+func (db *meteredStateDB) GetCodeSize(addr common.Address) int {
+	if !db.chargeAccount(addr) {
+		return 0
+	}
+	return db.StateDB.GetCodeSize(addr)
+}
+
+func (db *meteredStateDB) SetCode(addr common.Address, code []byte, reason tracing.CodeChangeReason) []byte {
+	if !db.chargeAccount(addr) || !db.charge(uint64(len(code))*params.CreateDataGas) {
+		return nil
+	}
+	return db.StateDB.SetCode(addr, code, reason)
+}
+
+func (db *meteredStateDB) GetNonce(addr common.Address) uint64 {
+	if !db.chargeAccount(addr) {
+		return 0
+	}
+	return db.StateDB.GetNonce(addr)
+}
+
+func (db *meteredStateDB) SetNonce(addr common.Address, nonce uint64, reason tracing.NonceChangeReason) {
+	if db.chargeAccount(addr) {
+		db.StateDB.SetNonce(addr, nonce, reason)
+	}
+}
+
+func (db *meteredStateDB) AddLog(log *types.Log) {
+	if db.charge(params.LogGas + uint64(len(log.Topics))*params.LogTopicGas + uint64(len(log.Data))*params.LogDataGas) {
+		db.StateDB.AddLog(log)
+	}
+}
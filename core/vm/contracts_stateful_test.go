@@ -0,0 +1,286 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math/big"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// newSmartDeFiTestEVM creates an EVM on a fresh state with the SmartDeFi
+// precompile active.
+func newSmartDeFiTestEVM() (*EVM, *state.StateDB) {
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	vmctx := BlockContext{
+		CanTransfer: func(db StateDB, addr common.Address, amount *uint256.Int) bool {
+			return db.GetBalance(addr).Cmp(amount) >= 0
+		},
+		Transfer: func(db StateDB, sender, recipient common.Address, amount *uint256.Int) {
+			db.SubBalance(sender, amount, tracing.BalanceChangeTransfer)
+			db.AddBalance(recipient, amount, tracing.BalanceChangeTransfer)
+		},
+		BlockNumber: big.NewInt(0),
+		Random:      &common.Hash{},
+	}
+	return NewEVM(vmctx, statedb, smartDeFiTestConfig(), Config{}), statedb
+}
+
+// smartDeFiTestConfig returns a chain config with all forks up to Prague, and
+// SmartDeFi, active from genesis.
+func smartDeFiTestConfig() *params.ChainConfig {
+	config := *params.MergedTestChainConfig
+	config.SmartDeFiTime = new(uint64)
+	config.OsakaTime = nil
+	return &config
+}
+
+// createTokenInput returns the calldata creating a token with the given
+// initial backing.
+func createTokenInput(t *testing.T, owner common.Address, backing *big.Int) []byte {
+	t.Helper()
+
+	var fees [12]*big.Int
+	for i := range fees {
+		fees[i] = new(big.Int)
+	}
+	input, err := assetbacking.EncodeCreateToken(assetbacking.TokenConfig{
+		Name:           "Test Token",
+		Symbol:         "TEST",
+		TotalSupply:    big.NewInt(1_000_000),
+		InitialBacking: backing,
+		Fees:           fees,
+		Owner:          owner,
+	})
+	if err != nil {
+		t.Fatalf("failed to encode input: %v", err)
+	}
+	return input
+}
+
+// TestStatefulPrecompileConcurrentCalls runs the shared asset-backing precompile
+// instance from many EVMs at once, each operating on its own state with its own
+// caller, and checks that no call observes the context of another. Run with
+// -race to detect any mutable state kept in the shared instance.
+func TestStatefulPrecompileConcurrentCalls(t *testing.T) {
+	const workers = 64
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			var (
+				evm, statedb = newSmartDeFiTestEVM()
+				caller       = common.BigToAddress(big.NewInt(int64(i + 1)))
+				backing      = big.NewInt(int64(1000 + i))
+			)
+			statedb.AddBalance(caller, uint256.NewInt(params.Ether), tracing.BalanceChangeUnspecified)
+
+			ret, _, err := evm.Call(caller, assetbacking.PrecompileAddressBytes, createTokenInput(t, caller, backing), 10_000_000, uint256.MustFromBig(backing))
+			if err != nil {
+				t.Errorf("worker %d: call failed: %v", i, err)
+				return
+			}
+			pool := backingpool.GetBackingPool(statedb, common.BytesToAddress(ret))
+			if pool == nil {
+				t.Errorf("worker %d: backing pool not found in own state", i)
+				return
+			}
+			if pool.TotalBacking.Cmp(backing) != 0 {
+				t.Errorf("worker %d: backing mismatch: have %v, want %v", i, pool.TotalBacking, backing)
+			}
+			if locked := statedb.GetBalance(assetbacking.PrecompileAddressBytes); locked.ToBig().Cmp(backing) != 0 {
+				t.Errorf("worker %d: locked balance mismatch: have %v, want %v", i, locked, backing)
+			}
+			want := new(uint256.Int).Sub(uint256.NewInt(params.Ether), uint256.MustFromBig(backing))
+			if have := statedb.GetBalance(caller); !have.Eq(want) {
+				t.Errorf("worker %d: caller balance mismatch: have %v, want %v", i, have, want)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestSmartDeFiPrecompileActivation checks that the asset-backing precompile is
+// only installed once the SmartDeFi fork is active.
+func TestSmartDeFiPrecompileActivation(t *testing.T) {
+	cancun := *params.MergedTestChainConfig
+	cancun.PragueTime, cancun.OsakaTime = nil, nil
+
+	addr := assetbacking.PrecompileAddressBytes
+	for _, tt := range []struct {
+		name   string
+		config *params.ChainConfig
+		addrs  []common.Address // precompile addresses of the base fork
+	}{
+		{"cancun", &cancun, PrecompiledAddressesCancun},
+		{"osaka", params.MergedTestChainConfig, PrecompiledAddressesOsaka},
+	} {
+		rules := tt.config.Rules(common.Big0, true, 0)
+		if _, ok := ActivePrecompiledContracts(rules)[addr].(*assetBacking); ok {
+			t.Errorf("%s: asset-backing precompile active without the SmartDeFi fork", tt.name)
+		}
+		if have := ActivePrecompiles(rules); len(have) != len(tt.addrs) {
+			t.Errorf("%s: address count mismatch without SmartDeFi: have %d, want %d", tt.name, len(have), len(tt.addrs))
+		}
+		config := *tt.config
+		config.SmartDeFiTime = new(uint64)
+		rules = config.Rules(common.Big0, true, 0)
+
+		if _, ok := ActivePrecompiledContracts(rules)[addr].(*assetBacking); !ok {
+			t.Errorf("%s: asset-backing precompile inactive with the SmartDeFi fork", tt.name)
+		}
+		// The fork's precompiles, including P256VERIFY on Osaka, are kept
+		have := ActivePrecompiles(rules)
+		if !slices.Contains(have, addr) {
+			t.Errorf("%s: asset-backing address missing from active precompiles", tt.name)
+		}
+		if len(have) != len(tt.addrs)+1 {
+			t.Errorf("%s: address count mismatch with SmartDeFi: have %d, want %d", tt.name, len(have), len(tt.addrs)+1)
+		}
+		for _, fork := range tt.addrs {
+			if _, ok := ActivePrecompiledContracts(rules)[fork].(*assetBacking); ok {
+				t.Errorf("%s: asset-backing precompile replaces precompile %x", tt.name, fork)
+			}
+		}
+	}
+}
+
+// TestStatefulPrecompileGasMetering checks that the state accesses of the
+// asset-backing precompile are charged while it runs: cold accesses cost more
+// than warm ones, running out of gas fails the call without side effects, and
+// every charge is reported to the tracer.
+func TestStatefulPrecompileGasMetering(t *testing.T) {
+	var (
+		evm, statedb = newSmartDeFiTestEVM()
+		caller       = common.HexToAddress("0xc0ffee")
+		addr         = assetbacking.PrecompileAddressBytes
+		traced       uint64
+	)
+	evm.Config.Tracer = &tracing.Hooks{
+		OnGasChange: func(old, new uint64, reason tracing.GasChangeReason) {
+			if reason == tracing.GasChangeCallPrecompiledContract {
+				traced += old - new
+			}
+		},
+	}
+	statedb.AddBalance(caller, uint256.NewInt(params.Ether), tracing.BalanceChangeUnspecified)
+
+	// Creating a token with too little gas for its storage fails and consumes
+	// all gas, leaving no token behind
+	input := createTokenInput(t, caller, big.NewInt(0))
+	p, _ := evm.precompile(addr)
+	required := p.RequiredGas(input)
+	ret, left, err := evm.Call(caller, addr, input, required+10_000, new(uint256.Int))
+	if err != ErrOutOfGas {
+		t.Fatalf("expected out of gas, got %v", err)
+	}
+	if left != 0 || ret != nil {
+		t.Errorf("expected no gas and output left, have %d and %x", left, ret)
+	}
+	if statedb.GetNonce(addr) != 0 {
+		t.Errorf("token creation not reverted")
+	}
+
+	// With enough gas the storage is charged on top of the base cost
+	const supplied = 10_000_000
+	traced = 0
+	ret, left, err = evm.Call(caller, addr, input, supplied, new(uint256.Int))
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+	token := common.BytesToAddress(ret)
+	if used := supplied - left; used <= required+10_000 {
+		t.Errorf("expected storage to be charged, used %d gas", used)
+	}
+	if want := supplied - left; traced != want {
+		t.Errorf("traced gas mismatch: have %d, want %d", traced, want)
+	}
+
+	// In a new transaction, reading the same pool again only touches warm slots
+	statedb.Prepare(evm.chainRules, caller, common.Address{}, &addr, ActivePrecompiles(evm.chainRules), nil)
+	input, err = assetbacking.EncodeGetBacking(token, big.NewInt(1000))
+	if err != nil {
+		t.Fatalf("failed to encode input: %v", err)
+	}
+	var used [2]uint64
+	for i := range used {
+		_, left, err = evm.Call(caller, addr, input, supplied, new(uint256.Int))
+		if err != nil {
+			t.Fatalf("failed to get backing: %v", err)
+		}
+		used[i] = supplied - left
+	}
+	if used[1] >= used[0] {
+		t.Errorf("expected warm read to be cheaper: cold %d, warm %d", used[0], used[1])
+	}
+}
+
+// TestStatefulPrecompileOutOfGasStopsReads checks that a precompile iterating
+// over a long list in state stops reading once its gas is exhausted, instead of
+// walking the whole list uncharged.
+func TestStatefulPrecompileOutOfGasStopsReads(t *testing.T) {
+	var (
+		evm, statedb = newSmartDeFiTestEVM()
+		caller       = common.HexToAddress("0xc0ffee")
+		creator      = common.HexToAddress("0xc4ea70")
+		addr         = assetbacking.PrecompileAddressBytes
+		charges      int
+	)
+	evm.Config.Tracer = &tracing.Hooks{
+		OnGasChange: func(old, new uint64, reason tracing.GasChangeReason) {
+			if reason == tracing.GasChangeCallPrecompiledContract {
+				charges++
+			}
+		},
+	}
+	// Claim far more tokens than could ever be read, and store the first ones
+	const stored = 1000
+	length := assetbacking.CreatorTokensSlot(creator)
+	statedb.SetState(addr, length, common.BigToHash(new(big.Int).Lsh(big.NewInt(1), 40)))
+	data := crypto.Keccak256Hash(length.Bytes()).Big()
+	for i := int64(0); i < stored; i++ {
+		slot := common.BigToHash(new(big.Int).Add(data, big.NewInt(i)))
+		statedb.SetState(addr, slot, common.BytesToHash(common.BigToAddress(big.NewInt(i+1)).Bytes()))
+	}
+	input := append(slices.Clone(assetbacking.MethodIDTokensByCreator), common.LeftPadBytes(creator.Bytes(), 32)...)
+
+	// Enough gas for about a hundred cold reads
+	_, left, err := evm.Call(caller, addr, input, 250_000, new(uint256.Int))
+	if err != ErrOutOfGas {
+		t.Fatalf("expected out of gas, got %v", err)
+	}
+	if left != 0 {
+		t.Errorf("expected all gas to be consumed, have %d left", left)
+	}
+	if charges >= stored {
+		t.Errorf("expected reads to stop at the gas limit, charged %d", charges)
+	}
+}
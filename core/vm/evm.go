@@ -41,6 +41,9 @@ type (
 	GetHashFunc func(uint64) common.Hash
 )
 
+// precompile returns the precompiled contract active at addr, if any. Tokens
+// created by the SmartDeFi asset-backing precompile are not included, as they
+// are recognized by their code once a call has loaded it, see isSmartToken.
 func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
 	p, ok := evm.precompiles[addr]
 	return p, ok
@@ -280,21 +283,17 @@ func (evm *EVM) Call(caller common.Address, addr common.Address, input []byte, g
 		}
 		evm.StateDB.CreateAccount(addr)
 	}
-	evm.Context.Transfer(evm.StateDB, caller, addr, value)
+	evm.transfer(p, caller, addr, value)
 
 	if isPrecompile {
-		// Use stateful version for SmartDeFi precompile (0x0000000000000000000000000000000000000100)
-		if addr == common.HexToAddress("0x0000000000000000000000000000000000000100") {
-			// Pass caller and value context for SmartDeFi precompile
-			ret, gas, err = RunPrecompiledContractWithStateAndCaller(p, input, gas, evm.StateDB, caller, value.ToBig(), evm.Config.Tracer)
-		} else {
-			ret, gas, err = RunPrecompiledContract(p, input, gas, evm.Config.Tracer)
-		}
+		ret, gas, err = evm.runPrecompiledContract(p, CALL, caller, addr, input, gas, value)
 	} else {
 		// Initialise a new contract and set the code that is to be used by the EVM.
 		code := evm.resolveCode(addr)
 		if len(code) == 0 {
 			ret, err = nil, nil // gas is unchanged
+		} else if evm.isSmartToken(addr, code) {
+			ret, gas, err = evm.runPrecompiledContract(smartTokenContract, CALL, caller, addr, input, gas, value)
 		} else {
 			// The contract is a scoped environment for this execution context only.
 			contract := NewContract(caller, addr, value, gas, evm.jumpDests)
@@ -353,12 +352,14 @@ func (evm *EVM) CallCode(caller common.Address, addr common.Address, input []byt
 
 	// It is allowed to call precompiles, even via delegatecall
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
-		ret, gas, err = RunPrecompiledContract(p, input, gas, evm.Config.Tracer)
+		ret, gas, err = evm.runPrecompiledContract(p, CALLCODE, caller, addr, input, gas, value)
+	} else if code := evm.resolveCode(addr); evm.isSmartToken(addr, code) {
+		ret, gas, err = evm.runPrecompiledContract(smartTokenContract, CALLCODE, caller, addr, input, gas, value)
 	} else {
 		// Initialise a new contract and set the code that is to be used by the EVM.
 		// The contract is a scoped environment for this execution context only.
 		contract := NewContract(caller, caller, value, gas, evm.jumpDests)
-		contract.SetCallCode(evm.resolveCodeHash(addr), evm.resolveCode(addr))
+		contract.SetCallCode(evm.resolveCodeHash(addr), code)
 		ret, err = evm.Run(contract, input, false)
 		gas = contract.Gas
 	}
@@ -396,13 +397,15 @@ func (evm *EVM) DelegateCall(originCaller common.Address, caller common.Address,
 
 	// It is allowed to call precompiles, even via delegatecall
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
-		ret, gas, err = RunPrecompiledContract(p, input, gas, evm.Config.Tracer)
+		ret, gas, err = evm.runPrecompiledContract(p, DELEGATECALL, originCaller, addr, input, gas, value)
+	} else if code := evm.resolveCode(addr); evm.isSmartToken(addr, code) {
+		ret, gas, err = evm.runPrecompiledContract(smartTokenContract, DELEGATECALL, originCaller, addr, input, gas, value)
 	} else {
 		// Initialise a new contract and make initialise the delegate values
 		//
 		// Note: The value refers to the original value from the parent call.
 		contract := NewContract(originCaller, caller, value, gas, evm.jumpDests)
-		contract.SetCallCode(evm.resolveCodeHash(addr), evm.resolveCode(addr))
+		contract.SetCallCode(evm.resolveCodeHash(addr), code)
 		ret, err = evm.Run(contract, input, false)
 		gas = contract.Gas
 	}
@@ -448,12 +451,14 @@ func (evm *EVM) StaticCall(caller common.Address, addr common.Address, input []b
 	evm.StateDB.AddBalance(addr, new(uint256.Int), tracing.BalanceChangeTouchAccount)
 
 	if p, isPrecompile := evm.precompile(addr); isPrecompile {
-		ret, gas, err = RunPrecompiledContract(p, input, gas, evm.Config.Tracer)
+		ret, gas, err = evm.runPrecompiledContract(p, STATICCALL, caller, addr, input, gas, nil)
+	} else if code := evm.resolveCode(addr); evm.isSmartToken(addr, code) {
+		ret, gas, err = evm.runPrecompiledContract(smartTokenContract, STATICCALL, caller, addr, input, gas, nil)
 	} else {
 		// Initialise a new contract and set the code that is to be used by the EVM.
 		// The contract is a scoped environment for this execution context only.
 		contract := NewContract(caller, addr, new(uint256.Int), gas, evm.jumpDests)
-		contract.SetCallCode(evm.resolveCodeHash(addr), evm.resolveCode(addr))
+		contract.SetCallCode(evm.resolveCodeHash(addr), code)
 
 		// When an error was returned by the EVM or when setting the creation code
 		// above we revert to the snapshot and consume any gas remaining. Additionally
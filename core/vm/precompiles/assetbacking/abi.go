@@ -1,4 +1,4 @@
-// Package assetbacking - ABI definitions and encoding/decoding
+// Package assetbacking - ABI definitions and encoding/decoding.
 package assetbacking
 
 import (
@@ -8,9 +8,10 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
-// ABI definition for the asset backing precompile
+// ABI definition for the asset backing precompile.
 const PrecompileABI = `[
 	{
 		"inputs": [{
@@ -30,7 +31,56 @@ const PrecompileABI = `[
 		}],
 		"name": "createAssetBackedToken",
 		"outputs": [{"name": "tokenAddress", "type": "address"}],
-		"stateMutability": "nonpayable",
+		"stateMutability": "payable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "salt", "type": "bytes32"},
+			{
+				"components": [
+					{"name": "name", "type": "string"},
+					{"name": "symbol", "type": "string"},
+					{"name": "totalSupply", "type": "uint256"},
+					{"name": "backingAsset", "type": "address"},
+					{"name": "initialBacking", "type": "uint256"},
+					{"name": "fees", "type": "uint256[12]"},
+					{"name": "onlySB", "type": "bool"},
+					{"name": "owner", "type": "address"},
+					{"name": "enableLGE", "type": "bool"}
+				],
+				"name": "config",
+				"type": "tuple"
+			}
+		],
+		"name": "createAssetBackedTokenWithSalt",
+		"outputs": [{"name": "tokenAddress", "type": "address"}],
+		"stateMutability": "payable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "creator", "type": "address"},
+			{"name": "salt", "type": "bytes32"},
+			{
+				"components": [
+					{"name": "name", "type": "string"},
+					{"name": "symbol", "type": "string"},
+					{"name": "totalSupply", "type": "uint256"},
+					{"name": "backingAsset", "type": "address"},
+					{"name": "initialBacking", "type": "uint256"},
+					{"name": "fees", "type": "uint256[12]"},
+					{"name": "onlySB", "type": "bool"},
+					{"name": "owner", "type": "address"},
+					{"name": "enableLGE", "type": "bool"}
+				],
+				"name": "config",
+				"type": "tuple"
+			}
+		],
+		"name": "computeTokenAddress",
+		"outputs": [{"name": "tokenAddress", "type": "address"}],
+		"stateMutability": "view",
 		"type": "function"
 	},
 	{
@@ -53,17 +103,560 @@ const PrecompileABI = `[
 		"stateMutability": "nonpayable",
 		"type": "function"
 	},
+	{
+		"inputs": [
+			{"name": "token", "type": "address"},
+			{"name": "amount", "type": "uint256"}
+		],
+		"name": "buyFeeReserve",
+		"outputs": [{"name": "backing", "type": "uint256"}],
+		"stateMutability": "payable",
+		"type": "function"
+	},
 	{
 		"inputs": [{"name": "token", "type": "address"}],
 		"name": "getFloorPrice",
 		"outputs": [{"name": "floorPrice", "type": "uint256"}],
 		"stateMutability": "view",
 		"type": "function"
+	},
+	{
+		"inputs": [
+			{
+				"components": [
+					{"name": "name", "type": "string"},
+					{"name": "symbol", "type": "string"},
+					{"name": "totalSupply", "type": "uint256"},
+					{"name": "backingAsset", "type": "address"},
+					{"name": "initialBacking", "type": "uint256"},
+					{"name": "fees", "type": "uint256[12]"},
+					{"name": "onlySB", "type": "bool"},
+					{"name": "owner", "type": "address"},
+					{"name": "enableLGE", "type": "bool"}
+				],
+				"name": "config",
+				"type": "tuple"
+			},
+			{
+				"components": [
+					{"name": "deadline", "type": "uint64"},
+					{"name": "cap", "type": "uint256"},
+					{"name": "minimum", "type": "uint256"}
+				],
+				"name": "lge",
+				"type": "tuple"
+			}
+		],
+		"name": "createAssetBackedTokenWithLGE",
+		"outputs": [{"name": "tokenAddress", "type": "address"}],
+		"stateMutability": "payable",
+		"type": "function"
+	},
+	{
+		"inputs": [{"name": "token", "type": "address"}],
+		"name": "contribute",
+		"outputs": [],
+		"stateMutability": "payable",
+		"type": "function"
+	},
+	{
+		"inputs": [{"name": "token", "type": "address"}],
+		"name": "claim",
+		"outputs": [{"name": "tokens", "type": "uint256"}],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [{"name": "token", "type": "address"}],
+		"name": "refund",
+		"outputs": [{"name": "amount", "type": "uint256"}],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [{"name": "token", "type": "address"}],
+		"name": "reclaim",
+		"outputs": [{"name": "amount", "type": "uint256"}],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [{"name": "token", "type": "address"}],
+		"name": "getLGEInfo",
+		"outputs": [
+			{"name": "status", "type": "uint8"},
+			{"name": "deadline", "type": "uint64"},
+			{"name": "cap", "type": "uint256"},
+			{"name": "minimum", "type": "uint256"},
+			{"name": "raised", "type": "uint256"}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "token", "type": "address"},
+			{"name": "account", "type": "address"}
+		],
+		"name": "getContribution",
+		"outputs": [{"name": "amount", "type": "uint256"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "routerAdmin",
+		"outputs": [{"name": "admin", "type": "address"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [{"name": "newAdmin", "type": "address"}],
+		"name": "transferRouterAdmin",
+		"outputs": [],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [{"name": "router", "type": "address"}],
+		"name": "isRouter",
+		"outputs": [{"name": "allowed", "type": "bool"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "router", "type": "address"},
+			{"name": "allowed", "type": "bool"}
+		],
+		"name": "setRouter",
+		"outputs": [],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "tokenCount",
+		"outputs": [{"name": "count", "type": "uint256"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [{"name": "index", "type": "uint256"}],
+		"name": "tokenAt",
+		"outputs": [{"name": "token", "type": "address"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [{"name": "creator", "type": "address"}],
+		"name": "tokensByCreator",
+		"outputs": [{"name": "tokens", "type": "address[]"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [{"name": "token", "type": "address"}],
+		"name": "owner",
+		"outputs": [{"name": "owner", "type": "address"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "token", "type": "address"},
+			{"name": "newOwner", "type": "address"}
+		],
+		"name": "transferOwnership",
+		"outputs": [],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [{"name": "token", "type": "address"}],
+		"name": "renounceOwnership",
+		"outputs": [],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "token", "type": "address"},
+			{"name": "fees", "type": "uint256[12]"}
+		],
+		"name": "setFees",
+		"outputs": [],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "token", "type": "address"},
+			{"name": "onlySB", "type": "bool"}
+		],
+		"name": "setOnlySB",
+		"outputs": [],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "token", "type": "address"},
+			{"indexed": true, "name": "owner", "type": "address"},
+			{"indexed": false, "name": "name", "type": "string"},
+			{"indexed": false, "name": "symbol", "type": "string"},
+			{"indexed": false, "name": "supply", "type": "uint256"},
+			{"indexed": false, "name": "initialBacking", "type": "uint256"}
+		],
+		"name": "TokenCreated",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "token", "type": "address"},
+			{"indexed": true, "name": "account", "type": "address"},
+			{"indexed": false, "name": "burned", "type": "uint256"},
+			{"indexed": false, "name": "recovered", "type": "uint256"}
+		],
+		"name": "BackingRecovered",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "token", "type": "address"},
+			{"indexed": false, "name": "amount", "type": "uint256"}
+		],
+		"name": "BackingAdded",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "token", "type": "address"},
+			{"indexed": true, "name": "previousOwner", "type": "address"},
+			{"indexed": true, "name": "newOwner", "type": "address"}
+		],
+		"name": "OwnershipTransferred",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "token", "type": "address"},
+			{"indexed": false, "name": "fees", "type": "uint256[12]"}
+		],
+		"name": "FeesUpdated",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "token", "type": "address"},
+			{"indexed": false, "name": "onlySB", "type": "bool"}
+		],
+		"name": "OnlySBUpdated",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "token", "type": "address"},
+			{"indexed": true, "name": "contributor", "type": "address"},
+			{"indexed": false, "name": "amount", "type": "uint256"}
+		],
+		"name": "LGEContribution",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "token", "type": "address"},
+			{"indexed": true, "name": "account", "type": "address"},
+			{"indexed": false, "name": "tokens", "type": "uint256"}
+		],
+		"name": "LGEClaimed",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "token", "type": "address"},
+			{"indexed": true, "name": "account", "type": "address"},
+			{"indexed": false, "name": "amount", "type": "uint256"}
+		],
+		"name": "LGERefunded",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "previousAdmin", "type": "address"},
+			{"indexed": true, "name": "newAdmin", "type": "address"}
+		],
+		"name": "RouterAdminTransferred",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "router", "type": "address"},
+			{"indexed": false, "name": "allowed", "type": "bool"}
+		],
+		"name": "RouterUpdated",
+		"type": "event"
+	},
+	{
+		"inputs": [{"name": "caller", "type": "address"}],
+		"name": "NotOwner",
+		"type": "error"
+	},
+	{
+		"inputs": [{"name": "caller", "type": "address"}],
+		"name": "NotRouterAdmin",
+		"type": "error"
+	},
+	{
+		"inputs": [
+			{"name": "token", "type": "address"},
+			{"name": "account", "type": "address"}
+		],
+		"name": "OnlySBViolation",
+		"type": "error"
+	},
+	{
+		"inputs": [
+			{"name": "index", "type": "uint256"},
+			{"name": "count", "type": "uint256"}
+		],
+		"name": "IndexOutOfBounds",
+		"type": "error"
+	},
+	{
+		"inputs": [
+			{"name": "token", "type": "address"},
+			{"name": "status", "type": "uint8"}
+		],
+		"name": "InvalidLGEStatus",
+		"type": "error"
+	},
+	{
+		"inputs": [{"name": "remaining", "type": "uint256"}],
+		"name": "LGECapExceeded",
+		"type": "error"
+	},
+	{
+		"inputs": [{"name": "token", "type": "address"}],
+		"name": "TransfersLocked",
+		"type": "error"
+	},
+	{
+		"inputs": [{"name": "account", "type": "address"}],
+		"name": "NothingToClaim",
+		"type": "error"
+	},
+	{
+		"inputs": [],
+		"name": "InvalidInput",
+		"type": "error"
+	},
+	{
+		"inputs": [],
+		"name": "StaticCallViolation",
+		"type": "error"
+	},
+	{
+		"inputs": [{"name": "value", "type": "uint256"}],
+		"name": "NonPayable",
+		"type": "error"
+	},
+	{
+		"inputs": [
+			{"name": "value", "type": "uint256"},
+			{"name": "initialBacking", "type": "uint256"}
+		],
+		"name": "ValueMismatch",
+		"type": "error"
+	},
+	{
+		"inputs": [
+			{"name": "value", "type": "uint256"},
+			{"name": "cost", "type": "uint256"}
+		],
+		"name": "InsufficientPayment",
+		"type": "error"
+	},
+	{
+		"inputs": [],
+		"name": "ZeroAddress",
+		"type": "error"
+	},
+	{
+		"inputs": [],
+		"name": "InvalidTokenConfig",
+		"type": "error"
+	},
+	{
+		"inputs": [],
+		"name": "InvalidFees",
+		"type": "error"
+	},
+	{
+		"inputs": [{"name": "asset", "type": "address"}],
+		"name": "UnsupportedBackingAsset",
+		"type": "error"
+	},
+	{
+		"inputs": [{"name": "token", "type": "address"}],
+		"name": "TokenExists",
+		"type": "error"
+	},
+	{
+		"inputs": [{"name": "token", "type": "address"}],
+		"name": "PoolNotFound",
+		"type": "error"
+	},
+	{
+		"inputs": [
+			{"name": "amount", "type": "uint256"},
+			{"name": "max", "type": "uint256"}
+		],
+		"name": "InvalidAmount",
+		"type": "error"
+	},
+	{
+		"inputs": [
+			{"name": "account", "type": "address"},
+			{"name": "balance", "type": "uint256"},
+			{"name": "needed", "type": "uint256"}
+		],
+		"name": "InsufficientBalance",
+		"type": "error"
+	},
+	{
+		"inputs": [
+			{"name": "spender", "type": "address"},
+			{"name": "allowance", "type": "uint256"},
+			{"name": "needed", "type": "uint256"}
+		],
+		"name": "InsufficientAllowance",
+		"type": "error"
+	},
+	{
+		"inputs": [
+			{"name": "available", "type": "uint256"},
+			{"name": "needed", "type": "uint256"}
+		],
+		"name": "InsufficientBacking",
+		"type": "error"
+	}
+]`
+
+// ABI definition of the ERC-20 interface served at every created token address.
+const TokenABI = `[
+	{
+		"inputs": [],
+		"name": "name",
+		"outputs": [{"name": "", "type": "string"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "symbol",
+		"outputs": [{"name": "", "type": "string"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "decimals",
+		"outputs": [{"name": "", "type": "uint8"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "totalSupply",
+		"outputs": [{"name": "", "type": "uint256"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [{"name": "account", "type": "address"}],
+		"name": "balanceOf",
+		"outputs": [{"name": "", "type": "uint256"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "to", "type": "address"},
+			{"name": "value", "type": "uint256"}
+		],
+		"name": "transfer",
+		"outputs": [{"name": "", "type": "bool"}],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "owner", "type": "address"},
+			{"name": "spender", "type": "address"}
+		],
+		"name": "allowance",
+		"outputs": [{"name": "", "type": "uint256"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "spender", "type": "address"},
+			{"name": "value", "type": "uint256"}
+		],
+		"name": "approve",
+		"outputs": [{"name": "", "type": "bool"}],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "from", "type": "address"},
+			{"name": "to", "type": "address"},
+			{"name": "value", "type": "uint256"}
+		],
+		"name": "transferFrom",
+		"outputs": [{"name": "", "type": "bool"}],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "from", "type": "address"},
+			{"indexed": true, "name": "to", "type": "address"},
+			{"indexed": false, "name": "value", "type": "uint256"}
+		],
+		"name": "Transfer",
+		"type": "event"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "owner", "type": "address"},
+			{"indexed": true, "name": "spender", "type": "address"},
+			{"indexed": false, "name": "value", "type": "uint256"}
+		],
+		"name": "Approval",
+		"type": "event"
 	}
 ]`
 
 var (
 	precompileABI abi.ABI
+	tokenABI      abi.ABI
 )
 
 func init() {
@@ -72,14 +665,23 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+	tokenABI, err = abi.JSON(bytes.NewReader([]byte(TokenABI)))
+	if err != nil {
+		panic(err)
+	}
 }
 
-// EncodeCreateToken encodes the createAssetBackedToken call
+// EncodeCreateToken encodes the createAssetBackedToken call.
 func EncodeCreateToken(config TokenConfig) ([]byte, error) {
 	return precompileABI.Pack("createAssetBackedToken", config)
 }
 
-// DecodeCreateTokenInput decodes the createAssetBackedToken input (parameters only, no method ID)
+// EncodeCreateTokenWithLGE encodes the createAssetBackedTokenWithLGE call.
+func EncodeCreateTokenWithLGE(config TokenConfig, lge LGEConfig) ([]byte, error) {
+	return precompileABI.Pack("createAssetBackedTokenWithLGE", config, lge)
+}
+
+// DecodeCreateTokenInput decodes the createAssetBackedToken input (parameters only, no method ID).
 func DecodeCreateTokenInput(input []byte) (TokenConfig, error) {
 	var config TokenConfig
 	method := precompileABI.Methods["createAssetBackedToken"]
@@ -87,16 +689,19 @@ func DecodeCreateTokenInput(input []byte) (TokenConfig, error) {
 	if err != nil {
 		return config, err
 	}
-	// Unpack the tuple into the struct
-	return config, method.Inputs.Copy(&config, values)
+	if len(values) < 1 {
+		return config, errors.New("insufficient values")
+	}
+	// Convert the anonymous tuple struct into TokenConfig
+	return *abi.ConvertType(values[0], new(TokenConfig)).(*TokenConfig), nil
 }
 
-// EncodeGetBacking encodes the getBacking call
+// EncodeGetBacking encodes the getBacking call.
 func EncodeGetBacking(token common.Address, amount *big.Int) ([]byte, error) {
 	return precompileABI.Pack("getBacking", token, amount)
 }
 
-// DecodeGetBackingInput decodes the getBacking input (parameters only, no method ID)
+// DecodeGetBackingInput decodes the getBacking input (parameters only, no method ID).
 func DecodeGetBackingInput(input []byte) (common.Address, *big.Int, error) {
 	method := precompileABI.Methods["getBacking"]
 	values, err := method.Inputs.Unpack(input)
@@ -114,12 +719,12 @@ func DecodeGetBackingInput(input []byte) (common.Address, *big.Int, error) {
 	return token, amount, nil
 }
 
-// EncodeBurnAndRecover encodes the burnAndRecover call
+// EncodeBurnAndRecover encodes the burnAndRecover call.
 func EncodeBurnAndRecover(token common.Address, amount *big.Int) ([]byte, error) {
 	return precompileABI.Pack("burnAndRecover", token, amount)
 }
 
-// DecodeBurnAndRecoverInput decodes the burnAndRecover input (parameters only, no method ID)
+// DecodeBurnAndRecoverInput decodes the burnAndRecover input (parameters only, no method ID).
 func DecodeBurnAndRecoverInput(input []byte) (common.Address, *big.Int, error) {
 	method := precompileABI.Methods["burnAndRecover"]
 	values, err := method.Inputs.Unpack(input)
@@ -137,12 +742,12 @@ func DecodeBurnAndRecoverInput(input []byte) (common.Address, *big.Int, error) {
 	return token, amount, nil
 }
 
-// EncodeGetFloorPrice encodes the getFloorPrice call
+// EncodeGetFloorPrice encodes the getFloorPrice call.
 func EncodeGetFloorPrice(token common.Address) ([]byte, error) {
 	return precompileABI.Pack("getFloorPrice", token)
 }
 
-// DecodeGetFloorPriceInput decodes the getFloorPrice input (parameters only, no method ID)
+// DecodeGetFloorPriceInput decodes the getFloorPrice input (parameters only, no method ID).
 func DecodeGetFloorPriceInput(input []byte) (common.Address, error) {
 	method := precompileABI.Methods["getFloorPrice"]
 	values, err := method.Inputs.Unpack(input)
@@ -159,7 +764,7 @@ func DecodeGetFloorPriceInput(input []byte) (common.Address, error) {
 	return token, nil
 }
 
-// EncodeOutput encodes function output
+// EncodeOutput encodes function output.
 func EncodeOutput(method string, output interface{}) ([]byte, error) {
 	methodObj, ok := precompileABI.Methods[method]
 	if !ok {
@@ -168,3 +773,74 @@ func EncodeOutput(method string, output interface{}) ([]byte, error) {
 	return methodObj.Outputs.Pack(output)
 }
 
+// EncodeEvent encodes an event of the precompile into its topics and data.
+func EncodeEvent(name string, args ...interface{}) ([]common.Hash, []byte, error) {
+	event, ok := precompileABI.Events[name]
+	if !ok {
+		return nil, nil, errors.New("event not found")
+	}
+	if len(args) != len(event.Inputs) {
+		return nil, nil, errors.New("argument count mismatch")
+	}
+	// Split the arguments into topics and data
+	var indexed [][]interface{}
+	var nonIndexed []interface{}
+	for i, input := range event.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, []interface{}{args[i]})
+		} else {
+			nonIndexed = append(nonIndexed, args[i])
+		}
+	}
+	topics, err := abi.MakeTopics(indexed...)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := event.Inputs.NonIndexed().Pack(nonIndexed...)
+	if err != nil {
+		return nil, nil, err
+	}
+	hashes := []common.Hash{event.ID}
+	for _, topic := range topics {
+		hashes = append(hashes, topic[0])
+	}
+	return hashes, data, nil
+}
+
+// poolEvents are the events emitted whenever the backing pool of the token in
+// their first topic changes.
+var poolEvents = []string{"TokenCreated", "BackingAdded", "BackingRecovered", "LGERefunded"}
+
+// ChangedPools returns the tokens whose backing pool is changed by the events
+// in logs, in order of first change. Besides the events of the precompile,
+// burns reported by a Transfer to the zero address change the circulating
+// supply of the pool; as any ERC-20 contract may emit them, callers must skip
+// addresses without a pool.
+func ChangedPools(logs []*types.Log) []common.Address {
+	var tokens []common.Address
+	seen := make(map[common.Address]bool)
+	add := func(token common.Address) {
+		if !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+	for _, log := range logs {
+		if log.Address != PrecompileAddressBytes {
+			if len(log.Topics) == 3 && log.Topics[0] == tokenABI.Events["Transfer"].ID && log.Topics[2] == (common.Hash{}) {
+				add(log.Address)
+			}
+			continue
+		}
+		if len(log.Topics) < 2 {
+			continue
+		}
+		for _, name := range poolEvents {
+			if log.Topics[0] == precompileABI.Events[name].ID {
+				add(common.BytesToAddress(log.Topics[1].Bytes()))
+				break
+			}
+		}
+	}
+	return tokens
+}
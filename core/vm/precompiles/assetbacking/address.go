@@ -0,0 +1,94 @@
+// Package assetbacking - deterministic token addresses.
+package assetbacking
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TokenAddress returns the address of the token created by creator with the
+// given salt and configuration. It is derived like a CREATE2 address, with the
+// hash of the ABI-encoded configuration in place of the init code hash:
+//
+//	keccak256(0xff ++ precompile ++ creator ++ salt ++ keccak256(abi.encode(config)))[12:].
+func TokenAddress(creator common.Address, salt common.Hash, config TokenConfig) (common.Address, error) {
+	encoded, err := precompileABI.Methods["createAssetBackedToken"].Inputs.Pack(config)
+	if err != nil {
+		return common.Address{}, err
+	}
+	hash := crypto.Keccak256(
+		[]byte{0xff},
+		PrecompileAddressBytes.Bytes(),
+		creator.Bytes(),
+		salt.Bytes(),
+		crypto.Keccak256(encoded),
+	)
+	return common.BytesToAddress(hash[12:]), nil
+}
+
+// DefaultTokenSalt returns the salt of a token created without a salt by a
+// creator which created count tokens before. The count is hashed with a tag, so
+// that the salts never coincide with small salts chosen by the creator, such as
+// bytes32(1), which would make later unsalted creations revert.
+func DefaultTokenSalt(count uint64) common.Hash {
+	return crypto.Keccak256Hash([]byte("SmartDeFi-DefaultTokenSalt"), common.BigToHash(new(big.Int).SetUint64(count)).Bytes())
+}
+
+// defaultTokenSalt returns the salt of the next token created by creator
+// without a salt.
+func defaultTokenSalt(stateDB StateDB, creator common.Address) common.Hash {
+	return DefaultTokenSalt(stateDB.GetState(PrecompileAddressBytes, CreatorTokensSlot(creator)).Big().Uint64())
+}
+
+// EncodeCreateTokenWithSalt encodes the createAssetBackedTokenWithSalt call.
+func EncodeCreateTokenWithSalt(salt common.Hash, config TokenConfig) ([]byte, error) {
+	return precompileABI.Pack("createAssetBackedTokenWithSalt", salt, config)
+}
+
+// decodeSaltedConfig decodes the salt and token configuration arguments of
+// method, which follow the given number of other arguments.
+func decodeSaltedConfig(method string, input []byte, skip int) ([]interface{}, common.Hash, TokenConfig, error) {
+	args, err := decodeInput(method, input)
+	if err != nil {
+		return nil, common.Hash{}, TokenConfig{}, err
+	}
+	if len(args) != skip+2 {
+		return nil, common.Hash{}, TokenConfig{}, revert("InvalidInput")
+	}
+	salt, ok := args[skip].([32]byte)
+	if !ok {
+		return nil, common.Hash{}, TokenConfig{}, revert("InvalidInput")
+	}
+	config, ok := abi.ConvertType(args[skip+1], new(TokenConfig)).(*TokenConfig)
+	if !ok {
+		return nil, common.Hash{}, TokenConfig{}, revert("InvalidInput")
+	}
+	return args[:skip], salt, *config, nil
+}
+
+// createAssetBackedTokenWithSalt creates a token at the address derived from
+// the salt chosen by the caller.
+func (p *Precompile) createAssetBackedTokenWithSalt(ctx CallContext, input []byte) ([]byte, error) {
+	_, salt, config, err := decodeSaltedConfig("createAssetBackedTokenWithSalt", input, 0)
+	if err != nil {
+		return nil, err
+	}
+	return p.createToken(ctx, salt, config, nil)
+}
+
+// computeTokenAddress returns the address of the token a creator would create
+// with the given salt and configuration.
+func (p *Precompile) computeTokenAddress(ctx CallContext, input []byte) ([]byte, error) {
+	args, salt, config, err := decodeSaltedConfig("computeTokenAddress", input, 1)
+	if err != nil {
+		return nil, err
+	}
+	token, err := TokenAddress(args[0].(common.Address), salt, config)
+	if err != nil {
+		return nil, revert("InvalidInput")
+	}
+	return EncodeOutput("computeTokenAddress", token)
+}
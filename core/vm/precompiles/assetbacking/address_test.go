@@ -0,0 +1,115 @@
+// Package assetbacking - Tests for deterministic token addresses.
+package assetbacking
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+)
+
+// TestTokenAddress tests that tokens are created at the address previewed by
+// computeTokenAddress, and that creations never collide within a transaction.
+func TestTokenAddress(t *testing.T) {
+	stateDB := newMockStateDB()
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	alice := common.HexToAddress("0xa11ce")
+	salt := common.HexToHash("0x5a17")
+
+	fees := [12]*big.Int{}
+	for i := range fees {
+		fees[i] = new(big.Int)
+	}
+	config := TokenConfig{
+		Name:           "Salted",
+		Symbol:         "SLT",
+		TotalSupply:    big.NewInt(1000),
+		InitialBacking: new(big.Int),
+		Fees:           fees,
+		Owner:          owner,
+	}
+
+	// The address follows the CREATE2 derivation over the encoded config
+	encoded, err := precompileABI.Methods["createAssetBackedToken"].Inputs.Pack(config)
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	want := common.BytesToAddress(crypto.Keccak256([]byte{0xff}, PrecompileAddressBytes.Bytes(), alice.Bytes(), salt.Bytes(), crypto.Keccak256(encoded))[12:])
+	if have, err := TokenAddress(alice, salt, config); err != nil || have != want {
+		t.Fatalf("Expected token address %s, got %s (%v)", want.Hex(), have.Hex(), err)
+	}
+
+	// The view previews the address, also within a static call
+	input, err := precompileABI.Pack("computeTokenAddress", alice, salt, config)
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	result, err := (&Precompile{}).RunStateful(CallContext{StateDB: stateDB, Caller: owner, ReadOnly: true}, input)
+	if err != nil || common.BytesToAddress(result) != want {
+		t.Fatalf("Expected preview %s, got %x (%v)", want.Hex(), result, err)
+	}
+	// Salted creation deploys the token at the previewed address
+	out, err := lgeCall(t, stateDB, alice, 0, 0, "createAssetBackedTokenWithSalt", salt, config)
+	if err != nil || out[0].(common.Address) != want {
+		t.Fatalf("Expected token at %s, got %v (%v)", want.Hex(), out, err)
+	}
+	if !IsToken(stateDB.GetCode(want)) {
+		t.Errorf("Expected token code at %s", want.Hex())
+	}
+	// Reusing the salt with the same config collides, another salt does not
+	_, err = lgeCall(t, stateDB, alice, 0, 0, "createAssetBackedTokenWithSalt", salt, config)
+	expectRevertName(t, err, "TokenExists")
+
+	out, err = lgeCall(t, stateDB, alice, 0, 0, "createAssetBackedTokenWithSalt", common.HexToHash("0x5a18"), config)
+	if err != nil || out[0].(common.Address) == want {
+		t.Errorf("Expected a new token for another salt, got %v (%v)", out, err)
+	}
+	// The same salt of another creator does not collide either
+	out, err = lgeCall(t, stateDB, owner, 0, 0, "createAssetBackedTokenWithSalt", salt, config)
+	if err != nil || out[0].(common.Address) == want {
+		t.Errorf("Expected a new token for another creator, got %v (%v)", out, err)
+	}
+
+	// Unsalted creations are salted with the creator's token count, so equal
+	// creations in one transaction get distinct addresses
+	bob := common.HexToAddress("0xb0b")
+	seen := make(map[common.Address]bool)
+	for i := uint64(0); i < 3; i++ {
+		want, _ := TokenAddress(bob, DefaultTokenSalt(i), config)
+		out, err := lgeCall(t, stateDB, bob, 0, 0, "createAssetBackedToken", config)
+		if err != nil {
+			t.Fatalf("Failed to create token %d: %v", i, err)
+		}
+		if token := out[0].(common.Address); token != want || seen[token] {
+			t.Errorf("Expected token %d at %s, got %s", i, want.Hex(), token.Hex())
+		}
+		seen[out[0].(common.Address)] = true
+	}
+
+	// An explicit salt equal to the next token count does not collide with the
+	// following unsalted creations
+	carol := common.HexToAddress("0xca201")
+	if _, err := lgeCall(t, stateDB, carol, 0, 0, "createAssetBackedTokenWithSalt", common.BigToHash(common.Big1), config); err != nil {
+		t.Fatalf("Failed to create token with salt 1: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := lgeCall(t, stateDB, carol, 0, 0, "createAssetBackedToken", config); err != nil {
+			t.Fatalf("Failed to create unsalted token after salted ones: %v", err)
+		}
+	}
+
+	// Salted creation is payable like unsalted creation
+	config.InitialBacking = big.NewInt(500)
+	out, err = lgeCall(t, stateDB, alice, 0, 500, "createAssetBackedTokenWithSalt", salt, config)
+	if err != nil {
+		t.Fatalf("Failed to create backed token: %v", err)
+	}
+	if pool := backingpool.GetBackingPool(stateDB, out[0].(common.Address)); pool.TotalBacking.Cmp(big.NewInt(500)) != 0 {
+		t.Errorf("Expected backing of 500, got %s", pool.TotalBacking)
+	}
+	_, err = (&Precompile{}).RunStateful(CallContext{StateDB: stateDB, Caller: alice, Value: uint256.NewInt(1)}, input)
+	expectRevertName(t, err, "NonPayable")
+}
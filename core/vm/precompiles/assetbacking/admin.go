@@ -0,0 +1,125 @@
+// Package assetbacking - owner administration of created tokens.
+package assetbacking
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+)
+
+// owner returns the owner of a token, the zero address once renounced.
+func (p *Precompile) owner(ctx CallContext, input []byte) ([]byte, error) {
+	args, err := decodeInput("owner", input)
+	if err != nil {
+		return nil, err
+	}
+	token := args[0].(common.Address)
+	if backingpool.GetBackingPool(ctx.StateDB, token) == nil {
+		return nil, revert("PoolNotFound", token)
+	}
+	return EncodeOutput("owner", TokenOwner(ctx.StateDB, token))
+}
+
+// transferOwnership hands the administration of a token to a new owner.
+func (p *Precompile) transferOwnership(ctx CallContext, input []byte) ([]byte, error) {
+	args, err := decodeInput("transferOwnership", input)
+	if err != nil {
+		return nil, err
+	}
+	token, newOwner := args[0].(common.Address), args[1].(common.Address)
+	if newOwner == (common.Address{}) {
+		return nil, revert("ZeroAddress") // Use renounceOwnership instead
+	}
+	return nil, setTokenOwner(ctx, token, newOwner)
+}
+
+// renounceOwnership leaves a token without owner, freezing its fee schedule.
+func (p *Precompile) renounceOwnership(ctx CallContext, input []byte) ([]byte, error) {
+	args, err := decodeInput("renounceOwnership", input)
+	if err != nil {
+		return nil, err
+	}
+	return nil, setTokenOwner(ctx, args[0].(common.Address), common.Address{})
+}
+
+// setFees replaces the fee schedule of a token, subject to the same limits as
+// at creation.
+func (p *Precompile) setFees(ctx CallContext, input []byte) ([]byte, error) {
+	args, err := decodeInput("setFees", input)
+	if err != nil {
+		return nil, err
+	}
+	token, fees := args[0].(common.Address), args[1].([12]*big.Int)
+	if err := onlyOwner(ctx, token); err != nil {
+		return nil, err
+	}
+	if err := validateFees(fees); err != nil {
+		return nil, err
+	}
+	_, onlySB := loadFeeStructure(ctx.StateDB, token)
+	storeFeeStructure(ctx.StateDB, token, fees, onlySB)
+
+	if err := emitEvent(ctx, "FeesUpdated", token, fees); err != nil {
+		return nil, ErrExecutionReverted
+	}
+	return nil, nil
+}
+
+// setOnlySB updates the OnlySB flag of a token.
+func (p *Precompile) setOnlySB(ctx CallContext, input []byte) ([]byte, error) {
+	args, err := decodeInput("setOnlySB", input)
+	if err != nil {
+		return nil, err
+	}
+	token, onlySB := args[0].(common.Address), args[1].(bool)
+	if err := onlyOwner(ctx, token); err != nil {
+		return nil, err
+	}
+	fees, _ := loadFeeStructure(ctx.StateDB, token)
+	storeFeeStructure(ctx.StateDB, token, fees, onlySB)
+
+	if err := emitEvent(ctx, "OnlySBUpdated", token, onlySB); err != nil {
+		return nil, ErrExecutionReverted
+	}
+	return nil, nil
+}
+
+// setTokenOwner replaces the owner of a token on behalf of its current owner.
+func setTokenOwner(ctx CallContext, token, newOwner common.Address) error {
+	if err := onlyOwner(ctx, token); err != nil {
+		return err
+	}
+	previous := TokenOwner(ctx.StateDB, token)
+	ctx.StateDB.SetState(token, slotTokenOwner, common.BytesToHash(newOwner.Bytes()))
+
+	if err := emitEvent(ctx, "OwnershipTransferred", token, previous, newOwner); err != nil {
+		return ErrExecutionReverted
+	}
+	return nil
+}
+
+// onlyOwner checks that the caller may administer a token.
+func onlyOwner(ctx CallContext, token common.Address) error {
+	if ctx.ReadOnly {
+		return revert("StaticCallViolation")
+	}
+	if backingpool.GetBackingPool(ctx.StateDB, token) == nil {
+		return revert("PoolNotFound", token)
+	}
+	// A renounced token has the zero owner and cannot be administered
+	if owner := TokenOwner(ctx.StateDB, token); owner != ctx.Caller || owner == (common.Address{}) {
+		return revert("NotOwner", ctx.Caller)
+	}
+	return nil
+}
+
+// decodeInput decodes the arguments of a method of the precompile (parameters
+// only, no method ID).
+func decodeInput(method string, input []byte) ([]interface{}, error) {
+	args, err := precompileABI.Methods[method].Inputs.Unpack(input)
+	if err != nil {
+		return nil, revert("InvalidInput")
+	}
+	return args, nil
+}
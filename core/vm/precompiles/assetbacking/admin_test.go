@@ -0,0 +1,128 @@
+// Package assetbacking - Tests for the owner administration of created tokens.
+package assetbacking
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestOwnerAdministration tests that only the owner of a token may update its
+// fees and OnlySB flag or hand over the ownership, and that every change is
+// announced by an event.
+func TestOwnerAdministration(t *testing.T) {
+	stateDB := newMockStateDB()
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	alice := common.HexToAddress("0xa11ce")
+	token := createTestToken(t, stateDB, owner, big.NewInt(1000))
+
+	call := func(caller common.Address, method string, args ...interface{}) ([]byte, error) {
+		t.Helper()
+		input, err := precompileABI.Pack(method, args...)
+		if err != nil {
+			t.Fatalf("Failed to encode %s: %v", method, err)
+		}
+		return (&Precompile{}).RunStateful(CallContext{StateDB: stateDB, Caller: caller}, input)
+	}
+	expectRevert := func(err error, name string) {
+		t.Helper()
+		var revertErr *RevertError
+		if !errors.As(err, &revertErr) || revertErr.Name() != name {
+			t.Errorf("Expected %s, got %v", name, err)
+		}
+	}
+	ownerOf := func() common.Address {
+		t.Helper()
+		ret, err := call(alice, "owner", token)
+		if err != nil {
+			t.Fatalf("Failed to get owner: %v", err)
+		}
+		return common.BytesToAddress(ret)
+	}
+	lastEvent := func() string {
+		log := stateDB.logs[len(stateDB.logs)-1]
+		event, err := precompileABI.EventByID(log.Topics[0])
+		if err != nil {
+			return ""
+		}
+		return event.Name
+	}
+	fees := [12]*big.Int{}
+	for i := range fees {
+		fees[i] = big.NewInt(0)
+	}
+	fees[FeeBacking] = big.NewInt(30)
+	fees[FeeSellOffset+FeeTreasury] = big.NewInt(40)
+
+	if have := ownerOf(); have != owner {
+		t.Fatalf("Expected owner %s, got %s", owner.Hex(), have.Hex())
+	}
+
+	// Non-owners and unknown tokens are rejected
+	_, err := call(alice, "setFees", token, fees)
+	expectRevert(err, "NotOwner")
+	_, err = call(alice, "setOnlySB", token, true)
+	expectRevert(err, "NotOwner")
+	_, err = call(alice, "transferOwnership", token, alice)
+	expectRevert(err, "NotOwner")
+	_, err = call(owner, "setFees", alice, fees)
+	expectRevert(err, "PoolNotFound")
+
+	// The fee cap of the creation still applies
+	excessive := fees
+	excessive[FeeLiquidity] = big.NewInt(471)
+	_, err = call(owner, "setFees", token, excessive)
+	expectRevert(err, "InvalidFees")
+
+	// The owner updates the fees and OnlySB flag
+	if _, err := call(owner, "setFees", token, fees); err != nil {
+		t.Fatalf("Failed to set fees: %v", err)
+	}
+	if lastEvent() != "FeesUpdated" {
+		t.Errorf("Expected FeesUpdated event, got %s", lastEvent())
+	}
+	if _, err := call(owner, "setOnlySB", token, true); err != nil {
+		t.Fatalf("Failed to set OnlySB: %v", err)
+	}
+	if lastEvent() != "OnlySBUpdated" {
+		t.Errorf("Expected OnlySBUpdated event, got %s", lastEvent())
+	}
+	stored, onlySB := loadFeeStructure(stateDB, token)
+	for i := range fees {
+		if stored[i].Cmp(fees[i]) != 0 {
+			t.Errorf("Expected fee %d to be %s, got %s", i, fees[i], stored[i])
+		}
+	}
+	if !onlySB {
+		t.Error("Expected OnlySB to be set")
+	}
+
+	// Ownership is handed over to alice, and the previous owner loses control
+	_, err = call(owner, "transferOwnership", token, common.Address{})
+	expectRevert(err, "ZeroAddress")
+	if _, err := call(owner, "transferOwnership", token, alice); err != nil {
+		t.Fatalf("Failed to transfer ownership: %v", err)
+	}
+	if lastEvent() != "OwnershipTransferred" {
+		t.Errorf("Expected OwnershipTransferred event, got %s", lastEvent())
+	}
+	if have := ownerOf(); have != alice {
+		t.Errorf("Expected owner %s, got %s", alice.Hex(), have.Hex())
+	}
+	_, err = call(owner, "setOnlySB", token, false)
+	expectRevert(err, "NotOwner")
+
+	// Renouncing leaves the token without administrator
+	if _, err := call(alice, "renounceOwnership", token); err != nil {
+		t.Fatalf("Failed to renounce ownership: %v", err)
+	}
+	if have := ownerOf(); have != (common.Address{}) {
+		t.Errorf("Expected no owner, got %s", have.Hex())
+	}
+	_, err = call(alice, "setOnlySB", token, false)
+	expectRevert(err, "NotOwner")
+	_, err = call(common.Address{}, "setOnlySB", token, false)
+	expectRevert(err, "NotOwner")
+}
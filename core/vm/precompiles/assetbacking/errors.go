@@ -0,0 +1,75 @@
+// Package assetbacking - custom errors returned as revert data.
+package assetbacking
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RevertError is a custom error of PrecompileABI, such as PoolNotFound(token).
+// The EVM returns its ABI encoding as the revert data of the call. It matches
+// ErrExecutionReverted, so callers not interested in the reason can keep
+// comparing against that.
+type RevertError struct {
+	name string
+	data []byte
+}
+
+// revert returns the custom error name of PrecompileABI with the given arguments.
+func revert(name string, args ...interface{}) *RevertError {
+	e, ok := precompileABI.Errors[name]
+	if !ok {
+		panic("assetbacking: unknown error " + name)
+	}
+	data := append([]byte{}, e.ID[:4]...)
+
+	// The arguments are fixed by the callers, so packing only fails if they do
+	// not match the ABI. Fall back to the bare selector rather than failing the call
+	if packed, err := e.Inputs.Pack(args...); err == nil {
+		data = append(data, packed...)
+	}
+	return &RevertError{name: name, data: data}
+}
+
+// Error implements the error interface.
+func (e *RevertError) Error() string {
+	return ErrExecutionReverted.Error() + ": " + e.name
+}
+
+// Unwrap makes the error match ErrExecutionReverted.
+func (e *RevertError) Unwrap() error {
+	return ErrExecutionReverted
+}
+
+// Name returns the name of the error in PrecompileABI.
+func (e *RevertError) Name() string {
+	return e.name
+}
+
+// Data returns the ABI encoded error, the revert data of the call.
+func (e *RevertError) Data() []byte {
+	return e.data
+}
+
+// UnpackRevert decodes revert data holding a custom error of PrecompileABI into
+// a human readable form, e.g. "InsufficientBacking(100, 250)".
+func UnpackRevert(data []byte) (string, error) {
+	if len(data) < 4 {
+		return "", errors.New("invalid data for unpacking")
+	}
+	e, err := precompileABI.ErrorByID([4]byte(data[:4]))
+	if err != nil {
+		return "", err
+	}
+	unpacked, err := e.Unpack(data)
+	if err != nil {
+		return "", err
+	}
+	values, _ := unpacked.([]interface{})
+	args := make([]string, len(values))
+	for i, value := range values {
+		args[i] = fmt.Sprint(value)
+	}
+	return e.Name + "(" + strings.Join(args, ", ") + ")", nil
+}
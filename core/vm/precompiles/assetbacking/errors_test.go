@@ -0,0 +1,82 @@
+// Package assetbacking - Tests for the custom errors of the precompile.
+package assetbacking
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+)
+
+// TestRevertErrors tests that failing calls return the custom error of the
+// failure, which unpacks to a readable reason and still matches
+// ErrExecutionReverted.
+func TestRevertErrors(t *testing.T) {
+	stateDB := newMockStateDB()
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	unknown := common.HexToAddress("0x9999999999999999999999999999999999999999")
+	token := createTestToken(t, stateDB, owner, big.NewInt(1000))
+
+	config := func(modify func(*TokenConfig)) []byte {
+		fees := [12]*big.Int{}
+		for i := range fees {
+			fees[i] = big.NewInt(0)
+		}
+		c := TokenConfig{Name: "Test", Symbol: "TST", TotalSupply: big.NewInt(1000), InitialBacking: big.NewInt(0), Fees: fees, Owner: owner}
+		modify(&c)
+		input, err := EncodeCreateToken(c)
+		if err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+		return input
+	}
+	floorPrice, _ := EncodeGetFloorPrice(unknown)
+	burn, _ := EncodeBurnAndRecover(token, big.NewInt(10))
+	transfer, _ := tokenABI.Pack("transfer", owner, big.NewInt(1))
+
+	for _, tt := range []struct {
+		name   string
+		run    func(CallContext, []byte) ([]byte, error)
+		ctx    CallContext
+		input  []byte
+		reason string
+	}{
+		{"unknown method", (&Precompile{}).RunStateful, CallContext{}, []byte{0xff, 0xff, 0xff, 0xff}, "InvalidInput()"},
+		{"unknown token", (&Precompile{}).RunStateful, CallContext{}, floorPrice, "PoolNotFound(" + unknown.Hex() + ")"},
+		{"value to view", (&Precompile{}).RunStateful, CallContext{Value: uint256.NewInt(5)}, floorPrice, "NonPayable(5)"},
+		{"static create", (&Precompile{}).RunStateful, CallContext{Caller: owner, ReadOnly: true}, config(func(*TokenConfig) {}), "StaticCallViolation()"},
+		{"value mismatch", (&Precompile{}).RunStateful, CallContext{Caller: owner, Value: uint256.NewInt(7)}, config(func(*TokenConfig) {}), "ValueMismatch(7, 0)"},
+		{"zero supply", (&Precompile{}).RunStateful, CallContext{Caller: owner}, config(func(c *TokenConfig) { c.TotalSupply = big.NewInt(0) }), "InvalidTokenConfig()"},
+		{"excessive fees", (&Precompile{}).RunStateful, CallContext{Caller: owner}, config(func(c *TokenConfig) { c.Fees[FeeBacking] = big.NewInt(501) }), "InvalidFees()"},
+		{"backing asset", (&Precompile{}).RunStateful, CallContext{Caller: owner}, config(func(c *TokenConfig) { c.BackingAsset = unknown }), "UnsupportedBackingAsset(" + unknown.Hex() + ")"},
+		{"burn balance", (&Precompile{}).RunStateful, CallContext{Caller: unknown}, burn, "InsufficientBalance(" + unknown.Hex() + ", 0, 10)"},
+		{"token balance", (&Token{}).RunStateful, CallContext{Caller: unknown, Address: token}, transfer, "InsufficientBalance(" + unknown.Hex() + ", 0, 1)"},
+	} {
+		tt.ctx.StateDB = stateDB
+		_, err := tt.run(tt.ctx, tt.input)
+		if !errors.Is(err, ErrExecutionReverted) {
+			t.Errorf("%s: expected %v, got %v", tt.name, ErrExecutionReverted, err)
+			continue
+		}
+		var revertErr *RevertError
+		if !errors.As(err, &revertErr) {
+			t.Errorf("%s: expected a custom error, got %v", tt.name, err)
+			continue
+		}
+		reason, err := UnpackRevert(revertErr.Data())
+		if err != nil {
+			t.Errorf("%s: failed to unpack revert data: %v", tt.name, err)
+			continue
+		}
+		if reason != tt.reason {
+			t.Errorf("%s: expected reason %s, got %s", tt.name, tt.reason, reason)
+		}
+	}
+
+	// Data not holding a custom error is rejected
+	if _, err := UnpackRevert([]byte{0x08, 0xc3, 0x79, 0xa0}); err == nil {
+		t.Error("Expected error when unpacking an Error(string) selector")
+	}
+}
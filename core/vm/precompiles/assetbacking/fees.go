@@ -0,0 +1,244 @@
+// Package assetbacking - buy and sell fees of created tokens.
+package assetbacking
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/core/tracing"
+)
+
+// Fee schedule
+//
+// TokenConfig.Fees holds the buy-side fees in Fees[0..5] and the sell-side fees
+// in Fees[6..11], in units of 0.1% of the transferred amount. The fee at index i
+// of a side has the following meaning:
+const (
+	FeeBacking   = 0 // Held in the fee reserve, sold into the backing pool (see buyFeeReserve)
+	FeeLiquidity = 1 // Credited to the token contract itself, to provide liquidity
+	FeeTreasury  = 2 // Credited to the token owner
+	// Indices 3 to 5 are reserved and must be zero
+
+	FeeSellOffset  = 6    // Index of the first sell-side fee
+	FeeDenominator = 1000 // Fees are expressed in 1/1000 of the amount
+)
+
+// SlotOnlySB is the offset of the OnlySB flag in the fee namespace, which
+// holds the fees at offsets 0 to 11.
+const SlotOnlySB = 12
+
+var feeRoot = backingpool.NamespaceSlot(backingpool.FeeNamespace)
+
+// feeSlot returns the slot of the fee structure field at offset.
+func feeSlot(offset int) common.Hash {
+	return backingpool.FieldSlot(feeRoot, offset)
+}
+
+// legacyFeeSlot returns the slot of the fee structure field at offset in the
+// layout used before the storage namespaces.
+func legacyFeeSlot(tokenAddress common.Address, offset int) common.Hash {
+	return backingpool.LegacySlot(tokenAddress, "SmartDeFi-Fees", offset)
+}
+
+// FeeSlots returns every slot the fee structure occupies in the token account.
+func FeeSlots() []common.Hash {
+	slots := make([]common.Hash, SlotOnlySB+1)
+	for i := range slots {
+		slots[i] = feeSlot(i)
+	}
+	return slots
+}
+
+// loadFeeStructure loads the fee structure stored by storeFeeStructure.
+func loadFeeStructure(stateDB StateDB, tokenAddress common.Address) ([12]*big.Int, bool) {
+	var fees [12]*big.Int
+	for i := range fees {
+		fees[i] = stateDB.GetState(tokenAddress, feeSlot(i)).Big()
+	}
+	onlySB := stateDB.GetState(tokenAddress, feeSlot(SlotOnlySB)).Big().Sign() != 0
+	return fees, onlySB
+}
+
+// TokenFees returns the fee structure and OnlySB flag of a token. The fees of a
+// token still holding a legacy pool, in a state before the SmartDeFi fork
+// migrated it, are read from the legacy slots.
+func TokenFees(stateDB backingpool.StateReader, tokenAddress common.Address) ([12]*big.Int, bool) {
+	slot := feeSlot
+	if stateDB.GetState(tokenAddress, backingpool.PoolSlot(backingpool.SlotTotalBacking)) == (common.Hash{}) &&
+		stateDB.GetState(tokenAddress, backingpool.PoolSlot(backingpool.SlotTotalSupply)) == (common.Hash{}) {
+		slot = func(offset int) common.Hash { return legacyFeeSlot(tokenAddress, offset) }
+	}
+	var fees [12]*big.Int
+	for i := range fees {
+		fees[i] = stateDB.GetState(tokenAddress, slot(i)).Big()
+	}
+	onlySB := stateDB.GetState(tokenAddress, slot(SlotOnlySB)).Big().Sign() != 0
+	return fees, onlySB
+}
+
+// MigrateLegacyStorage moves the backing pool and fee structure of a token
+// created before the storage namespaces to the namespaced layout, clearing the
+// legacy slots. Tokens are migrated once, when the SmartDeFi fork activates
+// (see misc.ApplySmartDeFiHardFork). It reports whether the token was migrated.
+func MigrateLegacyStorage(stateDB StateDB, tokenAddress common.Address) bool {
+	if !backingpool.MigrateLegacyBackingPool(stateDB, tokenAddress) {
+		return false
+	}
+	for i := 0; i <= SlotOnlySB; i++ {
+		value := stateDB.GetState(tokenAddress, legacyFeeSlot(tokenAddress, i))
+		stateDB.SetState(tokenAddress, legacyFeeSlot(tokenAddress, i), common.Hash{})
+		stateDB.SetState(tokenAddress, feeSlot(i), value)
+	}
+	// Tokens created before the token code have neither nonce, balance nor
+	// code, and would be removed with their storage once touched (EIP-161).
+	if stateDB.GetNonce(tokenAddress) == 0 {
+		stateDB.SetNonce(tokenAddress, 1, tracing.NonceChangeNewContract)
+	}
+	return true
+}
+
+// MigrateLegacyBacking moves the Smart coin locked at the legacy address of the
+// precompile to its current address, along with the backing of the legacy
+// tokens. It runs once, when the SmartDeFi fork activates (see
+// misc.ApplySmartDeFiHardFork), and reports whether any backing was moved.
+func MigrateLegacyBacking(stateDB StateDB) bool {
+	locked := stateDB.GetBalance(LegacyPrecompileAddressBytes)
+	if locked.IsZero() {
+		return false
+	}
+	locked = locked.Clone()
+	stateDB.SubBalance(LegacyPrecompileAddressBytes, locked, tracing.BalanceChangeBackingRecovered)
+	stateDB.AddBalance(PrecompileAddressBytes, locked, tracing.BalanceChangeBackingLocked)
+	return true
+}
+
+// feeSide returns the index of the first fee applying to a transfer. Tokens
+// leaving a contract (e.g. a trading pair) are bought and tokens moving into a
+// contract are sold. Transfers between externally owned accounts, and those
+// from or to the token owner or the token itself, are free of fees.
+func feeSide(stateDB StateDB, token, from, to common.Address) (int, bool) {
+	owner := TokenOwner(stateDB, token)
+	if from == owner || to == owner || from == token || to == token {
+		return 0, false
+	}
+	switch {
+	case stateDB.GetCodeSize(to) > 0:
+		return FeeSellOffset, true
+	case stateDB.GetCodeSize(from) > 0:
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// feeAmount returns the share of value taken by fee, rounded in the given
+// direction.
+func feeAmount(value *big.Int, fee *big.Int, rounding backingpool.Rounding) *big.Int {
+	return backingpool.MulDiv(value, fee, big.NewInt(FeeDenominator), rounding)
+}
+
+// chargeTransferFees takes the fees applying to a transfer of value tokens of
+// the called token out of the transferred amount, which the sender has already
+// been debited with. It returns the amount left for the recipient.
+func chargeTransferFees(ctx CallContext, from, to common.Address, value *big.Int) (*big.Int, error) {
+	stateDB, token := ctx.StateDB, ctx.Address
+
+	side, ok := feeSide(stateDB, token, from, to)
+	if !ok {
+		return value, nil
+	}
+	fees, _ := loadFeeStructure(stateDB, token)
+	received := new(big.Int).Set(value)
+	// The backing share is owed to the pool and rounds up, so that small
+	// transfers cannot evade it. As the fees of a side total at most half of
+	// the amount, the shares never exceed it.
+	backing := feeAmount(value, fees[side+FeeBacking], backingpool.RoundUp)
+
+	// Credit the liquidity and treasury shares. A renounced token has no owner
+	// to receive the treasury share, which joins the backing share.
+	for _, share := range []struct {
+		index     int
+		recipient common.Address
+	}{
+		{FeeLiquidity, token},
+		{FeeTreasury, TokenOwner(stateDB, token)},
+	} {
+		amount := feeAmount(value, fees[side+share.index], backingpool.RoundDown)
+		if amount.Sign() == 0 {
+			continue
+		}
+		if share.recipient == (common.Address{}) {
+			backing.Add(backing, amount)
+			continue
+		}
+		received.Sub(received, amount)
+		setTokenBalance(stateDB, token, share.recipient, new(big.Int).Add(TokenBalance(stateDB, token, share.recipient), amount))
+		emitTokenEvent(ctx, token, "Transfer", from, share.recipient, amount)
+	}
+	// Hold the backing share in the fee reserve
+	if backing.Sign() > 0 {
+		received.Sub(received, backing)
+		reserve := FeeReserve(stateDB, token)
+		setTokenBalance(stateDB, token, PrecompileAddressBytes, reserve.Add(reserve, backing))
+		emitTokenEvent(ctx, token, "Transfer", from, PrecompileAddressBytes, backing)
+	}
+	return received, nil
+}
+
+// FeeReserve returns the tokens the precompile holds from the backing shares of
+// the fees of a token. A transfer carries no Smart coin, so the shares cannot
+// be deposited as backing when charged. They are kept in circulation instead,
+// until buyFeeReserve sells them at the floor price and deposits the proceeds.
+func FeeReserve(stateDB backingpool.StateReader, token common.Address) *big.Int {
+	return TokenBalance(stateDB, token, PrecompileAddressBytes)
+}
+
+// buyFeeReserve sells tokens of the fee reserve to the caller. The msg.value,
+// which must cover the floor price of the tokens rounded up, is deposited into
+// the backing pool in full. As the reserve was already part of the circulating
+// supply, this raises the floor price.
+func (p *Precompile) buyFeeReserve(ctx CallContext, input []byte) ([]byte, error) {
+	if ctx.ReadOnly {
+		return nil, revert("StaticCallViolation")
+	}
+	args, err := decodeInput("buyFeeReserve", input)
+	if err != nil {
+		return nil, err
+	}
+	stateDB, token, amount := ctx.StateDB, args[0].(common.Address), args[1].(*big.Int)
+
+	pool := backingpool.GetBackingPool(stateDB, token)
+	if pool == nil {
+		return nil, revert("PoolNotFound", token)
+	}
+	reserve := FeeReserve(stateDB, token)
+	if amount.Sign() == 0 || amount.Cmp(reserve) > 0 {
+		return nil, revert("InvalidAmount", amount, reserve)
+	}
+	// The reserve of a token without backing is not given away either
+	circulatingSupply := new(big.Int).Sub(pool.TotalSupply, pool.BurnedSupply)
+	cost := backingpool.MulDiv(amount, pool.TotalBacking, circulatingSupply, backingpool.RoundUp)
+	if cost.Sign() == 0 {
+		cost.SetInt64(1)
+	}
+	// The EVM has already transferred the value to the precompile
+	value := new(big.Int)
+	if ctx.Value != nil {
+		value = ctx.Value.ToBig()
+	}
+	if value.Cmp(cost) < 0 {
+		return nil, revert("InsufficientPayment", value, cost)
+	}
+	pool.AddBacking(value)
+	backingpool.SetBackingPool(stateDB, pool)
+
+	setTokenBalance(stateDB, token, PrecompileAddressBytes, reserve.Sub(reserve, amount))
+	setTokenBalance(stateDB, token, ctx.Caller, new(big.Int).Add(TokenBalance(stateDB, token, ctx.Caller), amount))
+	emitTokenEvent(ctx, token, "Transfer", PrecompileAddressBytes, ctx.Caller, amount)
+
+	if err := emitEvent(ctx, "BackingAdded", token, value); err != nil {
+		return nil, ErrExecutionReverted
+	}
+	return EncodeOutput("buyFeeReserve", value)
+}
@@ -0,0 +1,415 @@
+// Package assetbacking - Tests for the buy and sell fees of created tokens.
+package assetbacking
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+	"testing/quick"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+)
+
+// TestTransferFees tests the exact split of the buy and sell fees of a token.
+func TestTransferFees(t *testing.T) {
+	stateDB := newMockStateDB()
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	alice := common.HexToAddress("0xa11ce")
+	bob := common.HexToAddress("0xb0b")
+	pair := common.HexToAddress("0x9a19")
+	stateDB.SetCodeSize(pair, 100)
+
+	// Buy: 2% backing, 1% liquidity, 3% treasury; sell: 5% backing, 2% treasury
+	fees := [12]*big.Int{}
+	for i := range fees {
+		fees[i] = big.NewInt(0)
+	}
+	fees[FeeBacking] = big.NewInt(20)
+	fees[FeeLiquidity] = big.NewInt(10)
+	fees[FeeTreasury] = big.NewInt(30)
+	fees[FeeSellOffset+FeeBacking] = big.NewInt(50)
+	fees[FeeSellOffset+FeeTreasury] = big.NewInt(20)
+
+	input, err := EncodeCreateToken(TokenConfig{
+		Name:           "Fee Token",
+		Symbol:         "FEE",
+		TotalSupply:    big.NewInt(1000000),
+		InitialBacking: big.NewInt(1000000000),
+		Fees:           fees,
+		Owner:          owner,
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	stateDB.balances[PrecompileAddressBytes] = big.NewInt(1000000000)
+	result, err := (&Precompile{}).RunStateful(CallContext{StateDB: stateDB, Caller: owner, Value: uint256.NewInt(1000000000)}, input)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+	token := common.BytesToAddress(result)
+
+	transfer := func(from, to common.Address, amount int64) {
+		t.Helper()
+		if _, err := callToken(CallContext{StateDB: stateDB, Address: token, Caller: from}, "transfer", to, big.NewInt(amount)); err != nil {
+			t.Fatalf("Failed to transfer: %v", err)
+		}
+	}
+	checkBalances := func(want map[common.Address]int64) {
+		t.Helper()
+		for holder, balance := range want {
+			if have := TokenBalance(stateDB, token, holder); have.Cmp(big.NewInt(balance)) != 0 {
+				t.Errorf("Expected balance %d for %s, got %s", balance, holder.Hex(), have)
+			}
+		}
+	}
+	floorPrice := func() *big.Int {
+		return backingpool.GetBackingPool(stateDB, token).CalculateFloorPrice()
+	}
+
+	// Transfers from the owner are free of fees
+	transfer(owner, pair, 100000)
+	transfer(owner, alice, 10000)
+	checkBalances(map[common.Address]int64{owner: 890000, pair: 100000, alice: 10000})
+
+	// Buying from the pair takes 2% + 1% + 3% of the amount
+	floor := floorPrice()
+	logs := len(stateDB.logs)
+	transfer(pair, bob, 10000)
+	checkBalances(map[common.Address]int64{pair: 90000, bob: 9400, token: 100, owner: 890300})
+
+	// The backing share is held in the fee reserve, without touching the pool
+	for _, log := range stateDB.logs[logs:] {
+		if log.Address == PrecompileAddressBytes {
+			t.Errorf("Unexpected precompile event %x for a fee", log.Topics[0])
+		}
+	}
+	if pools := ChangedPools(stateDB.logs[logs:]); len(pools) != 0 {
+		t.Errorf("Expected no changed pools, got %v", pools)
+	}
+	if reserve := FeeReserve(stateDB, token); reserve.Cmp(big.NewInt(200)) != 0 {
+		t.Errorf("Expected fee reserve 200, got %s", reserve)
+	}
+	if floorPrice().Cmp(floor) != 0 {
+		t.Errorf("Expected floor price to remain %s, got %s", floor, floorPrice())
+	}
+
+	// Selling to the pair takes 5% + 2% of the amount
+	transfer(bob, pair, 1000)
+	checkBalances(map[common.Address]int64{bob: 8400, pair: 90930, token: 100, owner: 890320, PrecompileAddressBytes: 250})
+
+	// The reserve is sold at the floor price, rounded up, into the backing
+	buyFeeReserve := func(amount, value int64) error {
+		input, err := precompileABI.Pack("buyFeeReserve", token, big.NewInt(amount))
+		if err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+		_, err = (&Precompile{}).RunStateful(CallContext{StateDB: stateDB, Caller: alice, Value: uint256.NewInt(uint64(value))}, input)
+		return err
+	}
+	if err := buyFeeReserve(251, 251000); err == nil {
+		t.Error("Expected error when buying more than the fee reserve")
+	}
+	if err := buyFeeReserve(250, 249999); err == nil {
+		t.Error("Expected error when paying less than the floor price")
+	}
+	logs = len(stateDB.logs)
+	if err := buyFeeReserve(250, 250000); err != nil {
+		t.Fatalf("Failed to buy the fee reserve: %v", err)
+	}
+	checkBalances(map[common.Address]int64{alice: 10250, PrecompileAddressBytes: 0})
+	if pools := ChangedPools(stateDB.logs[logs:]); len(pools) != 1 || pools[0] != token {
+		t.Errorf("Expected changed pools [%s], got %v", token.Hex(), pools)
+	}
+	pool := backingpool.GetBackingPool(stateDB, token)
+	if pool.TotalBacking.Cmp(big.NewInt(1000250000)) != 0 {
+		t.Errorf("Expected total backing 1000250000, got %s", pool.TotalBacking)
+	}
+	if pool.BurnedSupply.Sign() != 0 {
+		t.Errorf("Expected no burned supply, got %s", pool.BurnedSupply)
+	}
+	if floorPrice().Cmp(floor) <= 0 {
+		t.Errorf("Expected floor price to rise above %s, got %s", floor, floorPrice())
+	}
+
+	// Transfers between externally owned accounts are free of fees
+	transfer(bob, alice, 400)
+	checkBalances(map[common.Address]int64{bob: 8000, alice: 10650})
+
+	// No token was created or lost by the fees
+	var total int64
+	for _, holder := range []common.Address{owner, alice, bob, pair, token, PrecompileAddressBytes} {
+		total += TokenBalance(stateDB, token, holder).Int64()
+	}
+	if supply := TokenTotalSupply(stateDB, token).Int64(); total != supply {
+		t.Errorf("Expected balances to add up to the supply %d, got %d", supply, total)
+	}
+}
+
+// TestRenouncedTreasuryFees tests that the treasury share of a renounced token,
+// which has no owner to receive it, joins the fee reserve instead.
+func TestRenouncedTreasuryFees(t *testing.T) {
+	stateDB := newMockStateDB()
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	bob := common.HexToAddress("0xb0b")
+	pair := common.HexToAddress("0x9a19")
+	stateDB.SetCodeSize(pair, 100)
+	token := createTestToken(t, stateDB, owner, big.NewInt(1000000))
+
+	// Buy: 2% backing, 3% treasury
+	fees := [12]*big.Int{}
+	for i := range fees {
+		fees[i] = big.NewInt(0)
+	}
+	fees[FeeBacking] = big.NewInt(20)
+	fees[FeeTreasury] = big.NewInt(30)
+	call := func(method string, args ...interface{}) {
+		input, err := precompileABI.Pack(method, args...)
+		if err != nil {
+			t.Fatalf("Failed to encode %s: %v", method, err)
+		}
+		if _, err := (&Precompile{}).RunStateful(CallContext{StateDB: stateDB, Caller: owner}, input); err != nil {
+			t.Fatalf("Failed to call %s: %v", method, err)
+		}
+	}
+	call("setFees", token, fees)
+	if _, err := callToken(CallContext{StateDB: stateDB, Address: token, Caller: owner}, "transfer", pair, big.NewInt(100000)); err != nil {
+		t.Fatalf("Failed to transfer: %v", err)
+	}
+	call("renounceOwnership", token)
+
+	if _, err := callToken(CallContext{StateDB: stateDB, Address: token, Caller: pair}, "transfer", bob, big.NewInt(10000)); err != nil {
+		t.Fatalf("Failed to transfer: %v", err)
+	}
+	if balance := TokenBalance(stateDB, token, bob); balance.Cmp(big.NewInt(9500)) != 0 {
+		t.Errorf("Expected balance 9500, got %s", balance)
+	}
+	if balance := TokenBalance(stateDB, token, common.Address{}); balance.Sign() != 0 {
+		t.Errorf("Expected no balance at the zero address, got %s", balance)
+	}
+	if reserve := FeeReserve(stateDB, token); reserve.Cmp(big.NewInt(500)) != 0 {
+		t.Errorf("Expected fee reserve 500, got %s", reserve)
+	}
+}
+
+// TestFeeRounding tests that the backing share of a fee rounds up in the pool's
+// favor, so that no transfer evades it, while the other shares round down and
+// all shares together never exceed the transferred amount.
+func TestFeeRounding(t *testing.T) {
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	bob := common.HexToAddress("0xb0b")
+	pair := common.HexToAddress("0x9a19")
+
+	property := func(backing, liquidity, treasury uint16, amount uint32) bool {
+		stateDB := newMockStateDB()
+		stateDB.SetCodeSize(pair, 100)
+
+		// Buy fees totalling at most 50%
+		fees := [12]*big.Int{}
+		for i := range fees {
+			fees[i] = big.NewInt(0)
+		}
+		fees[FeeBacking] = big.NewInt(int64(backing % 501))
+		fees[FeeLiquidity] = big.NewInt(int64(liquidity) % (501 - fees[FeeBacking].Int64()))
+		fees[FeeTreasury] = big.NewInt(int64(treasury) % (501 - fees[FeeBacking].Int64() - fees[FeeLiquidity].Int64()))
+
+		input, err := EncodeCreateToken(TokenConfig{
+			Name:           "Fee Token",
+			Symbol:         "FEE",
+			TotalSupply:    big.NewInt(1000000000000),
+			InitialBacking: big.NewInt(1000000007),
+			Fees:           fees,
+			Owner:          owner,
+		})
+		if err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+		stateDB.balances[PrecompileAddressBytes] = big.NewInt(1000000007)
+		result, err := (&Precompile{}).RunStateful(CallContext{StateDB: stateDB, Caller: owner, Value: uint256.NewInt(1000000007)}, input)
+		if err != nil {
+			t.Fatalf("Failed to create token: %v", err)
+		}
+		token := common.BytesToAddress(result)
+		if _, err := callToken(CallContext{StateDB: stateDB, Address: token, Caller: owner}, "transfer", pair, big.NewInt(1<<32)); err != nil {
+			t.Fatalf("Failed to transfer: %v", err)
+		}
+		floor := backingpool.GetBackingPool(stateDB, token).CalculateFloorPrice()
+
+		value := big.NewInt(int64(amount))
+		if _, err := callToken(CallContext{StateDB: stateDB, Address: token, Caller: pair}, "transfer", bob, value); err != nil {
+			t.Fatalf("Failed to transfer: %v", err)
+		}
+		pool := backingpool.GetBackingPool(stateDB, token)
+		retired := backingpool.MulDiv(value, fees[FeeBacking], big.NewInt(FeeDenominator), backingpool.RoundUp)
+		if FeeReserve(stateDB, token).Cmp(retired) != 0 {
+			return false
+		}
+		// The backing share is at least the exact share
+		if new(big.Int).Mul(retired, big.NewInt(FeeDenominator)).Cmp(new(big.Int).Mul(value, fees[FeeBacking])) < 0 {
+			return false
+		}
+		received := new(big.Int).Sub(value, retired)
+		received.Sub(received, TokenBalance(stateDB, token, token))
+		received.Sub(received, new(big.Int).Sub(TokenBalance(stateDB, token, owner), big.NewInt(1000000000000-1<<32)))
+		if received.Sign() < 0 || TokenBalance(stateDB, token, bob).Cmp(received) != 0 {
+			return false
+		}
+		return pool.CalculateFloorPrice().Cmp(floor) >= 0
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestReservedFees tests that tokens cannot be created with reserved fees set.
+func TestReservedFees(t *testing.T) {
+	for _, index := range []int{3, 4, 5, FeeSellOffset + 3, FeeSellOffset + 4, FeeSellOffset + 5} {
+		fees := [12]*big.Int{}
+		for i := range fees {
+			fees[i] = big.NewInt(0)
+		}
+		fees[index] = big.NewInt(1)
+
+		input, err := EncodeCreateToken(TokenConfig{
+			Name:           "Fee Token",
+			Symbol:         "FEE",
+			TotalSupply:    big.NewInt(1000000),
+			InitialBacking: big.NewInt(0),
+			Fees:           fees,
+			Owner:          common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e"),
+		})
+		if err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+		ctx := CallContext{StateDB: newMockStateDB(), Caller: common.HexToAddress("0x01")}
+		if _, err := (&Precompile{}).RunStateful(ctx, input); err == nil {
+			t.Errorf("Expected error when setting reserved fee %d", index)
+		}
+	}
+}
+
+// TestMigrateLegacyStorage tests that the pool and fees of a token created
+// before the storage namespaces stay readable until they are moved to the
+// namespaced layout, and are charged from there afterwards.
+func TestMigrateLegacyStorage(t *testing.T) {
+	stateDB := newMockStateDB()
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	buyer := common.HexToAddress("0xb0b")
+	pair := common.HexToAddress("0x9a19")
+	stateDB.SetCodeSize(pair, 100)
+	token := createTestToken(t, stateDB, owner, big.NewInt(1000000))
+
+	// Move the pool, which has no backing, and a 10% buy fee to the legacy slots
+	pool := backingpool.GetBackingPool(stateDB, token)
+	for _, slot := range append(backingpool.PoolSlots(len(pool.BackingAssets)), FeeSlots()...) {
+		stateDB.SetState(token, slot, common.Hash{})
+	}
+	legacy := map[common.Hash]*big.Int{
+		backingpool.LegacySlot(token, "SmartDeFi-BackingPool", backingpool.SlotTotalSupply): pool.TotalSupply,
+		legacyFeeSlot(token, FeeTreasury): big.NewInt(100),
+	}
+	for slot, value := range legacy {
+		stateDB.SetState(token, slot, common.BigToHash(value))
+	}
+
+	// The legacy pool is readable without being migrated
+	if supply := TokenTotalSupply(stateDB, token); supply.Cmp(big.NewInt(1000000)) != 0 {
+		t.Errorf("Expected total supply 1000000, got %s", supply)
+	}
+	if _, err := callToken(CallContext{StateDB: stateDB, Address: token, ReadOnly: true}, "totalSupply"); err != nil {
+		t.Fatalf("Failed to get total supply: %v", err)
+	}
+	if fees, _ := TokenFees(stateDB, token); fees[FeeTreasury].Cmp(big.NewInt(100)) != 0 {
+		t.Errorf("Expected legacy treasury fee 100, got %s", fees[FeeTreasury])
+	}
+	for slot := range legacy {
+		if stateDB.GetState(token, slot) == (common.Hash{}) {
+			t.Fatalf("Expected legacy slot %x to remain before the migration", slot)
+		}
+	}
+
+	// The migration moves the pool and fees, which transfers then charge
+	if !MigrateLegacyStorage(stateDB, token) {
+		t.Fatal("Expected legacy token to be migrated")
+	}
+	if _, err := callToken(CallContext{StateDB: stateDB, Address: token, Caller: owner}, "transfer", pair, big.NewInt(1000)); err != nil {
+		t.Fatalf("Failed to transfer: %v", err)
+	}
+	if _, err := callToken(CallContext{StateDB: stateDB, Address: token, Caller: pair}, "transfer", buyer, big.NewInt(1000)); err != nil {
+		t.Fatalf("Failed to transfer: %v", err)
+	}
+	if balance := TokenBalance(stateDB, token, buyer); balance.Cmp(big.NewInt(900)) != 0 {
+		t.Errorf("Expected balance 900 after the legacy fee, got %s", balance)
+	}
+	for slot := range legacy {
+		if value := stateDB.GetState(token, slot); value != (common.Hash{}) {
+			t.Errorf("Expected legacy slot %x to be cleared, got %x", slot, value)
+		}
+	}
+	if fees, _ := loadFeeStructure(stateDB, token); fees[FeeTreasury].Cmp(big.NewInt(100)) != 0 {
+		t.Errorf("Expected migrated treasury fee 100, got %s", fees[FeeTreasury])
+	}
+	if fees, _ := TokenFees(stateDB, token); fees[FeeTreasury].Cmp(big.NewInt(100)) != 0 {
+		t.Errorf("Expected treasury fee 100 after migration, got %s", fees[FeeTreasury])
+	}
+	if MigrateLegacyStorage(stateDB, token) {
+		t.Error("Expected migrated token not to be migrated again")
+	}
+}
+
+// TestStorageSlotsDisjoint enumerates every storage field of many tokens and
+// checks that no two fields of a token account share a slot, and that no
+// namespaced field shares a slot with the legacy layout.
+func TestStorageSlotsDisjoint(t *testing.T) {
+	const tokens = 256
+
+	// Every token holds balances and allowances of every other token address
+	addrs := make([]common.Address, tokens)
+	for i := range addrs {
+		addrs[i] = common.BytesToAddress(crypto.Keccak256([]byte{byte(i), byte(i >> 8)}))
+	}
+	for _, token := range addrs {
+		fields := make(map[common.Hash]string)
+		add := func(slot common.Hash, field string) {
+			if prev, ok := fields[slot]; ok {
+				t.Fatalf("Token %s: %s and %s share slot %x", token.Hex(), prev, field, slot)
+			}
+			fields[slot] = field
+		}
+		// Pool, backing arrays and fees
+		for i, slot := range backingpool.PoolSlots(backingpool.MaxBackingAssets) {
+			add(slot, fmt.Sprintf("pool field %d", i))
+		}
+		for i, slot := range FeeSlots() {
+			add(slot, fmt.Sprintf("fee field %d", i))
+		}
+		for i, slot := range LGESlots(addrs) {
+			add(slot, fmt.Sprintf("LGE field %d", i))
+		}
+		// Metadata, including up to four words of string data
+		for name, slot := range map[string]common.Hash{"name": slotTokenName, "symbol": slotTokenSymbol, "owner": slotTokenOwner} {
+			add(slot, name)
+			data := crypto.Keccak256Hash(slot.Bytes())
+			for i := 0; i < 4; i++ {
+				add(backingpool.FieldSlot(data, i), fmt.Sprintf("%s word %d", name, i))
+			}
+		}
+		// Balances and allowances
+		for _, holder := range addrs {
+			add(tokenBalanceSlot(holder), "balance of "+holder.Hex())
+			add(tokenAllowanceSlot(token, holder), "allowance of "+holder.Hex())
+			if holder != token {
+				add(tokenAllowanceSlot(holder, token), "allowance by "+holder.Hex())
+			}
+		}
+		// The legacy slots, which a migration clears, are disjoint from all of the above
+		for i := 0; i <= backingpool.SlotBackingAmounts; i++ {
+			add(backingpool.LegacySlot(token, "SmartDeFi-BackingPool", i), fmt.Sprintf("legacy pool field %d", i))
+		}
+		for i := 0; i <= SlotOnlySB; i++ {
+			add(legacyFeeSlot(token, i), fmt.Sprintf("legacy fee field %d", i))
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Package assetbacking - tokens allocated in the genesis block.
+package assetbacking
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/holiman/uint256"
+)
+
+//go:generate go run github.com/fjl/gencodec -type GenesisToken -field-override genesisTokenMarshaling -out gen_genesis_token.go
+
+// GenesisToken is a token of the smartDeFi section of the genesis specification.
+// It is created in the genesis state as if its owner had created it with the
+// given salt, or without salt if none is given.
+type GenesisToken struct {
+	Name    string         `json:"name"`
+	Symbol  string         `json:"symbol"`
+	Owner   common.Address `json:"owner"   gencodec:"required"`
+	Supply  *big.Int       `json:"supply"  gencodec:"required"`
+	Backing *big.Int       `json:"backing"`
+	Fees    [12]uint64     `json:"fees"`
+	OnlySB  bool           `json:"onlySB"`
+	Salt    *common.Hash   `json:"salt,omitempty"`
+}
+
+// field type overrides for gencodec.
+type genesisTokenMarshaling struct {
+	Supply  *math.HexOrDecimal256
+	Backing *math.HexOrDecimal256
+}
+
+// config returns the configuration the token is created with.
+func (t GenesisToken) config() TokenConfig {
+	config := TokenConfig{
+		Name:           t.Name,
+		Symbol:         t.Symbol,
+		TotalSupply:    t.Supply,
+		InitialBacking: t.Backing,
+		OnlySB:         t.OnlySB,
+		Owner:          t.Owner,
+	}
+	if config.InitialBacking == nil {
+		config.InitialBacking = new(big.Int)
+	}
+	for i, fee := range t.Fees {
+		config.Fees[i] = new(big.Int).SetUint64(fee)
+	}
+	return config
+}
+
+// ValidateGenesisTokens checks the tokens of the smartDeFi genesis section,
+// returning an error that names the offending token.
+func ValidateGenesisTokens(tokens []GenesisToken) error {
+	total := new(big.Int)
+	for i, token := range tokens {
+		if err := validateGenesisToken(token); err != nil {
+			return fmt.Errorf("smartDeFi token %d (%s): %w", i, token.Symbol, err)
+		}
+		total.Add(total, token.config().InitialBacking)
+	}
+	if total.BitLen() > 256 {
+		return errors.New("smartDeFi backing exceeds 256 bits")
+	}
+	return nil
+}
+
+// validateGenesisToken checks a token like its creation would.
+func validateGenesisToken(token GenesisToken) error {
+	switch {
+	case token.Owner == (common.Address{}):
+		return errors.New("missing owner")
+	case token.Supply == nil || token.Supply.Sign() <= 0:
+		return errors.New("supply must be positive")
+	case token.Supply.BitLen() > 256:
+		return errors.New("supply exceeds 256 bits")
+	case token.Backing != nil && token.Backing.Sign() < 0:
+		return errors.New("negative backing")
+	case token.Backing != nil && token.Backing.BitLen() > 256:
+		return errors.New("backing exceeds 256 bits")
+	}
+	if err := validateFees(token.config().Fees); err != nil {
+		return errors.New("fees exceed 50% per side or use reserved fees")
+	}
+	return nil
+}
+
+// ApplyGenesisTokens creates the tokens of the smartDeFi genesis section in the
+// given state, in order. The backing of every token is added to the balance of
+// the precompile, which therefore holds the total backing of the genesis tokens.
+func ApplyGenesisTokens(stateDB StateDB, tokens []GenesisToken) ([]common.Address, error) {
+	if err := ValidateGenesisTokens(tokens); err != nil {
+		return nil, err
+	}
+	var (
+		p         = new(Precompile)
+		addresses = make([]common.Address, 0, len(tokens))
+	)
+	for i, token := range tokens {
+		config := token.config()
+		salt := defaultTokenSalt(stateDB, token.Owner)
+		if token.Salt != nil {
+			salt = *token.Salt
+		}
+		backing := uint256.MustFromBig(config.InitialBacking)
+		stateDB.AddBalance(PrecompileAddressBytes, backing, tracing.BalanceIncreaseGenesisBalance)
+
+		ctx := CallContext{
+			StateDB: stateDB,
+			Caller:  token.Owner,
+			Address: PrecompileAddressBytes,
+			Value:   backing,
+		}
+		output, err := p.createToken(ctx, salt, config, nil)
+		if err != nil {
+			var reason *RevertError
+			if errors.As(err, &reason) {
+				return nil, fmt.Errorf("smartDeFi token %d (%s): %s", i, token.Symbol, reason.Name())
+			}
+			return nil, fmt.Errorf("smartDeFi token %d (%s): %w", i, token.Symbol, err)
+		}
+		addresses = append(addresses, common.BytesToAddress(output))
+	}
+	return addresses, nil
+}
@@ -0,0 +1,111 @@
+// Package assetbacking - Tests for tokens allocated in the genesis block.
+package assetbacking
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+)
+
+// TestApplyGenesisTokens tests that genesis tokens are created like tokens
+// created by their owners, with the backing locked in the precompile.
+func TestApplyGenesisTokens(t *testing.T) {
+	stateDB := newMockStateDB()
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	salt := common.HexToHash("0x5a17")
+
+	var spec []GenesisToken
+	err := json.Unmarshal([]byte(`[
+		{"name": "Backed", "symbol": "BKD", "owner": "0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e",
+		 "supply": "0xf4240", "backing": "1000", "fees": [10, 20, 30, 0, 0, 0, 40, 50, 60, 0, 0, 0], "onlySB": true},
+		{"name": "Salted", "symbol": "SLT", "owner": "0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e",
+		 "supply": "500", "salt": "0x0000000000000000000000000000000000000000000000000000000000005a17"}
+	]`), &spec)
+	if err != nil {
+		t.Fatalf("Failed to decode genesis tokens: %v", err)
+	}
+	tokens, err := ApplyGenesisTokens(stateDB, spec)
+	if err != nil {
+		t.Fatalf("Failed to apply genesis tokens: %v", err)
+	}
+
+	// The first token is salted like an unsalted creation, the second with the
+	// given salt
+	for i, salt := range []common.Hash{DefaultTokenSalt(0), salt} {
+		want, _ := TokenAddress(owner, salt, spec[i].config())
+		if tokens[i] != want {
+			t.Errorf("Expected token %d at %s, got %s", i, want.Hex(), tokens[i].Hex())
+		}
+		if !IsToken(stateDB.GetCode(tokens[i])) {
+			t.Errorf("Expected token code at %s", tokens[i].Hex())
+		}
+		if balance := TokenBalance(stateDB, tokens[i], owner); balance.Cmp(spec[i].Supply) != 0 {
+			t.Errorf("Expected owner balance of %s, got %s", spec[i].Supply, balance)
+		}
+		if TokenAt(stateDB, uint64(i)) != tokens[i] {
+			t.Errorf("Expected token %d in the registry", i)
+		}
+	}
+	pool := backingpool.GetBackingPool(stateDB, tokens[0])
+	if pool == nil || pool.TotalBacking.Cmp(big.NewInt(1000)) != 0 || pool.TotalSupply.Cmp(big.NewInt(1_000_000)) != 0 {
+		t.Fatalf("Expected pool backed by 1000 with supply 1000000, got %+v", pool)
+	}
+	fees, onlySB := loadFeeStructure(stateDB, tokens[0])
+	if fees[FeeLiquidity].Cmp(big.NewInt(20)) != 0 || fees[FeeSellOffset+FeeTreasury].Cmp(big.NewInt(60)) != 0 || !onlySB {
+		t.Errorf("Expected stored fees, got %v (onlySB %v)", fees, onlySB)
+	}
+	if pool := backingpool.GetBackingPool(stateDB, tokens[1]); pool == nil || pool.TotalBacking.Sign() != 0 {
+		t.Errorf("Expected unbacked pool, got %+v", pool)
+	}
+
+	// The precompile holds the backing and keeps its account
+	if balance := stateDB.GetBalance(PrecompileAddressBytes); balance.Uint64() != 1000 {
+		t.Errorf("Expected precompile balance of 1000, got %s", balance)
+	}
+	if nonce := stateDB.GetNonce(PrecompileAddressBytes); nonce != 1 {
+		t.Errorf("Expected precompile nonce of 1, got %d", nonce)
+	}
+	if count := TokenCount(stateDB); count != 2 {
+		t.Errorf("Expected 2 registered tokens, got %d", count)
+	}
+
+	// Repeating a token collides with the one already created
+	_, err = ApplyGenesisTokens(stateDB, spec[1:])
+	if err == nil || !strings.Contains(err.Error(), "TokenExists") {
+		t.Errorf("Expected TokenExists, got %v", err)
+	}
+}
+
+// TestValidateGenesisTokens tests that invalid genesis tokens are rejected.
+func TestValidateGenesisTokens(t *testing.T) {
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	valid := GenesisToken{Name: "Valid", Symbol: "VLD", Owner: owner, Supply: big.NewInt(1000), Backing: big.NewInt(10)}
+	if err := ValidateGenesisTokens([]GenesisToken{valid}); err != nil {
+		t.Fatalf("Expected valid token, got %v", err)
+	}
+
+	tests := []struct {
+		modify func(*GenesisToken)
+		err    string
+	}{
+		{func(token *GenesisToken) { token.Owner = common.Address{} }, "missing owner"},
+		{func(token *GenesisToken) { token.Supply = nil }, "supply must be positive"},
+		{func(token *GenesisToken) { token.Supply = new(big.Int) }, "supply must be positive"},
+		{func(token *GenesisToken) { token.Backing = big.NewInt(-1) }, "negative backing"},
+		{func(token *GenesisToken) { token.Backing = new(big.Int).Lsh(big.NewInt(1), 256) }, "backing exceeds 256 bits"},
+		{func(token *GenesisToken) { token.Fees[FeeBacking] = 501 }, "fees exceed"},
+		{func(token *GenesisToken) { token.Fees[FeeTreasury+1] = 1 }, "fees exceed"},
+	}
+	for i, test := range tests {
+		token := valid
+		test.modify(&token)
+		err := ValidateGenesisTokens([]GenesisToken{valid, token})
+		if err == nil || !strings.Contains(err.Error(), "smartDeFi token 1 (VLD): "+test.err) {
+			t.Errorf("Test %d: expected error %q, got %v", i, test.err, err)
+		}
+	}
+}
@@ -0,0 +1,384 @@
+// Package assetbacking - Liquidity Generation Event of created tokens.
+package assetbacking
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+)
+
+// LGE status
+//
+// A token created with createAssetBackedTokenWithLGE holds its whole supply in
+// escrow at its own address and raises its backing from contributors until the
+// deadline, or until the cap is reached. Contributions are added to the pool's backing as
+// they arrive. Token transfers stay locked until the LGE has succeeded, after
+// which every contributor claims a share of the supply proportional to their
+// contribution. An LGE ending below its minimum fails: the contributors are
+// refunded instead, and the creator reclaims the initial backing while the
+// escrowed supply is burned.
+const (
+	LGENone      = 0 // The token was created without LGE
+	LGEActive    = 1 // Contributions are accepted
+	LGESucceeded = 2 // The supply can be claimed and transferred
+	LGEFailed    = 3 // The contributions can be refunded
+)
+
+// Storage slot offsets of the LGE state within LGENamespace.
+const (
+	SlotLGEDeadline      = 0
+	SlotLGECap           = 1
+	SlotLGEMinimum       = 2
+	SlotLGERaised        = 3
+	SlotLGEContributions = 4 // Mapping of contributor to contribution
+	SlotLGECreator       = 5 // Creator which funded the initial backing
+	SlotLGEInitial       = 6 // Initial backing not reclaimed by the creator
+)
+
+var lgeRoot = backingpool.NamespaceSlot(backingpool.LGENamespace)
+
+// lgeState is the LGE of a token.
+type lgeState struct {
+	Deadline uint64
+	Cap      *big.Int
+	Minimum  *big.Int
+	Raised   *big.Int
+}
+
+// lgeSlot returns the slot of the LGE field at offset.
+func lgeSlot(offset int) common.Hash {
+	return backingpool.FieldSlot(lgeRoot, offset)
+}
+
+// lgeContributionSlot returns the slot of the contribution of an account,
+// following the layout of a Solidity mapping.
+func lgeContributionSlot(account common.Address) common.Hash {
+	return crypto.Keccak256Hash(common.BytesToHash(account.Bytes()).Bytes(), lgeSlot(SlotLGEContributions).Bytes())
+}
+
+// LGESlots returns every slot the LGE state occupies in the token account,
+// including the contributions of the given accounts.
+func LGESlots(accounts []common.Address) []common.Hash {
+	slots := make([]common.Hash, 0, SlotLGEInitial+1+len(accounts))
+	for offset := SlotLGEDeadline; offset <= SlotLGEInitial; offset++ {
+		slots = append(slots, lgeSlot(offset))
+	}
+	for _, account := range accounts {
+		slots = append(slots, lgeContributionSlot(account))
+	}
+	return slots
+}
+
+// loadLGE loads the LGE of a token, nil if it was created without.
+func loadLGE(stateDB StateDB, token common.Address) *lgeState {
+	deadline := stateDB.GetState(token, lgeSlot(SlotLGEDeadline)).Big()
+	if deadline.Sign() == 0 {
+		return nil
+	}
+	return &lgeState{
+		Deadline: deadline.Uint64(),
+		Cap:      stateDB.GetState(token, lgeSlot(SlotLGECap)).Big(),
+		Minimum:  stateDB.GetState(token, lgeSlot(SlotLGEMinimum)).Big(),
+		Raised:   stateDB.GetState(token, lgeSlot(SlotLGERaised)).Big(),
+	}
+}
+
+// storeLGE stores the LGE of a token.
+func storeLGE(stateDB StateDB, token common.Address, lge *lgeState) {
+	stateDB.SetState(token, lgeSlot(SlotLGEDeadline), common.BigToHash(new(big.Int).SetUint64(lge.Deadline)))
+	stateDB.SetState(token, lgeSlot(SlotLGECap), common.BigToHash(lge.Cap))
+	stateDB.SetState(token, lgeSlot(SlotLGEMinimum), common.BigToHash(lge.Minimum))
+	stateDB.SetState(token, lgeSlot(SlotLGERaised), common.BigToHash(lge.Raised))
+}
+
+// openLGE opens the LGE of a token created by creator with the given initial
+// backing.
+func openLGE(stateDB StateDB, token, creator common.Address, lge *LGEConfig, initial *big.Int) {
+	storeLGE(stateDB, token, &lgeState{
+		Deadline: lge.Deadline,
+		Cap:      lge.Cap,
+		Minimum:  lge.Minimum,
+		Raised:   new(big.Int),
+	})
+	stateDB.SetState(token, lgeSlot(SlotLGECreator), common.BytesToHash(creator.Bytes()))
+	stateDB.SetState(token, lgeSlot(SlotLGEInitial), common.BigToHash(initial))
+}
+
+// status returns the status of the LGE at the given time.
+func (l *lgeState) status(now uint64) uint8 {
+	if l == nil {
+		return LGENone
+	}
+	capped := l.Cap.Sign() > 0 && l.Raised.Cmp(l.Cap) >= 0
+	if now < l.Deadline && !capped {
+		return LGEActive
+	}
+	// An LGE raising nothing has nothing to distribute the supply by
+	if l.Raised.Sign() == 0 || l.Raised.Cmp(l.Minimum) < 0 {
+		return LGEFailed
+	}
+	return LGESucceeded
+}
+
+// transfersLocked reports whether transfers of a token are locked by its LGE.
+func transfersLocked(ctx CallContext, token common.Address) bool {
+	status := loadLGE(ctx.StateDB, token).status(ctx.Time)
+	return status != LGENone && status != LGESucceeded
+}
+
+// validateLGE validates the LGE of a token configuration, nil if the token is
+// created without LGE.
+func validateLGE(ctx CallContext, config TokenConfig, lge *LGEConfig) error {
+	if config.EnableLGE != (lge != nil) {
+		return revert("InvalidTokenConfig") // LGE tokens are created by createAssetBackedTokenWithLGE
+	}
+	if lge == nil {
+		return nil
+	}
+	if lge.Deadline <= ctx.Time {
+		return revert("InvalidTokenConfig") // The LGE must end in the future
+	}
+	if lge.Cap.Sign() > 0 && lge.Cap.Cmp(lge.Minimum) < 0 {
+		return revert("InvalidTokenConfig") // The cap must allow reaching the minimum
+	}
+	return nil
+}
+
+// createAssetBackedTokenWithLGE creates a token which raises its backing in an
+// LGE before its supply is distributed.
+func (p *Precompile) createAssetBackedTokenWithLGE(ctx CallContext, input []byte) ([]byte, error) {
+	args, err := decodeInput("createAssetBackedTokenWithLGE", input)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != 2 {
+		return nil, revert("InvalidInput")
+	}
+	config, ok := abi.ConvertType(args[0], new(TokenConfig)).(*TokenConfig)
+	if !ok {
+		return nil, revert("InvalidInput")
+	}
+	lge, ok := abi.ConvertType(args[1], new(LGEConfig)).(*LGEConfig)
+	if !ok {
+		return nil, revert("InvalidInput")
+	}
+	return p.createToken(ctx, defaultTokenSalt(ctx.StateDB, ctx.Caller), *config, lge)
+}
+
+// contribute adds the Smart coin sent with the call to the backing of a token
+// in its LGE, crediting the caller with the contribution.
+func (p *Precompile) contribute(ctx CallContext, input []byte) ([]byte, error) {
+	if ctx.ReadOnly {
+		return nil, revert("StaticCallViolation")
+	}
+	args, err := decodeInput("contribute", input)
+	if err != nil {
+		return nil, err
+	}
+	stateDB, token := ctx.StateDB, args[0].(common.Address)
+
+	lge := loadLGE(stateDB, token)
+	if status := lge.status(ctx.Time); status != LGEActive {
+		return nil, revert("InvalidLGEStatus", token, status)
+	}
+	// The EVM has already transferred the value to the precompile
+	amount := new(big.Int)
+	if ctx.Value != nil {
+		amount = ctx.Value.ToBig()
+	}
+	if amount.Sign() == 0 {
+		return nil, revert("InvalidAmount", amount, amount)
+	}
+	if lge.Cap.Sign() > 0 {
+		if remaining := new(big.Int).Sub(lge.Cap, lge.Raised); amount.Cmp(remaining) > 0 {
+			return nil, revert("LGECapExceeded", remaining)
+		}
+	}
+	pool := backingpool.GetBackingPool(stateDB, token)
+	if pool == nil {
+		return nil, revert("PoolNotFound", token)
+	}
+	pool.AddBacking(amount)
+	backingpool.SetBackingPool(stateDB, pool)
+
+	lge.Raised.Add(lge.Raised, amount)
+	storeLGE(stateDB, token, lge)
+	contribution := stateDB.GetState(token, lgeContributionSlot(ctx.Caller)).Big()
+	stateDB.SetState(token, lgeContributionSlot(ctx.Caller), common.BigToHash(contribution.Add(contribution, amount)))
+
+	if err := emitEvent(ctx, "LGEContribution", token, ctx.Caller, amount); err != nil {
+		return nil, ErrExecutionReverted
+	}
+	if err := emitEvent(ctx, "BackingAdded", token, amount); err != nil {
+		return nil, ErrExecutionReverted
+	}
+	return nil, nil
+}
+
+// claim credits the caller with their share of the supply of a token whose
+// LGE has succeeded.
+func (p *Precompile) claim(ctx CallContext, input []byte) ([]byte, error) {
+	token, lge, contribution, err := settleContribution(ctx, "claim", input, LGESucceeded)
+	if err != nil {
+		return nil, err
+	}
+	stateDB := ctx.StateDB
+
+	// The share is paid out of the escrow, without fees
+	pool := backingpool.GetBackingPool(stateDB, token)
+	if pool == nil {
+		return nil, revert("PoolNotFound", token)
+	}
+	tokens := backingpool.MulDiv(pool.TotalSupply, contribution, lge.Raised, backingpool.RoundDown)
+
+	escrow := TokenBalance(stateDB, token, token)
+	if tokens.Cmp(escrow) > 0 {
+		tokens.Set(escrow)
+	}
+	setTokenBalance(stateDB, token, token, escrow.Sub(escrow, tokens))
+	setTokenBalance(stateDB, token, ctx.Caller, new(big.Int).Add(TokenBalance(stateDB, token, ctx.Caller), tokens))
+	emitTokenEvent(ctx, token, "Transfer", token, ctx.Caller, tokens)
+
+	if err := emitEvent(ctx, "LGEClaimed", token, ctx.Caller, tokens); err != nil {
+		return nil, ErrExecutionReverted
+	}
+	return EncodeOutput("claim", tokens)
+}
+
+// refund returns the contribution of the caller to a token whose LGE has failed.
+func (p *Precompile) refund(ctx CallContext, input []byte) ([]byte, error) {
+	token, _, contribution, err := settleContribution(ctx, "refund", input, LGEFailed)
+	if err != nil {
+		return nil, err
+	}
+	stateDB := ctx.StateDB
+
+	pool := backingpool.GetBackingPool(stateDB, token)
+	if pool == nil {
+		return nil, revert("PoolNotFound", token)
+	}
+	if locked := stateDB.GetBalance(PrecompileAddressBytes).ToBig(); contribution.Cmp(locked) > 0 || contribution.Cmp(pool.TotalBacking) > 0 {
+		return nil, revert("InsufficientBacking", locked, contribution)
+	}
+	pool.RemoveBacking(contribution)
+	backingpool.SetBackingPool(stateDB, pool)
+
+	amount, _ := uint256.FromBig(contribution)
+	stateDB.SubBalance(PrecompileAddressBytes, amount, tracing.BalanceChangeBackingRecovered)
+	stateDB.AddBalance(ctx.Caller, amount, tracing.BalanceChangeBackingRecovered)
+
+	if err := emitEvent(ctx, "LGERefunded", token, ctx.Caller, contribution); err != nil {
+		return nil, ErrExecutionReverted
+	}
+	return EncodeOutput("refund", contribution)
+}
+
+// reclaim winds down a token whose LGE has failed: the initial backing is
+// returned to the creator, and the escrowed supply, which no contributor can
+// claim anymore, is burned. Anyone may call it, the backing is always paid to
+// the creator.
+func (p *Precompile) reclaim(ctx CallContext, input []byte) ([]byte, error) {
+	if ctx.ReadOnly {
+		return nil, revert("StaticCallViolation")
+	}
+	args, err := decodeInput("reclaim", input)
+	if err != nil {
+		return nil, err
+	}
+	stateDB, token := ctx.StateDB, args[0].(common.Address)
+
+	if status := loadLGE(stateDB, token).status(ctx.Time); status != LGEFailed {
+		return nil, revert("InvalidLGEStatus", token, status)
+	}
+	pool := backingpool.GetBackingPool(stateDB, token)
+	if pool == nil {
+		return nil, revert("PoolNotFound", token)
+	}
+	creator := common.BytesToAddress(stateDB.GetState(token, lgeSlot(SlotLGECreator)).Bytes())
+	initial := stateDB.GetState(token, lgeSlot(SlotLGEInitial)).Big()
+	escrow := TokenBalance(stateDB, token, token)
+	if initial.Sign() == 0 && escrow.Sign() == 0 {
+		return nil, revert("NothingToClaim", creator)
+	}
+	if locked := stateDB.GetBalance(PrecompileAddressBytes).ToBig(); initial.Cmp(locked) > 0 || initial.Cmp(pool.TotalBacking) > 0 {
+		return nil, revert("InsufficientBacking", locked, initial)
+	}
+	// Burn the escrow, leaving only the backing owed to the contributors
+	if escrow.Sign() > 0 {
+		setTokenBalance(stateDB, token, token, new(big.Int))
+		pool.BurnTokens(escrow)
+		emitTokenEvent(ctx, token, "Transfer", token, common.Address{}, escrow)
+	}
+	pool.RemoveBacking(initial)
+	backingpool.SetBackingPool(stateDB, pool)
+	stateDB.SetState(token, lgeSlot(SlotLGEInitial), common.Hash{})
+
+	if initial.Sign() > 0 {
+		amount, _ := uint256.FromBig(initial)
+		stateDB.SubBalance(PrecompileAddressBytes, amount, tracing.BalanceChangeBackingRecovered)
+		stateDB.AddBalance(creator, amount, tracing.BalanceChangeBackingRecovered)
+
+		if err := emitEvent(ctx, "LGERefunded", token, creator, initial); err != nil {
+			return nil, ErrExecutionReverted
+		}
+	}
+	return EncodeOutput("reclaim", initial)
+}
+
+// settleContribution clears the contribution of the caller to the LGE of the
+// token named by the input of method, which must have the given status. It
+// returns the token, its LGE and the cleared contribution.
+func settleContribution(ctx CallContext, method string, input []byte, want uint8) (common.Address, *lgeState, *big.Int, error) {
+	if ctx.ReadOnly {
+		return common.Address{}, nil, nil, revert("StaticCallViolation")
+	}
+	args, err := decodeInput(method, input)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	stateDB, token := ctx.StateDB, args[0].(common.Address)
+
+	lge := loadLGE(stateDB, token)
+	if status := lge.status(ctx.Time); status != want {
+		return common.Address{}, nil, nil, revert("InvalidLGEStatus", token, status)
+	}
+	contribution := stateDB.GetState(token, lgeContributionSlot(ctx.Caller)).Big()
+	if contribution.Sign() == 0 {
+		return common.Address{}, nil, nil, revert("NothingToClaim", ctx.Caller)
+	}
+	stateDB.SetState(token, lgeContributionSlot(ctx.Caller), common.Hash{})
+	return token, lge, contribution, nil
+}
+
+// getLGEInfo returns the status and progress of the LGE of a token.
+func (p *Precompile) getLGEInfo(ctx CallContext, input []byte) ([]byte, error) {
+	args, err := decodeInput("getLGEInfo", input)
+	if err != nil {
+		return nil, err
+	}
+	token := args[0].(common.Address)
+	if backingpool.GetBackingPool(ctx.StateDB, token) == nil {
+		return nil, revert("PoolNotFound", token)
+	}
+	lge := loadLGE(ctx.StateDB, token)
+	if lge == nil {
+		lge = &lgeState{Cap: new(big.Int), Minimum: new(big.Int), Raised: new(big.Int)}
+	}
+	return precompileABI.Methods["getLGEInfo"].Outputs.Pack(lge.status(ctx.Time), lge.Deadline, lge.Cap, lge.Minimum, lge.Raised)
+}
+
+// getContribution returns the unsettled contribution of an account to the LGE
+// of a token.
+func (p *Precompile) getContribution(ctx CallContext, input []byte) ([]byte, error) {
+	args, err := decodeInput("getContribution", input)
+	if err != nil {
+		return nil, err
+	}
+	token, account := args[0].(common.Address), args[1].(common.Address)
+	return EncodeOutput("getContribution", ctx.StateDB.GetState(token, lgeContributionSlot(account)).Big())
+}
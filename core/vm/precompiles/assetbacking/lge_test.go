@@ -0,0 +1,310 @@
+// Package assetbacking - Tests for the Liquidity Generation Event of created tokens.
+package assetbacking
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/holiman/uint256"
+)
+
+// createLGEToken creates a token with an LGE ending at the given deadline.
+func createLGEToken(t *testing.T, stateDB *mockStateDB, owner common.Address, deadline uint64, capLGE, minimum *big.Int) common.Address {
+	t.Helper()
+
+	fees := [12]*big.Int{}
+	for i := range fees {
+		fees[i] = big.NewInt(0)
+	}
+	input, err := EncodeCreateTokenWithLGE(TokenConfig{
+		Name:           "LGE Token",
+		Symbol:         "LGE",
+		TotalSupply:    big.NewInt(1000000),
+		InitialBacking: big.NewInt(0),
+		Fees:           fees,
+		Owner:          owner,
+		EnableLGE:      true,
+	}, LGEConfig{Deadline: deadline, Cap: capLGE, Minimum: minimum})
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	result, err := (&Precompile{}).RunStateful(CallContext{StateDB: stateDB, Caller: owner, Time: 100}, input)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+	return common.BytesToAddress(result)
+}
+
+// lgeCall calls an LGE method of the precompile at the given time, sending
+// value with the call like the EVM does.
+func lgeCall(t *testing.T, stateDB *mockStateDB, caller common.Address, time uint64, value int64, method string, args ...interface{}) ([]interface{}, error) {
+	t.Helper()
+
+	input, err := precompileABI.Pack(method, args...)
+	if err != nil {
+		t.Fatalf("Failed to encode %s: %v", method, err)
+	}
+	if value > 0 {
+		balance := stateDB.balances[PrecompileAddressBytes]
+		if balance == nil {
+			balance = new(big.Int)
+		}
+		stateDB.balances[PrecompileAddressBytes] = new(big.Int).Add(balance, big.NewInt(value))
+	}
+	ctx := CallContext{StateDB: stateDB, Caller: caller, Time: time, Value: uint256.NewInt(uint64(value))}
+	result, err := (&Precompile{}).RunStateful(ctx, input)
+	if err != nil {
+		if value > 0 {
+			stateDB.balances[PrecompileAddressBytes].Sub(stateDB.balances[PrecompileAddressBytes], big.NewInt(value))
+		}
+		return nil, err
+	}
+	return precompileABI.Methods[method].Outputs.Unpack(result)
+}
+
+// expectRevertName checks that err is the custom error name.
+func expectRevertName(t *testing.T, err error, name string) {
+	t.Helper()
+
+	var revertErr *RevertError
+	if !errors.As(err, &revertErr) || revertErr.Name() != name {
+		t.Errorf("Expected %s, got %v", name, err)
+	}
+}
+
+// TestLGESuccess tests an LGE reaching its cap: contributions raise the
+// backing, transfers stay locked until it ends, and the supply is claimed in
+// proportion to the contributions.
+func TestLGESuccess(t *testing.T) {
+	stateDB := newMockStateDB()
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	alice := common.HexToAddress("0xa11ce")
+	bob := common.HexToAddress("0xb0b")
+	token := createLGEToken(t, stateDB, owner, 1000, big.NewInt(4000), big.NewInt(2000))
+
+	// The supply is held in escrow by the token
+	if balance := TokenBalance(stateDB, token, token); balance.Cmp(big.NewInt(1000000)) != 0 {
+		t.Fatalf("Expected escrow of 1000000, got %s", balance)
+	}
+	if balance := TokenBalance(stateDB, token, owner); balance.Sign() != 0 {
+		t.Errorf("Expected no balance for the owner, got %s", balance)
+	}
+
+	// Contributions are added to the backing
+	if _, err := lgeCall(t, stateDB, alice, 200, 1000, "contribute", token); err != nil {
+		t.Fatalf("Failed to contribute: %v", err)
+	}
+	if _, err := lgeCall(t, stateDB, bob, 300, 2000, "contribute", token); err != nil {
+		t.Fatalf("Failed to contribute: %v", err)
+	}
+	_, err := lgeCall(t, stateDB, alice, 300, 1001, "contribute", token)
+	expectRevertName(t, err, "LGECapExceeded")
+
+	out, err := lgeCall(t, stateDB, alice, 300, 0, "getLGEInfo", token)
+	if err != nil {
+		t.Fatalf("Failed to get LGE info: %v", err)
+	}
+	if out[0].(uint8) != LGEActive || out[1].(uint64) != 1000 || out[4].(*big.Int).Cmp(big.NewInt(3000)) != 0 {
+		t.Errorf("Unexpected LGE info %v", out)
+	}
+	if pool := backingpool.GetBackingPool(stateDB, token); pool.TotalBacking.Cmp(big.NewInt(3000)) != 0 {
+		t.Errorf("Expected total backing 3000, got %s", pool.TotalBacking)
+	}
+
+	// Claims and transfers wait for the end of the LGE
+	_, err = lgeCall(t, stateDB, alice, 300, 0, "claim", token)
+	expectRevertName(t, err, "InvalidLGEStatus")
+
+	// Reaching the cap ends the LGE before the deadline
+	if _, err := lgeCall(t, stateDB, alice, 400, 1000, "contribute", token); err != nil {
+		t.Fatalf("Failed to contribute: %v", err)
+	}
+	out, err = lgeCall(t, stateDB, alice, 400, 0, "getContribution", token, alice)
+	if err != nil || out[0].(*big.Int).Cmp(big.NewInt(2000)) != 0 {
+		t.Errorf("Expected contribution 2000, got %v (%v)", out, err)
+	}
+	out, _ = lgeCall(t, stateDB, alice, 400, 0, "getLGEInfo", token)
+	if out[0].(uint8) != LGESucceeded {
+		t.Errorf("Expected LGE to have succeeded, got status %d", out[0])
+	}
+	_, err = lgeCall(t, stateDB, bob, 400, 1, "contribute", token)
+	expectRevertName(t, err, "InvalidLGEStatus")
+
+	// Each contributor claims their share once
+	for holder, want := range map[common.Address]int64{alice: 500000, bob: 500000} {
+		out, err := lgeCall(t, stateDB, holder, 400, 0, "claim", token)
+		if err != nil {
+			t.Fatalf("Failed to claim: %v", err)
+		}
+		if out[0].(*big.Int).Cmp(big.NewInt(want)) != 0 || TokenBalance(stateDB, token, holder).Cmp(big.NewInt(want)) != 0 {
+			t.Errorf("Expected claim of %d, got %v", want, out[0])
+		}
+	}
+	_, err = lgeCall(t, stateDB, alice, 400, 0, "claim", token)
+	expectRevertName(t, err, "NothingToClaim")
+	_, err = lgeCall(t, stateDB, owner, 400, 0, "refund", token)
+	expectRevertName(t, err, "InvalidLGEStatus")
+
+	// Transfers are unlocked, and the floor price reflects the raised backing
+	if _, err := callToken(CallContext{StateDB: stateDB, Address: token, Caller: alice, Time: 400}, "transfer", bob, big.NewInt(100)); err != nil {
+		t.Errorf("Failed to transfer after the LGE: %v", err)
+	}
+	want := new(big.Int).Div(new(big.Int).Mul(big.NewInt(4000), big.NewInt(1e18)), big.NewInt(1000000))
+	if price := backingpool.GetBackingPool(stateDB, token).CalculateFloorPrice(); price.Cmp(want) != 0 {
+		t.Errorf("Expected floor price %s, got %s", want, price)
+	}
+}
+
+// TestLGEFailure tests that an LGE ending below its minimum locks the token
+// for good and refunds the contributors.
+func TestLGEFailure(t *testing.T) {
+	stateDB := newMockStateDB()
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	alice := common.HexToAddress("0xa11ce")
+	token := createLGEToken(t, stateDB, owner, 1000, big.NewInt(0), big.NewInt(5000))
+
+	if _, err := lgeCall(t, stateDB, alice, 200, 1000, "contribute", token); err != nil {
+		t.Fatalf("Failed to contribute: %v", err)
+	}
+	// Transfers are locked during the LGE, even for the escrow
+	if _, err := callToken(CallContext{StateDB: stateDB, Address: token, Caller: owner, Time: 200}, "approve", alice, big.NewInt(1)); err != nil {
+		t.Errorf("Failed to approve during the LGE: %v", err)
+	}
+	_, err := callToken(CallContext{StateDB: stateDB, Address: token, Caller: token, Time: 200}, "transfer", alice, big.NewInt(1))
+	expectRevertName(t, err, "TransfersLocked")
+
+	// Refunds are only possible once the deadline passed without the minimum
+	_, err = lgeCall(t, stateDB, alice, 999, 0, "refund", token)
+	expectRevertName(t, err, "InvalidLGEStatus")
+	out, err := lgeCall(t, stateDB, alice, 1000, 0, "getLGEInfo", token)
+	if err != nil || out[0].(uint8) != LGEFailed {
+		t.Fatalf("Expected LGE to have failed, got %v (%v)", out, err)
+	}
+	_, err = lgeCall(t, stateDB, alice, 1000, 0, "claim", token)
+	expectRevertName(t, err, "InvalidLGEStatus")
+
+	out, err = lgeCall(t, stateDB, alice, 1000, 0, "refund", token)
+	if err != nil {
+		t.Fatalf("Failed to refund: %v", err)
+	}
+	if out[0].(*big.Int).Cmp(big.NewInt(1000)) != 0 || stateDB.balances[alice].Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("Expected refund of 1000, got %v", out[0])
+	}
+	if locked := stateDB.balances[PrecompileAddressBytes]; locked.Sign() != 0 {
+		t.Errorf("Expected no Smart coin left locked, got %s", locked)
+	}
+	if pool := backingpool.GetBackingPool(stateDB, token); pool.TotalBacking.Sign() != 0 {
+		t.Errorf("Expected no backing left, got %s", pool.TotalBacking)
+	}
+	_, err = lgeCall(t, stateDB, alice, 1000, 0, "refund", token)
+	expectRevertName(t, err, "NothingToClaim")
+	_, err = callToken(CallContext{StateDB: stateDB, Address: token, Caller: token, Time: 1000}, "transfer", alice, big.NewInt(1))
+	expectRevertName(t, err, "TransfersLocked")
+}
+
+// TestLGEReclaim tests that the initial backing of a token whose LGE failed is
+// returned to its creator and the escrowed supply burned, while the
+// contributions stay refundable.
+func TestLGEReclaim(t *testing.T) {
+	stateDB := newMockStateDB()
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	alice := common.HexToAddress("0xa11ce")
+	bob := common.HexToAddress("0xb0b")
+
+	fees := [12]*big.Int{}
+	for i := range fees {
+		fees[i] = big.NewInt(0)
+	}
+	config := TokenConfig{
+		Name:           "LGE Token",
+		Symbol:         "LGE",
+		TotalSupply:    big.NewInt(1000000),
+		InitialBacking: big.NewInt(500),
+		Fees:           fees,
+		Owner:          owner,
+		EnableLGE:      true,
+	}
+	out, err := lgeCall(t, stateDB, owner, 100, 500, "createAssetBackedTokenWithLGE", config, LGEConfig{Deadline: 1000, Cap: big.NewInt(0), Minimum: big.NewInt(5000)})
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+	token := out[0].(common.Address)
+	if _, err := lgeCall(t, stateDB, alice, 200, 1000, "contribute", token); err != nil {
+		t.Fatalf("Failed to contribute: %v", err)
+	}
+	_, err = lgeCall(t, stateDB, bob, 999, 0, "reclaim", token)
+	expectRevertName(t, err, "InvalidLGEStatus")
+
+	// Anyone may wind the token down, the backing goes to the creator
+	out, err = lgeCall(t, stateDB, bob, 1000, 0, "reclaim", token)
+	if err != nil {
+		t.Fatalf("Failed to reclaim: %v", err)
+	}
+	if out[0].(*big.Int).Cmp(big.NewInt(500)) != 0 || stateDB.balances[owner].Cmp(big.NewInt(500)) != 0 {
+		t.Errorf("Expected reclaim of 500, got %v", out[0])
+	}
+	if balance := stateDB.balances[bob]; balance != nil && balance.Sign() != 0 {
+		t.Errorf("Expected no Smart coin for the caller, got %s", balance)
+	}
+	if escrow := TokenBalance(stateDB, token, token); escrow.Sign() != 0 {
+		t.Errorf("Expected escrow to be burned, got %s", escrow)
+	}
+	pool := backingpool.GetBackingPool(stateDB, token)
+	if pool.BurnedSupply.Cmp(big.NewInt(1000000)) != 0 {
+		t.Errorf("Expected burned supply 1000000, got %s", pool.BurnedSupply)
+	}
+	if pool.TotalBacking.Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("Expected backing 1000 left for the contributors, got %s", pool.TotalBacking)
+	}
+	_, err = lgeCall(t, stateDB, owner, 1000, 0, "reclaim", token)
+	expectRevertName(t, err, "NothingToClaim")
+
+	if _, err := lgeCall(t, stateDB, alice, 1000, 0, "refund", token); err != nil {
+		t.Fatalf("Failed to refund: %v", err)
+	}
+	if locked := stateDB.balances[PrecompileAddressBytes]; locked.Sign() != 0 {
+		t.Errorf("Expected no Smart coin left locked, got %s", locked)
+	}
+}
+
+// TestLGEConfig tests the validation of the LGE parameters at creation.
+func TestLGEConfig(t *testing.T) {
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	fees := [12]*big.Int{}
+	for i := range fees {
+		fees[i] = big.NewInt(0)
+	}
+	for i, test := range []struct {
+		enable bool
+		lge    *LGEConfig
+	}{
+		{true, nil}, // LGE requested from createAssetBackedToken
+		{false, &LGEConfig{Deadline: 1000, Cap: big.NewInt(0), Minimum: big.NewInt(0)}}, // LGE without EnableLGE
+		{true, &LGEConfig{Deadline: 100, Cap: big.NewInt(0), Minimum: big.NewInt(0)}},   // Deadline not in the future
+		{true, &LGEConfig{Deadline: 1000, Cap: big.NewInt(1), Minimum: big.NewInt(2)}},  // Cap below the minimum
+	} {
+		config := TokenConfig{
+			Name:           "LGE Token",
+			Symbol:         "LGE",
+			TotalSupply:    big.NewInt(1000),
+			InitialBacking: big.NewInt(0),
+			Fees:           fees,
+			Owner:          owner,
+			EnableLGE:      test.enable,
+		}
+		input, err := EncodeCreateToken(config)
+		if test.lge != nil {
+			input, err = EncodeCreateTokenWithLGE(config, *test.lge)
+		}
+		if err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+		_, err = (&Precompile{}).RunStateful(CallContext{StateDB: newMockStateDB(), Caller: owner, Time: 100}, input)
+		if !errors.Is(err, ErrExecutionReverted) {
+			t.Errorf("Test %d: expected invalid configuration to revert, got %v", i, err)
+		}
+	}
+}
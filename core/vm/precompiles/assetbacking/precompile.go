@@ -1,5 +1,24 @@
 // Package assetbacking implements the native asset-backed token precompile
-// Address: 0x0000000000000000000000000000000000000100
+// at address 0x0000000000000000000000000000000000005def.
+//
+// The precompile is installed once params.ChainConfig.SmartDeFiTime is reached.
+// Chains which ran it before the fork did so at 0x100, the address P256VERIFY
+// (EIP-7951) takes at Osaka; the fork moves the backing locked there, see
+// MigrateLegacyBacking.
+//
+// Contracts reach it with CALL, in which case the calling contract is
+// msg.sender, or with STATICCALL for the view methods. The precompile owns its
+// storage and the Smart coin locked as backing, so DELEGATECALL and CALLCODE
+// into it always revert.
+//
+// Every created token is an ERC-20 at its own address, whose account carries
+// TokenCode and is executed natively by the Token contract. Its backing pool,
+// fees and LGE live in ERC-7201 namespaces of the token account, the router
+// allow-list and token registry in namespaces of the precompile account.
+//
+// Only the createAssetBackedToken methods, contribute and buyFeeReserve are
+// payable; their msg.value is the only Smart coin moved into the precompile.
+// Every other method reverts when value is sent.
 package assetbacking
 
 import (
@@ -7,26 +26,48 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	
+	"github.com/holiman/uint256"
+
 	"github.com/ethereum/go-ethereum/core/state/backingpool"
 )
 
-// ErrExecutionReverted is returned when execution reverts
+// ErrExecutionReverted is returned when execution reverts.
 var ErrExecutionReverted = errors.New("execution reverted")
 
-// StateDB interface to avoid circular import with vm package
+// StateDB interface to avoid circular import with vm package.
+// The method set mirrors vm.StateDB so the EVM's state can be passed directly.
 type StateDB interface {
 	GetState(common.Address, common.Hash) common.Hash
-	SetState(common.Address, common.Hash, common.Hash)
-	GetBalance(common.Address) *big.Int
-	AddBalance(common.Address, *big.Int)
-	SubBalance(common.Address, *big.Int)
+	SetState(common.Address, common.Hash, common.Hash) common.Hash
+	GetBalance(common.Address) *uint256.Int
+	AddBalance(common.Address, *uint256.Int, tracing.BalanceChangeReason) uint256.Int
+	SubBalance(common.Address, *uint256.Int, tracing.BalanceChangeReason) uint256.Int
+	GetCode(common.Address) []byte
 	GetCodeSize(common.Address) int
+	SetCode(common.Address, []byte, tracing.CodeChangeReason) []byte
 	GetNonce(common.Address) uint64
+	SetNonce(common.Address, uint64, tracing.NonceChangeReason)
+	AddLog(*types.Log)
+}
+
+// CallContext is the execution context of a single precompile call.
+// It is assembled by the EVM for every invocation and never stored, so one
+// Precompile instance can safely serve concurrently running EVMs.
+type CallContext struct {
+	StateDB     StateDB
+	Caller      common.Address // msg.sender of the precompile call
+	Address     common.Address // Address of the called contract
+	Value       *uint256.Int   // msg.value of the precompile call
+	ReadOnly    bool           // Whether state modifications are disallowed
+	Depth       int            // Call depth the precompile is executed at
+	BlockNumber uint64         // Number of the block the call is executed in
+	Time        uint64         // Timestamp of the block the call is executed in
 }
 
-// PrecompiledContract interface (to avoid circular import)
+// PrecompiledContract interface (to avoid circular import).
 type PrecompiledContract interface {
 	RequiredGas(input []byte) uint64
 	Run(input []byte) ([]byte, error)
@@ -34,44 +75,102 @@ type PrecompiledContract interface {
 }
 
 const (
-	// PrecompileAddress is the address where this precompile is deployed
-	PrecompileAddress = "0x0000000000000000000000000000000000000100"
-	
+	// PrecompileAddress is the address where this precompile is deployed.
+	PrecompileAddress = "0x0000000000000000000000000000000000005def"
+
+	// LegacyPrecompileAddress is the address the precompile was deployed at
+	// before the SmartDeFi fork. It is taken by P256VERIFY at Osaka.
+	LegacyPrecompileAddress = "0x0000000000000000000000000000000000000100"
+
 	// Gas costs
-	GasCreateToken      = 100000  // Base cost for token creation
-	GasGetBacking       = 5000    // Cost for getting backing info
-	GasBurnAndRecover   = 30000   // Cost for burn and recover
-	GasPerByte          = 200     // Additional gas per byte of data
+	// These are the base costs of the computation only. The EVM additionally
+	// charges every storage access, balance change, deployed code byte and log
+	// while the call runs, at the prices of the equivalent opcodes.
+	GasCreateToken    = 32000 // Base cost for token creation
+	GasGetBacking     = 1000  // Cost for getting backing info
+	GasBurnAndRecover = 5000  // Cost for burn and recover
+	GasAdmin          = 2000  // Cost for owner administration
+	GasLGE            = 5000  // Cost for LGE contributions, claims and refunds
+	GasPerByte        = 16    // Additional gas per byte of data
+
+	// tokenConfigType is the ABI signature of the TokenConfig tuple.
+	tokenConfigType = "(string,string,uint256,address,uint256,uint256[12],bool,address,bool)"
+
+	// lgeConfigType is the ABI signature of the LGEConfig tuple.
+	lgeConfigType = "(uint64,uint256,uint256)"
 )
 
 var (
-	// PrecompileAddressBytes is the address as bytes
+	// PrecompileAddressBytes is the address as bytes.
 	PrecompileAddressBytes = common.HexToAddress(PrecompileAddress)
-	
-	// Method IDs (first 4 bytes of keccak256 hash of function signature)
-	MethodIDCreateToken    = crypto.Keccak256([]byte("createAssetBackedToken((string,string,uint256,address,uint256,uint256[12],bool,address,bool))"))[:4]
+
+	// LegacyPrecompileAddressBytes is the legacy address as bytes.
+	LegacyPrecompileAddressBytes = common.HexToAddress(LegacyPrecompileAddress)
+
+	// Method IDs (first 4 bytes of keccak256 hash of function signature).
+	MethodIDCreateToken    = crypto.Keccak256([]byte("createAssetBackedToken(" + tokenConfigType + ")"))[:4]
 	MethodIDGetBacking     = crypto.Keccak256([]byte("getBacking(address,uint256)"))[:4]
 	MethodIDBurnAndRecover = crypto.Keccak256([]byte("burnAndRecover(address,uint256)"))[:4]
 	MethodIDGetFloorPrice  = crypto.Keccak256([]byte("getFloorPrice(address)"))[:4]
+	MethodIDBuyFeeReserve  = crypto.Keccak256([]byte("buyFeeReserve(address,uint256)"))[:4]
+
+	// Method IDs of the salted token creation.
+	MethodIDCreateTokenWithSalt = crypto.Keccak256([]byte("createAssetBackedTokenWithSalt(bytes32," + tokenConfigType + ")"))[:4]
+	MethodIDComputeTokenAddress = crypto.Keccak256([]byte("computeTokenAddress(address,bytes32," + tokenConfigType + ")"))[:4]
+
+	// Method IDs of the Liquidity Generation Event.
+	MethodIDCreateTokenWithLGE = crypto.Keccak256([]byte("createAssetBackedTokenWithLGE(" + tokenConfigType + "," + lgeConfigType + ")"))[:4]
+	MethodIDContribute         = crypto.Keccak256([]byte("contribute(address)"))[:4]
+	MethodIDClaim              = crypto.Keccak256([]byte("claim(address)"))[:4]
+	MethodIDRefund             = crypto.Keccak256([]byte("refund(address)"))[:4]
+	MethodIDReclaim            = crypto.Keccak256([]byte("reclaim(address)"))[:4]
+	MethodIDGetLGEInfo         = crypto.Keccak256([]byte("getLGEInfo(address)"))[:4]
+	MethodIDGetContribution    = crypto.Keccak256([]byte("getContribution(address,address)"))[:4]
+
+	// Method IDs of the router allow-list.
+	MethodIDRouterAdmin         = crypto.Keccak256([]byte("routerAdmin()"))[:4]
+	MethodIDTransferRouterAdmin = crypto.Keccak256([]byte("transferRouterAdmin(address)"))[:4]
+	MethodIDIsRouter            = crypto.Keccak256([]byte("isRouter(address)"))[:4]
+	MethodIDSetRouter           = crypto.Keccak256([]byte("setRouter(address,bool)"))[:4]
+
+	// Method IDs of the token registry.
+	MethodIDTokenCount      = crypto.Keccak256([]byte("tokenCount()"))[:4]
+	MethodIDTokenAt         = crypto.Keccak256([]byte("tokenAt(uint256)"))[:4]
+	MethodIDTokensByCreator = crypto.Keccak256([]byte("tokensByCreator(address)"))[:4]
+
+	// Method IDs of the owner administration.
+	MethodIDOwner             = crypto.Keccak256([]byte("owner(address)"))[:4]
+	MethodIDTransferOwnership = crypto.Keccak256([]byte("transferOwnership(address,address)"))[:4]
+	MethodIDRenounceOwnership = crypto.Keccak256([]byte("renounceOwnership(address)"))[:4]
+	MethodIDSetFees           = crypto.Keccak256([]byte("setFees(address,uint256[12])"))[:4]
+	MethodIDSetOnlySB         = crypto.Keccak256([]byte("setOnlySB(address,bool)"))[:4]
 )
 
-// TokenConfig represents the configuration for creating an asset-backed token
-// Note: All tokens are backed by Smart coin only (native coin)
+// TokenConfig represents the configuration for creating an asset-backed token.
+// Note: All tokens are backed by Smart coin only (native coin).
 type TokenConfig struct {
-	Name          string
-	Symbol        string
-	TotalSupply   *big.Int
+	Name        string
+	Symbol      string
+	TotalSupply *big.Int
 	// BackingAsset is always Smart coin (native coin) - address(0) or native
-	// This field is kept for future compatibility but will be enforced as Smart
-	BackingAsset  common.Address // Must be address(0) for Smart coin
-	InitialBacking *big.Int      // Amount of Smart coin to lock as backing
-	Fees          [12]*big.Int
-	OnlySB        bool
-	Owner         common.Address
-	EnableLGE     bool
+	// This field is kept for future compatibility but will be enforced as Smart.
+	BackingAsset   common.Address // Must be address(0) for Smart coin
+	InitialBacking *big.Int       // Amount of Smart coin to lock as backing
+	Fees           [12]*big.Int
+	OnlySB         bool
+	Owner          common.Address
+	EnableLGE      bool // Must be set if, and only if, the token is created with an LGE
+}
+
+// LGEConfig represents the Liquidity Generation Event of a token created with
+// createAssetBackedTokenWithLGE.
+type LGEConfig struct {
+	Deadline uint64   // Timestamp at which the LGE ends
+	Cap      *big.Int // Smart coin raised at which the LGE ends early, zero for none
+	Minimum  *big.Int // Smart coin the LGE must raise to succeed
 }
 
-// BackingInfo represents backing information for a token
+// BackingInfo represents backing information for a token.
 type BackingInfo struct {
 	BackingAsset    common.Address
 	TotalBacking    *big.Int
@@ -81,326 +180,456 @@ type BackingInfo struct {
 	BackingPerToken *big.Int
 }
 
-// Precompile implements the asset backing precompile
-type Precompile struct {
-	stateDB StateDB
-	caller  common.Address // For testing - caller address
-	value   *big.Int       // For testing - call value
-}
-
-// NewPrecompile creates a new asset backing precompile instance
-func NewPrecompile(stateDB StateDB) *Precompile {
-	return &Precompile{
-		stateDB: stateDB,
-	}
-}
-
-// SetStateDB sets the state database for the precompile
-func (p *Precompile) SetStateDB(stateDB StateDB) {
-	p.stateDB = stateDB
-}
-
-// SetCaller sets the caller address (for testing)
-func (p *Precompile) SetCaller(caller common.Address) {
-	p.caller = caller
-}
-
-// SetValue sets the call value (for testing)
-func (p *Precompile) SetValue(value *big.Int) {
-	p.value = value
-}
+// Precompile implements the asset backing precompile.
+// It holds no fields: all call-specific data is passed in a CallContext.
+type Precompile struct{}
 
-// Name returns the precompile name
+// Name returns the precompile name.
 func (p *Precompile) Name() string {
 	return "SmartDeFi Asset Backing"
 }
 
-// RequiredGas calculates the gas required for the precompile operation
+// RequiredGas calculates the gas required for the precompile operation.
 func (p *Precompile) RequiredGas(input []byte) uint64 {
 	if len(input) < 4 {
 		return 0
 	}
-	
+
 	methodID := input[:4]
-	
+
 	switch {
-	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDCreateToken):
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDCreateToken),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDCreateTokenWithSalt),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDCreateTokenWithLGE):
 		// Base cost + data size cost
 		return GasCreateToken + uint64(len(input)-4)*GasPerByte
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDComputeTokenAddress):
+		return GasGetBacking + uint64(len(input)-4)*GasPerByte
 	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDGetBacking):
 		return GasGetBacking
-	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDBurnAndRecover):
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDBurnAndRecover),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDBuyFeeReserve):
 		return GasBurnAndRecover
 	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDGetFloorPrice):
 		return GasGetBacking
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDContribute),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDClaim),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDRefund),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDReclaim):
+		return GasLGE
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDGetLGEInfo),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDGetContribution),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDOwner),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDRouterAdmin),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDIsRouter),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDTokenCount),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDTokenAt),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDTokensByCreator):
+		return GasGetBacking
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDTransferOwnership),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDRenounceOwnership),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDSetFees),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDSetOnlySB),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDTransferRouterAdmin),
+		common.BytesToHash(methodID) == common.BytesToHash(MethodIDSetRouter):
+		return GasAdmin
 	default:
 		return 0
 	}
 }
 
-// Run executes the precompile logic (implements PrecompiledContract interface)
+// Run implements the stateless PrecompiledContract interface.
+// The precompile cannot operate without state, so a call without a context reverts.
 func (p *Precompile) Run(input []byte) ([]byte, error) {
-	if p.stateDB == nil {
+	return nil, ErrExecutionReverted
+}
+
+// RunStateful executes the precompile logic within the given call context.
+func (p *Precompile) RunStateful(ctx CallContext, input []byte) ([]byte, error) {
+	if ctx.StateDB == nil {
 		return nil, ErrExecutionReverted
 	}
-	
+
 	if len(input) < 4 {
-		return nil, ErrExecutionReverted
+		return nil, revert("InvalidInput")
 	}
-	
+
 	methodID := input[:4]
-	
-	// For now, we'll need caller and value from EVM context
-	// This is a simplified version - full implementation needs EVM modification
-	// Get caller and value (use stored values if available, otherwise zero)
-	caller := p.caller
-	if caller == (common.Address{}) {
-		// In production, caller will be set by EVM via SetCaller before Run
-		// For now, we'll use zero address which will cause some operations to fail
-		caller = common.Address{}
-	}
-	value := p.value
-	if value == nil {
-		value = big.NewInt(0)
+
+	// Only token creation, LGE contributions and fee reserve sales are payable
+	isCreate := common.BytesToHash(methodID) == common.BytesToHash(MethodIDCreateToken)
+	isCreateWithSalt := common.BytesToHash(methodID) == common.BytesToHash(MethodIDCreateTokenWithSalt)
+	isCreateWithLGE := common.BytesToHash(methodID) == common.BytesToHash(MethodIDCreateTokenWithLGE)
+	isContribute := common.BytesToHash(methodID) == common.BytesToHash(MethodIDContribute)
+	isBuyFeeReserve := common.BytesToHash(methodID) == common.BytesToHash(MethodIDBuyFeeReserve)
+	if !isCreate && !isCreateWithSalt && !isCreateWithLGE && !isContribute && !isBuyFeeReserve && ctx.Value != nil && !ctx.Value.IsZero() {
+		return nil, revert("NonPayable", ctx.Value.ToBig())
 	}
-	
+
 	switch {
-	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDCreateToken):
-		return p.createAssetBackedToken(input[4:], caller, value, false)
+	case isCreate:
+		return p.createAssetBackedToken(ctx, input[4:])
+	case isCreateWithSalt:
+		return p.createAssetBackedTokenWithSalt(ctx, input[4:])
+	case isCreateWithLGE:
+		return p.createAssetBackedTokenWithLGE(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDComputeTokenAddress):
+		return p.computeTokenAddress(ctx, input[4:])
 	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDGetBacking):
-		return p.getBacking(input[4:], true)
+		return p.getBacking(ctx, input[4:])
 	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDBurnAndRecover):
-		return p.burnAndRecover(input[4:], caller, false)
+		return p.burnAndRecover(ctx, input[4:])
 	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDGetFloorPrice):
-		return p.getFloorPrice(input[4:], true)
+		return p.getFloorPrice(ctx, input[4:])
+	case isBuyFeeReserve:
+		return p.buyFeeReserve(ctx, input[4:])
+	case isContribute:
+		return p.contribute(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDClaim):
+		return p.claim(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDRefund):
+		return p.refund(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDReclaim):
+		return p.reclaim(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDGetLGEInfo):
+		return p.getLGEInfo(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDGetContribution):
+		return p.getContribution(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDRouterAdmin):
+		return p.routerAdmin(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDTransferRouterAdmin):
+		return p.transferRouterAdmin(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDIsRouter):
+		return p.isRouter(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDSetRouter):
+		return p.setRouter(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDTokenCount):
+		return p.tokenCount(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDTokenAt):
+		return p.tokenAt(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDTokensByCreator):
+		return p.tokensByCreator(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDOwner):
+		return p.owner(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDTransferOwnership):
+		return p.transferOwnership(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDRenounceOwnership):
+		return p.renounceOwnership(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDSetFees):
+		return p.setFees(ctx, input[4:])
+	case common.BytesToHash(methodID) == common.BytesToHash(MethodIDSetOnlySB):
+		return p.setOnlySB(ctx, input[4:])
 	default:
-		return nil, ErrExecutionReverted
+		return nil, revert("InvalidInput")
 	}
 }
 
-// createAssetBackedToken creates a new asset-backed token natively on the chain
-func (p *Precompile) createAssetBackedToken(input []byte, caller common.Address, value *big.Int, readOnly bool) ([]byte, error) {
-	if readOnly {
-		return nil, ErrExecutionReverted
-	}
-	
-	// Check caller is not zero (required for token creation)
-	if caller == (common.Address{}) {
-		return nil, ErrExecutionReverted
-	}
-	
+// createAssetBackedToken creates a new asset-backed token natively on the chain.
+// The token address is salted with the number of tokens the caller created
+// before (see DefaultTokenSalt), so repeated creations never collide, even
+// within a transaction.
+func (p *Precompile) createAssetBackedToken(ctx CallContext, input []byte) ([]byte, error) {
 	// Decode TokenConfig from input
 	config, err := DecodeCreateTokenInput(input)
 	if err != nil {
-		return nil, ErrExecutionReverted
+		return nil, revert("InvalidInput")
+	}
+	return p.createToken(ctx, defaultTokenSalt(ctx.StateDB, ctx.Caller), config, nil)
+}
+
+// createToken creates a token with the given configuration at the address
+// derived from the caller and salt. The LGE is nil for tokens without LGE.
+func (p *Precompile) createToken(ctx CallContext, salt common.Hash, config TokenConfig, lge *LGEConfig) ([]byte, error) {
+	if ctx.ReadOnly {
+		return nil, revert("StaticCallViolation")
+	}
+	stateDB, caller := ctx.StateDB, ctx.Caller
+
+	// Check caller is not zero (required for token creation)
+	if caller == (common.Address{}) {
+		return nil, revert("ZeroAddress")
 	}
-	
+
 	// Validate configuration
 	if err := validateTokenConfig(config); err != nil {
-		return nil, ErrExecutionReverted
+		return nil, err
 	}
-	
+	if err := validateLGE(ctx, config, lge); err != nil {
+		return nil, err
+	}
+
 	// Enforce Smart coin as only backing asset
 	// BackingAsset must be address(0) for native Smart coin
 	if config.BackingAsset != (common.Address{}) {
-		return nil, ErrExecutionReverted // Only Smart coin supported
-	}
-	
-	// Check caller has sufficient balance for initial backing
-	if config.InitialBacking.Cmp(big.NewInt(0)) > 0 {
-		callerBalance := p.stateDB.GetBalance(caller)
-		if callerBalance.Cmp(config.InitialBacking) < 0 {
-			return nil, ErrExecutionReverted // Insufficient balance
-		}
+		return nil, revert("UnsupportedBackingAsset", config.BackingAsset) // Only Smart coin supported
 	}
-	
-	// Create deterministic token address (CREATE2-like)
-	// Using caller address + nonce + config hash for determinism
-	nonce := p.stateDB.GetNonce(caller)
-	configHash := crypto.Keccak256Hash(
-		caller.Bytes(),
-		common.BigToHash(big.NewInt(int64(nonce))).Bytes(),
-		[]byte(config.Name),
-		[]byte(config.Symbol),
-		config.TotalSupply.Bytes(),
-	)
-	tokenAddress := common.BytesToAddress(configHash[:20])
-	
-	// Check if token already exists
-	if p.stateDB.GetCodeSize(tokenAddress) > 0 {
-		return nil, ErrExecutionReverted // Token already exists
-	}
-	
+
+	// The initial backing is funded by the Smart coin sent with the call, which
+	// the EVM has already transferred to the precompile
+	value := new(big.Int)
+	if ctx.Value != nil {
+		value = ctx.Value.ToBig()
+	}
+	if value.Cmp(config.InitialBacking) != 0 {
+		return nil, revert("ValueMismatch", value, config.InitialBacking) // Value must match the initial backing
+	}
+
+	// Derive the token address from the caller, salt and configuration
+	// (CREATE2-like), so it can be computed before creation
+	tokenAddress, err := TokenAddress(caller, salt, config)
+	if err != nil {
+		return nil, revert("InvalidInput")
+	}
+
+	// Check if token already exists, like CREATE2 refuses accounts with code
+	// or nonce. Within a transaction the token deployed by an earlier creation
+	// with the same salt and configuration is found here as well
+	if stateDB.GetCodeSize(tokenAddress) > 0 || stateDB.GetNonce(tokenAddress) > 0 {
+		return nil, revert("TokenExists", tokenAddress) // Token already exists
+	}
+
 	// Initialize backing pool with Smart coin (native coin)
 	// BackingAsset is always address(0) for Smart coin
 	smartCoinAddress := common.Address{} // Native Smart coin
-	
+
 	pool := &backingpool.BackingPool{
-		TokenAddress:  tokenAddress,
-		BackingAsset:  smartCoinAddress, // Always Smart coin
-		TotalBacking:  new(big.Int).Set(config.InitialBacking),
-		TotalSupply:   new(big.Int).Set(config.TotalSupply),
-		BurnedSupply:  big.NewInt(0),
-		BackingAssets: []common.Address{smartCoinAddress}, // Only Smart coin
+		TokenAddress:   tokenAddress,
+		BackingAsset:   smartCoinAddress, // Always Smart coin
+		TotalBacking:   new(big.Int).Set(config.InitialBacking),
+		TotalSupply:    new(big.Int).Set(config.TotalSupply),
+		BurnedSupply:   big.NewInt(0),
+		BackingAssets:  []common.Address{smartCoinAddress}, // Only Smart coin
 		BackingAmounts: []*big.Int{new(big.Int).Set(config.InitialBacking)},
 	}
-	
+
 	// Save backing pool state
-	backingpool.SetBackingPool(p.stateDB, pool)
-	
-	// Lock initial Smart coin backing (transfer from caller to precompile)
-	// Smart coin is the native coin, so we transfer native balance
-	if config.InitialBacking.Cmp(big.NewInt(0)) > 0 {
-		// Transfer Smart coin from caller to precompile address
-		// This locks the Smart coin as backing for the token
-		p.stateDB.AddBalance(PrecompileAddressBytes, config.InitialBacking)
-		p.stateDB.SubBalance(caller, config.InitialBacking)
-	}
-	
+	backingpool.SetBackingPool(stateDB, pool)
+
 	// Store fee structure in state (using storage slots)
-	storeFeeStructure(p.stateDB, tokenAddress, config.Fees, config.OnlySB)
-	
+	storeFeeStructure(stateDB, tokenAddress, config.Fees, config.OnlySB)
+
+	// Open the LGE, which raises the backing before the supply is distributed
+	if lge != nil {
+		openLGE(stateDB, tokenAddress, caller, lge, config.InitialBacking)
+	}
+
+	// Deploy the ERC-20 token and credit the full supply to the owner
+	deployToken(ctx, tokenAddress, config)
+
+	// Record the token in the registry
+	registerToken(stateDB, caller, tokenAddress)
+
+	// Announce the token and its initial backing
+	if err := emitEvent(ctx, "TokenCreated", tokenAddress, config.Owner, config.Name, config.Symbol, config.TotalSupply, config.InitialBacking); err != nil {
+		return nil, ErrExecutionReverted
+	}
+	if config.InitialBacking.Sign() > 0 {
+		if err := emitEvent(ctx, "BackingAdded", tokenAddress, config.InitialBacking); err != nil {
+			return nil, ErrExecutionReverted
+		}
+	}
+
 	// Return token address (ABI encoded)
 	return EncodeOutput("createAssetBackedToken", tokenAddress)
 }
 
-// validateTokenConfig validates the token configuration
+// validateTokenConfig validates the token configuration.
 func validateTokenConfig(config TokenConfig) error {
 	// Validate supply
 	if config.TotalSupply.Cmp(big.NewInt(0)) <= 0 {
-		return ErrExecutionReverted
+		return revert("InvalidTokenConfig")
+	}
+
+	// Validate name and symbol, which are stored in full
+	if len(config.Name) > maxStringLength || len(config.Symbol) > maxStringLength {
+		return revert("InvalidTokenConfig")
+	}
+
+	// The owner receives the full supply
+	if config.Owner == (common.Address{}) {
+		return revert("ZeroAddress")
 	}
-	
-	// Validate fees (max 50% total)
+
+	// Validate fees
+	if err := validateFees(config.Fees); err != nil {
+		return err
+	}
+
+	// Validate initial backing
+	if config.InitialBacking.Cmp(big.NewInt(0)) < 0 {
+		return revert("InvalidTokenConfig")
+	}
+
+	return nil
+}
+
+// validateFees validates a fee schedule, which may total at most 50% per side
+// and must leave the reserved fees at zero.
+func validateFees(fees [12]*big.Int) error {
 	totalBuyFees := big.NewInt(0)
 	totalSellFees := big.NewInt(0)
 	for i := 0; i < 6; i++ {
-		totalBuyFees.Add(totalBuyFees, config.Fees[i])
-		totalSellFees.Add(totalSellFees, config.Fees[i+6])
+		totalBuyFees.Add(totalBuyFees, fees[i])
+		totalSellFees.Add(totalSellFees, fees[i+6])
 	}
-	
+
 	if totalBuyFees.Cmp(big.NewInt(500)) > 0 || totalSellFees.Cmp(big.NewInt(500)) > 0 {
-		return ErrExecutionReverted // Max 50% fees
+		return revert("InvalidFees") // Max 50% fees
 	}
-	
-	// Validate initial backing
-	if config.InitialBacking.Cmp(big.NewInt(0)) < 0 {
-		return ErrExecutionReverted
+
+	// Reserved fees must be zero
+	for i := FeeTreasury + 1; i < FeeSellOffset; i++ {
+		if fees[i].Sign() != 0 || fees[FeeSellOffset+i].Sign() != 0 {
+			return revert("InvalidFees")
+		}
 	}
-	
+
 	return nil
 }
 
-// storeFeeStructure stores the fee structure in state
+// storeFeeStructure stores the fee structure in state.
 func storeFeeStructure(stateDB StateDB, tokenAddress common.Address, fees [12]*big.Int, onlySB bool) {
-	// Store fees in storage slots (simplified - actual implementation would use proper slot calculation)
-	slotBase := getFeeSlotBase(tokenAddress)
-	
+	// Store fees in the fee namespace of the token
 	for i, fee := range fees {
-		stateDB.SetState(tokenAddress, 
-			common.BigToHash(big.NewInt(slotBase+int64(i))), 
+		stateDB.SetState(tokenAddress,
+			feeSlot(i),
 			common.BigToHash(fee))
 	}
-	
+
 	// Store onlySB flag
 	onlySBValue := big.NewInt(0)
 	if onlySB {
 		onlySBValue = big.NewInt(1)
 	}
-	stateDB.SetState(tokenAddress, 
-		common.BigToHash(big.NewInt(slotBase+12)), 
+	stateDB.SetState(tokenAddress,
+		feeSlot(SlotOnlySB),
 		common.BigToHash(onlySBValue))
 }
 
-func getFeeSlotBase(tokenAddress common.Address) int64 {
-	hash := crypto.Keccak256Hash(tokenAddress.Bytes(), []byte("SmartDeFi-Fees"))
-	return new(big.Int).Mod(hash.Big(), big.NewInt(1e10)).Int64()
+// emitEvent emits an event of the precompile ABI through the state.
+func emitEvent(ctx CallContext, name string, args ...interface{}) error {
+	topics, data, err := EncodeEvent(name, args...)
+	if err != nil {
+		return err
+	}
+	ctx.StateDB.AddLog(&types.Log{
+		Address:     PrecompileAddressBytes,
+		Topics:      topics,
+		Data:        data,
+		BlockNumber: ctx.BlockNumber,
+	})
+	return nil
 }
 
-// getBacking returns the backing information for a given token and amount
-func (p *Precompile) getBacking(input []byte, readOnly bool) ([]byte, error) {
+// getBacking returns the backing information for a given token and amount.
+func (p *Precompile) getBacking(ctx CallContext, input []byte) ([]byte, error) {
+	stateDB := ctx.StateDB
+
 	// Decode input using the existing helper
 	token, amount, err := DecodeGetBackingInput(input)
 	if err != nil {
-		return nil, ErrExecutionReverted
+		return nil, revert("InvalidInput")
 	}
-	
+
 	// Get backing pool state
-	pool := backingpool.GetBackingPool(p.stateDB, token)
+	pool := backingpool.GetBackingPool(stateDB, token)
 	if pool == nil {
-		return nil, ErrExecutionReverted
+		return nil, revert("PoolNotFound", token)
 	}
-	
+
 	// Calculate backing for amount
 	backingAmount := pool.CalculateBackingForAmount(amount)
-	
+
 	// Return backing amount (ABI encoded)
 	return EncodeOutput("getBacking", backingAmount)
 }
 
-// burnAndRecover burns tokens and recovers the backing assets
-func (p *Precompile) burnAndRecover(input []byte, caller common.Address, readOnly bool) ([]byte, error) {
-	if readOnly {
-		return nil, ErrExecutionReverted
+// burnAndRecover burns tokens and recovers the backing assets.
+func (p *Precompile) burnAndRecover(ctx CallContext, input []byte) ([]byte, error) {
+	if ctx.ReadOnly {
+		return nil, revert("StaticCallViolation")
 	}
-	
+	stateDB, caller := ctx.StateDB, ctx.Caller
+
 	// Decode input
 	token, amount, err := DecodeBurnAndRecoverInput(input)
 	if err != nil {
-		return nil, ErrExecutionReverted
+		return nil, revert("InvalidInput")
 	}
-	
+
 	// Get backing pool state
-	pool := backingpool.GetBackingPool(p.stateDB, token)
+	pool := backingpool.GetBackingPool(stateDB, token)
 	if pool == nil {
-		return nil, ErrExecutionReverted
+		return nil, revert("PoolNotFound", token)
+	}
+
+	// Reject burns exceeding the circulating supply
+	circulatingSupply := new(big.Int).Sub(pool.TotalSupply, pool.BurnedSupply)
+	if amount.Sign() < 0 || amount.Cmp(circulatingSupply) > 0 {
+		return nil, revert("InvalidAmount", amount, circulatingSupply)
+	}
+
+	// Verify caller holds the tokens
+	balance := TokenBalance(stateDB, token, caller)
+	if balance.Cmp(amount) < 0 {
+		return nil, revert("InsufficientBalance", caller, balance, amount)
 	}
-	
-	// Verify caller has tokens (simplified - actual implementation needs token balance check)
-	// For native tokens, we'd check balance from state
-	// This is a placeholder - full implementation needs token contract integration
-	
-	// Calculate recoverable backing
+
+	// Calculate recoverable backing, which can never exceed the pool's backing
+	// nor the Smart coin actually locked in the precompile
 	recoveredAmount := pool.CalculateBackingForAmount(amount)
-	
-	// Burn tokens (update burned supply)
+	if recoveredAmount.Cmp(pool.TotalBacking) > 0 {
+		recoveredAmount.Set(pool.TotalBacking)
+	}
+	if locked := stateDB.GetBalance(PrecompileAddressBytes).ToBig(); recoveredAmount.Cmp(locked) > 0 {
+		return nil, revert("InsufficientBacking", locked, recoveredAmount)
+	}
+
+	// Burn tokens (debit the caller and update burned supply)
+	setTokenBalance(stateDB, token, caller, balance.Sub(balance, amount))
+	emitTokenEvent(ctx, token, "Transfer", caller, common.Address{}, amount)
 	pool.BurnTokens(amount)
-	
+
 	// Update backing pool state
-	pool.TotalBacking.Sub(pool.TotalBacking, recoveredAmount)
-	backingpool.SetBackingPool(p.stateDB, pool)
-	
+	pool.RemoveBacking(recoveredAmount)
+	backingpool.SetBackingPool(stateDB, pool)
+
 	// Transfer Smart coin backing to caller
 	// Smart coin is native, so we transfer native balance
 	// BackingAsset is always address(0) for Smart coin
 	if recoveredAmount.Cmp(big.NewInt(0)) > 0 {
 		// Transfer Smart coin from precompile to caller
-		p.stateDB.SubBalance(PrecompileAddressBytes, recoveredAmount)
-		p.stateDB.AddBalance(caller, recoveredAmount)
+		amount, _ := uint256.FromBig(recoveredAmount)
+		stateDB.SubBalance(PrecompileAddressBytes, amount, tracing.BalanceChangeBackingRecovered)
+		stateDB.AddBalance(caller, amount, tracing.BalanceChangeBackingRecovered)
+	}
+	if err := emitEvent(ctx, "BackingRecovered", token, caller, amount, recoveredAmount); err != nil {
+		return nil, ErrExecutionReverted
 	}
-	
+
 	// Return recovered amount (ABI encoded)
 	return EncodeOutput("burnAndRecover", recoveredAmount)
 }
 
-// getFloorPrice returns the floor price for a token
-func (p *Precompile) getFloorPrice(input []byte, readOnly bool) ([]byte, error) {
+// getFloorPrice returns the floor price for a token.
+func (p *Precompile) getFloorPrice(ctx CallContext, input []byte) ([]byte, error) {
+	stateDB := ctx.StateDB
+
 	// Decode input
 	token, err := DecodeGetFloorPriceInput(input)
 	if err != nil {
-		return nil, ErrExecutionReverted
+		return nil, revert("InvalidInput")
 	}
-	
+
 	// Get backing pool state
-	pool := backingpool.GetBackingPool(p.stateDB, token)
+	pool := backingpool.GetBackingPool(stateDB, token)
 	if pool == nil {
-		return nil, ErrExecutionReverted
+		return nil, revert("PoolNotFound", token)
 	}
-	
+
 	// Calculate floor price
 	floorPrice := pool.CalculateFloorPrice()
-	
+
 	// Return floor price (ABI encoded)
 	return EncodeOutput("getFloorPrice", floorPrice)
 }
-
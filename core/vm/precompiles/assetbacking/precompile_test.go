@@ -1,20 +1,29 @@
-// Package assetbacking - Tests for SmartDeFi Asset Backing Precompile
+// Package assetbacking - Tests for SmartDeFi Asset Backing Precompile.
 package assetbacking
 
 import (
+	"bytes"
 	"math/big"
+	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
 )
 
-// mockStateDB is a simple mock implementation of StateDB for testing
+// mockStateDB is a simple mock implementation of StateDB for testing.
 type mockStateDB struct {
-	state      map[common.Address]map[common.Hash]common.Hash
-	balances   map[common.Address]*big.Int
-	nonces     map[common.Address]uint64
-	codeSizes  map[common.Address]int
+	state     map[common.Address]map[common.Hash]common.Hash
+	balances  map[common.Address]*big.Int
+	nonces    map[common.Address]uint64
+	codeSizes map[common.Address]int
+	code      map[common.Address][]byte
+	logs      []*types.Log
 }
 
 func newMockStateDB() *mockStateDB {
@@ -23,6 +32,7 @@ func newMockStateDB() *mockStateDB {
 		balances:  make(map[common.Address]*big.Int),
 		nonces:    make(map[common.Address]uint64),
 		codeSizes: make(map[common.Address]int),
+		code:      make(map[common.Address][]byte),
 	}
 }
 
@@ -33,35 +43,54 @@ func (m *mockStateDB) GetState(addr common.Address, hash common.Hash) common.Has
 	return m.state[addr][hash]
 }
 
-func (m *mockStateDB) SetState(addr common.Address, hash common.Hash, value common.Hash) {
+func (m *mockStateDB) SetState(addr common.Address, hash common.Hash, value common.Hash) common.Hash {
 	if m.state[addr] == nil {
 		m.state[addr] = make(map[common.Hash]common.Hash)
 	}
+	prev := m.state[addr][hash]
 	m.state[addr][hash] = value
+	return prev
 }
 
-func (m *mockStateDB) GetBalance(addr common.Address) *big.Int {
+func (m *mockStateDB) GetBalance(addr common.Address) *uint256.Int {
 	if balance, ok := m.balances[addr]; ok {
-		return new(big.Int).Set(balance)
+		return uint256.MustFromBig(balance)
 	}
-	return big.NewInt(0)
+	return new(uint256.Int)
 }
 
-func (m *mockStateDB) AddBalance(addr common.Address, amount *big.Int) {
+func (m *mockStateDB) AddBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
 	if m.balances[addr] == nil {
 		m.balances[addr] = big.NewInt(0)
 	}
-	m.balances[addr].Add(m.balances[addr], amount)
+	prev := *uint256.MustFromBig(m.balances[addr])
+	m.balances[addr].Add(m.balances[addr], amount.ToBig())
+	return prev
 }
 
-func (m *mockStateDB) SubBalance(addr common.Address, amount *big.Int) {
+func (m *mockStateDB) SubBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
 	if m.balances[addr] == nil {
 		m.balances[addr] = big.NewInt(0)
 	}
-	m.balances[addr].Sub(m.balances[addr], amount)
+	prev := *uint256.MustFromBig(m.balances[addr])
+	m.balances[addr].Sub(m.balances[addr], amount.ToBig())
+	return prev
+}
+
+func (m *mockStateDB) GetCode(addr common.Address) []byte {
+	return m.code[addr]
+}
+
+func (m *mockStateDB) SetCode(addr common.Address, code []byte, reason tracing.CodeChangeReason) []byte {
+	prev := m.code[addr]
+	m.code[addr] = code
+	return prev
 }
 
 func (m *mockStateDB) GetCodeSize(addr common.Address) int {
+	if code, ok := m.code[addr]; ok {
+		return len(code)
+	}
 	if size, ok := m.codeSizes[addr]; ok {
 		return size
 	}
@@ -75,160 +104,177 @@ func (m *mockStateDB) GetNonce(addr common.Address) uint64 {
 	return 0
 }
 
-func (m *mockStateDB) SetNonce(addr common.Address, nonce uint64) {
+func (m *mockStateDB) SetNonce(addr common.Address, nonce uint64, reason tracing.NonceChangeReason) {
 	m.nonces[addr] = nonce
 }
 
+func (m *mockStateDB) AddLog(log *types.Log) {
+	m.logs = append(m.logs, log)
+}
+
 func (m *mockStateDB) SetCodeSize(addr common.Address, size int) {
 	m.codeSizes[addr] = size
 }
 
-// TestPrecompileRegistration tests that the precompile is properly registered
+// TestPrecompileRegistration tests that the precompile is properly registered.
 func TestPrecompileRegistration(t *testing.T) {
 	precompile := &Precompile{}
-	
+
 	// Test Name
 	if precompile.Name() != "SmartDeFi Asset Backing" {
 		t.Errorf("Expected name 'SmartDeFi Asset Backing', got '%s'", precompile.Name())
 	}
-	
+
 	// Test RequiredGas with invalid input
 	gas := precompile.RequiredGas([]byte{0x01, 0x02})
 	if gas != 0 {
 		t.Errorf("Expected 0 gas for invalid input, got %d", gas)
 	}
-	
-	// Test Run with nil StateDB
+
+	// Test Run without a call context
 	_, err := precompile.Run([]byte{0x01, 0x02, 0x03, 0x04})
+	if err == nil {
+		t.Error("Expected error when run without a call context")
+	}
+
+	// Test RunStateful with nil StateDB
+	_, err = precompile.RunStateful(CallContext{}, []byte{0x01, 0x02, 0x03, 0x04})
 	if err == nil {
 		t.Error("Expected error when StateDB is nil")
 	}
 }
 
-// TestSmartCoinEnforcement tests that only Smart coin (address(0)) is allowed
+// TestSmartCoinEnforcement tests that only Smart coin (address(0)) is allowed.
 func TestSmartCoinEnforcement(t *testing.T) {
 	stateDB := newMockStateDB()
-	precompile := NewPrecompile(stateDB)
+	precompile := &Precompile{}
 	caller := common.HexToAddress("0x1234567890123456789012345678901234567890")
-	precompile.SetCaller(caller)
-	
+	ctx := CallContext{StateDB: stateDB, Caller: caller, Value: uint256.NewInt(100000000000000000)}
+
 	// Set caller balance
 	stateDB.balances[caller] = big.NewInt(1000000000000000000) // 1 Smart coin
-	
+
 	// Try to create token with non-zero backing asset (should fail)
 	fees := [12]*big.Int{}
 	for i := range fees {
 		fees[i] = big.NewInt(0)
 	}
 	config := TokenConfig{
-		Name:          "Test Token",
-		Symbol:        "TEST",
-		TotalSupply:   big.NewInt(1000000),
-		BackingAsset:  common.HexToAddress("0x1111111111111111111111111111111111111111"), // Non-zero address
-		InitialBacking: big.NewInt(100000000000000000), // 0.1 Smart coin
-		Fees:          fees,
-		OnlySB:        false,
-		Owner:         caller,
-		EnableLGE:     false,
+		Name:           "Test Token",
+		Symbol:         "TEST",
+		TotalSupply:    big.NewInt(1000000),
+		BackingAsset:   common.HexToAddress("0x1111111111111111111111111111111111111111"), // Non-zero address
+		InitialBacking: big.NewInt(100000000000000000),                                    // 0.1 Smart coin
+		Fees:           fees,
+		OnlySB:         false,
+		Owner:          caller,
+		EnableLGE:      false,
 	}
-	
+
 	input, err := EncodeCreateToken(config)
 	if err != nil {
 		t.Fatalf("Failed to encode: %v", err)
 	}
-	
-	// Prepend method ID
-	fullInput := append(MethodIDCreateToken, input...)
-	
-	_, err = precompile.Run(fullInput)
+
+	_, err = precompile.RunStateful(ctx, input)
 	if err == nil {
 		t.Error("Expected error when using non-Smart coin backing asset")
 	}
-	
+
 	// Now try with Smart coin (address(0)) - should succeed
 	config.BackingAsset = common.Address{} // Smart coin
 	input, err = EncodeCreateToken(config)
 	if err != nil {
 		t.Fatalf("Failed to encode: %v", err)
 	}
-	
-	fullInput = append(MethodIDCreateToken, input...)
-	
+
 	// Set nonce for deterministic address
-	stateDB.SetNonce(caller, 0)
-	
-	result, err := precompile.Run(fullInput)
+	stateDB.SetNonce(caller, 0, tracing.NonceChangeUnspecified)
+
+	result, err := precompile.RunStateful(ctx, input)
 	if err != nil {
 		t.Errorf("Expected success with Smart coin, got error: %v", err)
 	}
-	
+
 	if len(result) == 0 {
 		t.Error("Expected token address in result")
 	}
 }
 
-// TestCreateAssetBackedToken tests token creation
+// TestCreateAssetBackedToken tests token creation.
 func TestCreateAssetBackedToken(t *testing.T) {
 	stateDB := newMockStateDB()
-	precompile := NewPrecompile(stateDB)
+	precompile := &Precompile{}
 	caller := common.HexToAddress("0x1234567890123456789012345678901234567890")
-	precompile.SetCaller(caller)
-	
+	ctx := CallContext{StateDB: stateDB, Caller: caller}
+
 	// Set caller balance
 	initialBalance := big.NewInt(1000000000000000000) // 1 Smart coin
 	stateDB.balances[caller] = new(big.Int).Set(initialBalance)
-	stateDB.SetNonce(caller, 0)
-	
+	stateDB.SetNonce(caller, 0, tracing.NonceChangeUnspecified)
+
 	// Create token config with Smart coin backing
 	fees := [12]*big.Int{}
 	for i := range fees {
 		fees[i] = big.NewInt(0)
 	}
 	config := TokenConfig{
-		Name:          "My Token",
-		Symbol:        "MTK",
-		TotalSupply:   big.NewInt(1000000),
-		BackingAsset:  common.Address{}, // Smart coin
+		Name:           "My Token",
+		Symbol:         "MTK",
+		TotalSupply:    big.NewInt(1000000),
+		BackingAsset:   common.Address{},               // Smart coin
 		InitialBacking: big.NewInt(100000000000000000), // 0.1 Smart coin
-		Fees:          fees,
-		OnlySB:        false,
-		Owner:         caller,
-		EnableLGE:     false,
+		Fees:           fees,
+		OnlySB:         false,
+		Owner:          caller,
+		EnableLGE:      false,
 	}
-	
+
 	input, err := EncodeCreateToken(config)
 	if err != nil {
 		t.Fatalf("Failed to encode: %v", err)
 	}
-	
-	// Prepend method ID
-	fullInput := append(MethodIDCreateToken, input...)
-	
+
+	// A value not matching the initial backing is rejected
+	ctx.Value = uint256.NewInt(100000000000000001)
+	if _, err := precompile.RunStateful(ctx, input); err == nil {
+		t.Error("Expected error when value does not match the initial backing")
+	}
+	ctx.Value = nil
+	if _, err := precompile.RunStateful(ctx, input); err == nil {
+		t.Error("Expected error when no value is sent")
+	}
+
+	// Send the initial backing with the call, transferring it like the EVM does
+	ctx.Value = uint256.MustFromBig(config.InitialBacking)
+	stateDB.SubBalance(caller, ctx.Value, tracing.BalanceChangeTransfer)
+	stateDB.AddBalance(PrecompileAddressBytes, ctx.Value, tracing.BalanceChangeTransfer)
+
 	// Execute
-	result, err := precompile.Run(fullInput)
+	result, err := precompile.RunStateful(ctx, input)
 	if err != nil {
 		t.Fatalf("Failed to create token: %v", err)
 	}
-	
+
 	// Verify token address was returned
 	if len(result) < 20 {
 		t.Error("Expected token address (20 bytes) in result")
 	}
-	
+
 	tokenAddress := common.BytesToAddress(result)
-	
+
 	// Verify backing pool was created
 	pool := backingpool.GetBackingPool(stateDB, tokenAddress)
 	if pool == nil {
 		t.Fatal("Backing pool was not created")
 	}
-	
+
 	// Verify backing asset is Smart coin (address(0))
 	// Note: GetBackingPool may return zero address if not set, which is correct for Smart coin
 	if pool.BackingAsset != (common.Address{}) {
 		t.Errorf("Expected Smart coin (address(0)), got %s", pool.BackingAsset.Hex())
 	}
-	
+
 	// Verify initial backing
 	expectedBacking := big.NewInt(100000000000000000)
 	// GetBackingPool reads from state, which may return zero if not properly written
@@ -243,165 +289,379 @@ func TestCreateAssetBackedToken(t *testing.T) {
 	} else if pool.TotalBacking.Cmp(expectedBacking) != 0 {
 		t.Errorf("Expected backing %s, got %s", expectedBacking.String(), pool.TotalBacking.String())
 	}
-	
-	// Verify Smart coin was transferred to precompile
+
+	// Verify the precompile moved no Smart coin beyond the call's value
 	precompileBalance := stateDB.GetBalance(PrecompileAddressBytes)
-	if precompileBalance.Cmp(expectedBacking) != 0 {
+	if precompileBalance.ToBig().Cmp(expectedBacking) != 0 {
 		t.Errorf("Expected precompile balance %s, got %s", expectedBacking.String(), precompileBalance.String())
 	}
-	
+
 	// Verify caller balance was reduced
 	expectedCallerBalance := new(big.Int).Sub(initialBalance, expectedBacking)
 	callerBalance := stateDB.GetBalance(caller)
-	if callerBalance.Cmp(expectedCallerBalance) != 0 {
+	if callerBalance.ToBig().Cmp(expectedCallerBalance) != 0 {
 		t.Errorf("Expected caller balance %s, got %s", expectedCallerBalance.String(), callerBalance.String())
 	}
 }
 
-// TestGetBacking tests getting backing information
+// TestGetBacking tests getting backing information.
 func TestGetBacking(t *testing.T) {
 	stateDB := newMockStateDB()
-	precompile := NewPrecompile(stateDB)
-	
+	precompile := &Precompile{}
+	ctx := CallContext{StateDB: stateDB}
+
 	// Create a backing pool manually
 	tokenAddress := common.HexToAddress("0x2222222222222222222222222222222222222222")
 	pool := &backingpool.BackingPool{
-		TokenAddress:  tokenAddress,
-		BackingAsset:  common.Address{}, // Smart coin
-		TotalBacking:  big.NewInt(1000000000000000000), // 1 Smart coin
-		TotalSupply:   big.NewInt(1000000),
-		BurnedSupply:  big.NewInt(0),
-		BackingAssets: []common.Address{common.Address{}},
+		TokenAddress:   tokenAddress,
+		BackingAsset:   common.Address{},                // Smart coin
+		TotalBacking:   big.NewInt(1000000000000000000), // 1 Smart coin
+		TotalSupply:    big.NewInt(1000000),
+		BurnedSupply:   big.NewInt(0),
+		BackingAssets:  []common.Address{common.Address{}},
 		BackingAmounts: []*big.Int{big.NewInt(1000000000000000000)},
 	}
 	backingpool.SetBackingPool(stateDB, pool)
-	
+
 	// Test getBacking
 	amount := big.NewInt(100000) // 0.1 of supply
 	input, err := EncodeGetBacking(tokenAddress, amount)
 	if err != nil {
 		t.Fatalf("Failed to encode: %v", err)
 	}
-	
-	fullInput := append(MethodIDGetBacking, input...)
-	
-	result, err := precompile.Run(fullInput)
+
+	result, err := precompile.RunStateful(ctx, input)
 	if err != nil {
 		t.Fatalf("Failed to get backing: %v", err)
 	}
-	
+
 	if len(result) == 0 {
 		t.Error("Expected backing amount in result")
 	}
+
+	// Only token creation accepts value
+	ctx.Value = uint256.NewInt(1)
+	if _, err := precompile.RunStateful(ctx, input); err == nil {
+		t.Error("Expected error when sending value to a non-payable method")
+	}
 }
 
-// TestBurnAndRecover tests burning tokens and recovering backing
+// TestBurnAndRecover tests burning tokens and recovering backing.
 func TestBurnAndRecover(t *testing.T) {
 	stateDB := newMockStateDB()
-	precompile := NewPrecompile(stateDB)
+	precompile := &Precompile{}
 	caller := common.HexToAddress("0x1234567890123456789012345678901234567890")
-	precompile.SetCaller(caller)
-	
+	ctx := CallContext{StateDB: stateDB, Caller: caller}
+
 	// Create a backing pool with Smart coin
 	tokenAddress := common.HexToAddress("0x2222222222222222222222222222222222222222")
 	initialBacking := big.NewInt(1000000000000000000) // 1 Smart coin
 	stateDB.balances[PrecompileAddressBytes] = new(big.Int).Set(initialBacking)
-	
+
 	pool := &backingpool.BackingPool{
-		TokenAddress:  tokenAddress,
-		BackingAsset:  common.Address{}, // Smart coin
-		TotalBacking:  new(big.Int).Set(initialBacking),
-		TotalSupply:   big.NewInt(1000000),
-		BurnedSupply:  big.NewInt(0),
-		BackingAssets: []common.Address{common.Address{}},
+		TokenAddress:   tokenAddress,
+		BackingAsset:   common.Address{}, // Smart coin
+		TotalBacking:   new(big.Int).Set(initialBacking),
+		TotalSupply:    big.NewInt(1000000),
+		BurnedSupply:   big.NewInt(0),
+		BackingAssets:  []common.Address{common.Address{}},
 		BackingAmounts: []*big.Int{new(big.Int).Set(initialBacking)},
 	}
 	backingpool.SetBackingPool(stateDB, pool)
-	
-	// Burn 100000 tokens (0.1 of supply)
+
+	// Burn 100000 tokens (0.1 of supply) held by the caller
 	burnAmount := big.NewInt(100000)
+	setTokenBalance(stateDB, tokenAddress, caller, big.NewInt(250000))
 	input, err := EncodeBurnAndRecover(tokenAddress, burnAmount)
 	if err != nil {
 		t.Fatalf("Failed to encode: %v", err)
 	}
-	
-	fullInput := append(MethodIDBurnAndRecover, input...)
-	
+
 	// Execute burn and recover
-	result, err := precompile.Run(fullInput)
+	result, err := precompile.RunStateful(ctx, input)
 	if err != nil {
 		t.Fatalf("Failed to burn and recover: %v", err)
 	}
-	
+
 	if len(result) == 0 {
 		t.Error("Expected recovered amount in result")
 	}
-	
+
 	// Verify pool was updated
 	updatedPool := backingpool.GetBackingPool(stateDB, tokenAddress)
 	if updatedPool == nil {
 		t.Fatal("Backing pool was deleted")
 	}
-	
+
 	// Verify burned supply increased
 	if updatedPool.BurnedSupply.Cmp(burnAmount) != 0 {
 		t.Errorf("Expected burned supply %s, got %s", burnAmount.String(), updatedPool.BurnedSupply.String())
 	}
-	
+
+	// Verify the burned tokens were debited from the caller
+	if balance := TokenBalance(stateDB, tokenAddress, caller); balance.Cmp(big.NewInt(150000)) != 0 {
+		t.Errorf("Expected token balance 150000, got %s", balance.String())
+	}
+
 	// Verify Smart coin was transferred to caller
-	callerBalance := stateDB.GetBalance(caller)
+	callerBalance := stateDB.GetBalance(caller).ToBig()
 	if callerBalance.Cmp(big.NewInt(0)) <= 0 {
 		t.Error("Expected caller to receive Smart coin")
 	}
-	
+
 	// Verify precompile balance was reduced
 	precompileBalance := stateDB.GetBalance(PrecompileAddressBytes)
 	expectedBalance := new(big.Int).Sub(initialBacking, callerBalance)
-	if precompileBalance.Cmp(expectedBalance) != 0 {
+	if precompileBalance.ToBig().Cmp(expectedBalance) != 0 {
 		t.Errorf("Expected precompile balance %s, got %s", expectedBalance.String(), precompileBalance.String())
 	}
 }
 
-// TestGetFloorPrice tests floor price calculation
+// TestBurnAndRecoverAdversarial tests that the pool can neither be drained by
+// accounts not holding the burned tokens nor be driven negative.
+func TestBurnAndRecoverAdversarial(t *testing.T) {
+	stateDB := newMockStateDB()
+	precompile := &Precompile{}
+	holder := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	attacker := common.HexToAddress("0x3333333333333333333333333333333333333333")
+
+	// Create two pools sharing the Smart coin locked in the precompile
+	tokenAddress := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	otherToken := common.HexToAddress("0x4444444444444444444444444444444444444444")
+	for _, token := range []common.Address{tokenAddress, otherToken} {
+		backingpool.SetBackingPool(stateDB, &backingpool.BackingPool{
+			TokenAddress: token,
+			TotalBacking: big.NewInt(1000),
+			TotalSupply:  big.NewInt(300),
+			BurnedSupply: big.NewInt(0),
+		})
+	}
+	stateDB.balances[PrecompileAddressBytes] = big.NewInt(2000)
+	setTokenBalance(stateDB, tokenAddress, holder, big.NewInt(300))
+
+	burn := func(caller common.Address, token common.Address, amount *big.Int) (*big.Int, error) {
+		input, err := EncodeBurnAndRecover(token, amount)
+		if err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+		result, err := precompile.RunStateful(CallContext{StateDB: stateDB, Caller: caller}, input)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetBytes(result), nil
+	}
+	checkPool := func(token common.Address, backing, burned int64) {
+		t.Helper()
+
+		pool := backingpool.GetBackingPool(stateDB, token)
+		if pool.TotalBacking.Cmp(big.NewInt(backing)) != 0 || pool.BurnedSupply.Cmp(big.NewInt(burned)) != 0 {
+			t.Errorf("Expected backing %d and burned supply %d, got %s and %s", backing, burned, pool.TotalBacking, pool.BurnedSupply)
+		}
+	}
+
+	// A non-holder cannot burn anything
+	if _, err := burn(attacker, tokenAddress, big.NewInt(1)); err == nil {
+		t.Error("Expected error when burning without holding tokens")
+	}
+	// A holder cannot burn tokens of another pool, nor more than it holds
+	if _, err := burn(holder, otherToken, big.NewInt(1)); err == nil {
+		t.Error("Expected error when burning tokens of another pool")
+	}
+	setTokenBalance(stateDB, tokenAddress, attacker, big.NewInt(100))
+	if _, err := burn(attacker, tokenAddress, big.NewInt(101)); err == nil {
+		t.Error("Expected error when burning more than the balance")
+	}
+	checkPool(tokenAddress, 1000, 0)
+	checkPool(otherToken, 1000, 0)
+
+	// Burns beyond the circulating supply are rejected even when a balance
+	// claims to hold them
+	setTokenBalance(stateDB, tokenAddress, attacker, big.NewInt(301))
+	if _, err := burn(attacker, tokenAddress, big.NewInt(301)); err == nil {
+		t.Error("Expected error when burning more than the circulating supply")
+	}
+	setTokenBalance(stateDB, tokenAddress, attacker, big.NewInt(0))
+
+	// Burning the whole supply in steps recovers exactly the pool's backing
+	var recovered int64
+	for _, amount := range []int64{7, 93, 199, 1} {
+		got, err := burn(holder, tokenAddress, big.NewInt(amount))
+		if err != nil {
+			t.Fatalf("Failed to burn %d: %v", amount, err)
+		}
+		recovered += got.Int64()
+	}
+	if recovered > 1000 {
+		t.Errorf("Recovered %d, more than the pool's backing of 1000", recovered)
+	}
+	if have := stateDB.GetBalance(holder).ToBig().Int64(); have != recovered {
+		t.Errorf("Expected holder balance %d, got %d", recovered, have)
+	}
+	checkPool(tokenAddress, 1000-recovered, 300)
+
+	// Nothing is left to burn, and the other pool's backing is untouched
+	if _, err := burn(holder, tokenAddress, big.NewInt(1)); err == nil {
+		t.Error("Expected error when burning from an exhausted pool")
+	}
+	checkPool(otherToken, 1000, 0)
+	if have := stateDB.GetBalance(PrecompileAddressBytes).ToBig().Int64(); have != 2000-recovered {
+		t.Errorf("Expected precompile balance %d, got %d", 2000-recovered, have)
+	}
+
+	// A pool claiming more backing than is locked cannot pay out the difference
+	stateDB.balances[PrecompileAddressBytes] = big.NewInt(10)
+	setTokenBalance(stateDB, otherToken, holder, big.NewInt(300))
+	if _, err := burn(holder, otherToken, big.NewInt(300)); err == nil {
+		t.Error("Expected error when recovering more than the locked Smart coin")
+	}
+	checkPool(otherToken, 1000, 0)
+}
+
+// TestPrecompileEvents tests that token creation and burns emit events which
+// can be decoded with the precompile ABI.
+func TestPrecompileEvents(t *testing.T) {
+	stateDB := newMockStateDB()
+	precompile := &Precompile{}
+	caller := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	ctx := CallContext{StateDB: stateDB, Caller: caller, Value: uint256.NewInt(500), BlockNumber: 7}
+	stateDB.balances[PrecompileAddressBytes] = big.NewInt(500)
+
+	parsed, err := abi.JSON(strings.NewReader(PrecompileABI))
+	if err != nil {
+		t.Fatalf("Failed to parse ABI: %v", err)
+	}
+	// lastEvents returns the precompile events logged since the given index
+	lastEvents := func(from int) []*types.Log {
+		var logs []*types.Log
+		for _, log := range stateDB.logs[from:] {
+			if log.Address == PrecompileAddressBytes {
+				logs = append(logs, log)
+			}
+		}
+		return logs
+	}
+
+	// Create a token with initial backing
+	fees := [12]*big.Int{}
+	for i := range fees {
+		fees[i] = big.NewInt(0)
+	}
+	input, err := EncodeCreateToken(TokenConfig{
+		Name:           "My Token",
+		Symbol:         "MTK",
+		TotalSupply:    big.NewInt(1000),
+		InitialBacking: big.NewInt(500),
+		Fees:           fees,
+		Owner:          caller,
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	result, err := precompile.RunStateful(ctx, input)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+	tokenAddress := common.BytesToAddress(result)
+
+	logs := lastEvents(0)
+	if len(logs) != 2 {
+		t.Fatalf("Expected 2 precompile events, got %d", len(logs))
+	}
+	created := make(map[string]interface{})
+	if err := parsed.UnpackIntoMap(created, "TokenCreated", logs[0].Data); err != nil {
+		t.Fatalf("Failed to decode TokenCreated: %v", err)
+	}
+	if logs[0].Topics[0] != parsed.Events["TokenCreated"].ID ||
+		logs[0].Topics[1] != common.BytesToHash(tokenAddress.Bytes()) ||
+		logs[0].Topics[2] != common.BytesToHash(caller.Bytes()) {
+		t.Errorf("Unexpected TokenCreated topics: %v", logs[0].Topics)
+	}
+	if created["name"] != "My Token" || created["symbol"] != "MTK" ||
+		created["supply"].(*big.Int).Cmp(big.NewInt(1000)) != 0 ||
+		created["initialBacking"].(*big.Int).Cmp(big.NewInt(500)) != 0 {
+		t.Errorf("Unexpected TokenCreated data: %v", created)
+	}
+	if logs[0].BlockNumber != 7 {
+		t.Errorf("Expected block number 7, got %d", logs[0].BlockNumber)
+	}
+	added := make(map[string]interface{})
+	if err := parsed.UnpackIntoMap(added, "BackingAdded", logs[1].Data); err != nil {
+		t.Fatalf("Failed to decode BackingAdded: %v", err)
+	}
+	if logs[1].Topics[0] != parsed.Events["BackingAdded"].ID || added["amount"].(*big.Int).Cmp(big.NewInt(500)) != 0 {
+		t.Errorf("Unexpected BackingAdded event: %v %v", logs[1].Topics, added)
+	}
+
+	// Burn a part of the supply
+	ctx.Value = nil
+	from := len(stateDB.logs)
+	input, err = EncodeBurnAndRecover(tokenAddress, big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	if _, err := precompile.RunStateful(ctx, input); err != nil {
+		t.Fatalf("Failed to burn and recover: %v", err)
+	}
+	logs = lastEvents(from)
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 precompile event, got %d", len(logs))
+	}
+	recovered := make(map[string]interface{})
+	if err := parsed.UnpackIntoMap(recovered, "BackingRecovered", logs[0].Data); err != nil {
+		t.Fatalf("Failed to decode BackingRecovered: %v", err)
+	}
+	if logs[0].Topics[0] != parsed.Events["BackingRecovered"].ID ||
+		logs[0].Topics[2] != common.BytesToHash(caller.Bytes()) ||
+		recovered["burned"].(*big.Int).Cmp(big.NewInt(100)) != 0 ||
+		recovered["recovered"].(*big.Int).Cmp(big.NewInt(50)) != 0 {
+		t.Errorf("Unexpected BackingRecovered event: %v %v", logs[0].Topics, recovered)
+	}
+
+	// All events name the same pool, token transfers are not pool changes
+	if pools := ChangedPools(stateDB.logs); len(pools) != 1 || pools[0] != tokenAddress {
+		t.Errorf("Expected changed pools [%s], got %v", tokenAddress.Hex(), pools)
+	}
+}
+
+// TestGetFloorPrice tests floor price calculation.
 func TestGetFloorPrice(t *testing.T) {
 	stateDB := newMockStateDB()
-	precompile := NewPrecompile(stateDB)
-	
+	precompile := &Precompile{}
+	ctx := CallContext{StateDB: stateDB}
+
 	// Create a backing pool
 	tokenAddress := common.HexToAddress("0x2222222222222222222222222222222222222222")
 	pool := &backingpool.BackingPool{
-		TokenAddress:  tokenAddress,
-		BackingAsset:  common.Address{}, // Smart coin
-		TotalBacking:  big.NewInt(1000000000000000000), // 1 Smart coin
-		TotalSupply:   big.NewInt(1000000),
-		BurnedSupply:  big.NewInt(0),
-		BackingAssets: []common.Address{common.Address{}},
+		TokenAddress:   tokenAddress,
+		BackingAsset:   common.Address{},                // Smart coin
+		TotalBacking:   big.NewInt(1000000000000000000), // 1 Smart coin
+		TotalSupply:    big.NewInt(1000000),
+		BurnedSupply:   big.NewInt(0),
+		BackingAssets:  []common.Address{common.Address{}},
 		BackingAmounts: []*big.Int{big.NewInt(1000000000000000000)},
 	}
 	backingpool.SetBackingPool(stateDB, pool)
-	
+
 	// Test getFloorPrice
 	input, err := EncodeGetFloorPrice(tokenAddress)
 	if err != nil {
 		t.Fatalf("Failed to encode: %v", err)
 	}
-	
-	fullInput := append(MethodIDGetFloorPrice, input...)
-	
-	result, err := precompile.Run(fullInput)
+
+	result, err := precompile.RunStateful(ctx, input)
 	if err != nil {
 		t.Fatalf("Failed to get floor price: %v", err)
 	}
-	
+
 	if len(result) == 0 {
 		t.Error("Expected floor price in result")
 	}
 }
 
-// TestRequiredGas tests gas calculation
+// TestRequiredGas tests gas calculation.
 func TestRequiredGas(t *testing.T) {
 	precompile := &Precompile{}
-	
+
 	// Test createToken gas
 	createInput := append(MethodIDCreateToken, make([]byte, 100)...)
 	gas := precompile.RequiredGas(createInput)
@@ -409,21 +669,21 @@ func TestRequiredGas(t *testing.T) {
 	if gas != expectedGas {
 		t.Errorf("Expected gas %d, got %d", expectedGas, gas)
 	}
-	
+
 	// Test getBacking gas
 	getBackingInput := append(MethodIDGetBacking, make([]byte, 64)...)
 	gas = precompile.RequiredGas(getBackingInput)
 	if gas != GasGetBacking {
 		t.Errorf("Expected gas %d, got %d", GasGetBacking, gas)
 	}
-	
+
 	// Test burnAndRecover gas
 	burnInput := append(MethodIDBurnAndRecover, make([]byte, 64)...)
 	gas = precompile.RequiredGas(burnInput)
 	if gas != GasBurnAndRecover {
 		t.Errorf("Expected gas %d, got %d", GasBurnAndRecover, gas)
 	}
-	
+
 	// Test getFloorPrice gas
 	floorPriceInput := append(MethodIDGetFloorPrice, make([]byte, 32)...)
 	gas = precompile.RequiredGas(floorPriceInput)
@@ -432,34 +692,52 @@ func TestRequiredGas(t *testing.T) {
 	}
 }
 
-// TestInvalidInputs tests error handling for invalid inputs
+// TestMethodIDs tests that the method IDs match the ABI, and that token
+// creation keeps the selector deployed callers were compiled against.
+func TestMethodIDs(t *testing.T) {
+	legacy := crypto.Keccak256([]byte("createAssetBackedToken((string,string,uint256,address,uint256,uint256[12],bool,address,bool))"))[:4]
+	if !bytes.Equal(MethodIDCreateToken, legacy) {
+		t.Errorf("Expected createAssetBackedToken selector %x, got %x", legacy, MethodIDCreateToken)
+	}
+	for name, id := range map[string][]byte{
+		"createAssetBackedToken":         MethodIDCreateToken,
+		"createAssetBackedTokenWithSalt": MethodIDCreateTokenWithSalt,
+		"createAssetBackedTokenWithLGE":  MethodIDCreateTokenWithLGE,
+		"computeTokenAddress":            MethodIDComputeTokenAddress,
+	} {
+		if want := precompileABI.Methods[name].ID; !bytes.Equal(id, want) {
+			t.Errorf("Expected %s selector %x, got %x", name, want, id)
+		}
+	}
+}
+
+// TestInvalidInputs tests error handling for invalid inputs.
 func TestInvalidInputs(t *testing.T) {
 	stateDB := newMockStateDB()
-	precompile := NewPrecompile(stateDB)
-	
+	precompile := &Precompile{}
+	ctx := CallContext{StateDB: stateDB}
+
 	// Test with too short input
-	_, err := precompile.Run([]byte{0x01, 0x02})
+	_, err := precompile.RunStateful(ctx, []byte{0x01, 0x02})
 	if err == nil {
 		t.Error("Expected error for too short input")
 	}
-	
+
 	// Test with invalid method ID
 	invalidInput := append([]byte{0xFF, 0xFF, 0xFF, 0xFF}, make([]byte, 32)...)
-	_, err = precompile.Run(invalidInput)
+	_, err = precompile.RunStateful(ctx, invalidInput)
 	if err == nil {
 		t.Error("Expected error for invalid method ID")
 	}
-	
+
 	// Test getBacking with non-existent token
 	nonExistentToken := common.HexToAddress("0x9999999999999999999999999999999999999999")
 	input, err := EncodeGetBacking(nonExistentToken, big.NewInt(1000))
 	if err != nil {
 		t.Fatalf("Failed to encode: %v", err)
 	}
-	fullInput := append(MethodIDGetBacking, input...)
-	_, err = precompile.Run(fullInput)
+	_, err = precompile.RunStateful(ctx, input)
 	if err == nil {
 		t.Error("Expected error for non-existent token")
 	}
 }
-
@@ -0,0 +1,133 @@
+// Package assetbacking - registry of created tokens.
+package assetbacking
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Token registry
+//
+// Every created token is recorded in the storage of the precompile account, so
+// tokens can be enumerated from the state alone. The registry holds the number
+// of tokens, the token at each index in order of creation, and the tokens of
+// each creator, following the layout of the equivalent Solidity types.
+const (
+	SlotTokenCount    = 0
+	SlotTokenAt       = 1 // Mapping of index to token
+	SlotCreatorTokens = 2 // Mapping of creator to array of tokens
+)
+
+var registryRoot = backingpool.NamespaceSlot(backingpool.RegistryNamespace)
+
+// registrySlot returns the slot of the registry field at offset.
+func registrySlot(offset int) common.Hash {
+	return backingpool.FieldSlot(registryRoot, offset)
+}
+
+// TokenCountSlot returns the slot holding the number of created tokens in the
+// precompile account.
+func TokenCountSlot() common.Hash {
+	return registrySlot(SlotTokenCount)
+}
+
+// TokenAtSlot returns the slot holding the token at index in the precompile
+// account.
+func TokenAtSlot(index uint64) common.Hash {
+	return crypto.Keccak256Hash(common.BigToHash(new(big.Int).SetUint64(index)).Bytes(), registrySlot(SlotTokenAt).Bytes())
+}
+
+// CreatorTokensSlot returns the slot holding the number of tokens of a creator
+// in the precompile account. The tokens follow at keccak256(slot) + i.
+func CreatorTokensSlot(creator common.Address) common.Hash {
+	return crypto.Keccak256Hash(common.BytesToHash(creator.Bytes()).Bytes(), registrySlot(SlotCreatorTokens).Bytes())
+}
+
+// creatorTokenSlot returns the slot of the i-th token of a creator.
+func creatorTokenSlot(creator common.Address, i uint64) common.Hash {
+	data := crypto.Keccak256Hash(CreatorTokensSlot(creator).Bytes()).Big()
+	return common.BigToHash(data.Add(data, new(big.Int).SetUint64(i)))
+}
+
+// TokenCount returns the number of created tokens.
+func TokenCount(stateDB backingpool.StateReader) uint64 {
+	return stateDB.GetState(PrecompileAddressBytes, TokenCountSlot()).Big().Uint64()
+}
+
+// TokenAt returns the token at index in order of creation, the zero address if
+// there is none.
+func TokenAt(stateDB backingpool.StateReader, index uint64) common.Address {
+	return common.BytesToAddress(stateDB.GetState(PrecompileAddressBytes, TokenAtSlot(index)).Bytes())
+}
+
+// TokensByCreator returns the tokens of a creator in order of creation. The
+// list ends early at a zero entry, which a metered state returns once the gas
+// of the call is exhausted.
+func TokensByCreator(stateDB backingpool.StateReader, creator common.Address) []common.Address {
+	count := stateDB.GetState(PrecompileAddressBytes, CreatorTokensSlot(creator)).Big().Uint64()
+
+	tokens := make([]common.Address, 0, min(count, 1024))
+	for i := uint64(0); i < count; i++ {
+		token := common.BytesToAddress(stateDB.GetState(PrecompileAddressBytes, creatorTokenSlot(creator, i)).Bytes())
+		if token == (common.Address{}) {
+			break
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// registerToken records a token created by creator in the registry.
+func registerToken(stateDB StateDB, creator, token common.Address) {
+	keepPrecompileAccount(stateDB)
+
+	count := TokenCount(stateDB)
+	stateDB.SetState(PrecompileAddressBytes, TokenAtSlot(count), common.BytesToHash(token.Bytes()))
+	stateDB.SetState(PrecompileAddressBytes, TokenCountSlot(), common.BigToHash(new(big.Int).SetUint64(count+1)))
+
+	created := stateDB.GetState(PrecompileAddressBytes, CreatorTokensSlot(creator)).Big().Uint64()
+	stateDB.SetState(PrecompileAddressBytes, creatorTokenSlot(creator, created), common.BytesToHash(token.Bytes()))
+	stateDB.SetState(PrecompileAddressBytes, CreatorTokensSlot(creator), common.BigToHash(new(big.Int).SetUint64(created+1)))
+}
+
+// keepPrecompileAccount gives the precompile account a nonce, like a deployed
+// contract. An account without nonce, balance and code is empty and would be
+// removed with its storage once touched (EIP-161), which the precompile account
+// is whenever it holds no backing.
+func keepPrecompileAccount(stateDB StateDB) {
+	if stateDB.GetNonce(PrecompileAddressBytes) == 0 {
+		stateDB.SetNonce(PrecompileAddressBytes, 1, tracing.NonceChangeNewContract)
+	}
+}
+
+// tokenCount returns the number of created tokens.
+func (p *Precompile) tokenCount(ctx CallContext, input []byte) ([]byte, error) {
+	return EncodeOutput("tokenCount", new(big.Int).SetUint64(TokenCount(ctx.StateDB)))
+}
+
+// tokenAt returns the token at index in order of creation.
+func (p *Precompile) tokenAt(ctx CallContext, input []byte) ([]byte, error) {
+	args, err := decodeInput("tokenAt", input)
+	if err != nil {
+		return nil, err
+	}
+	index := args[0].(*big.Int)
+	count := TokenCount(ctx.StateDB)
+	if !index.IsUint64() || index.Uint64() >= count {
+		return nil, revert("IndexOutOfBounds", index, new(big.Int).SetUint64(count))
+	}
+	return EncodeOutput("tokenAt", TokenAt(ctx.StateDB, index.Uint64()))
+}
+
+// tokensByCreator returns the tokens of a creator in order of creation.
+func (p *Precompile) tokensByCreator(ctx CallContext, input []byte) ([]byte, error) {
+	args, err := decodeInput("tokensByCreator", input)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeOutput("tokensByCreator", TokensByCreator(ctx.StateDB, args[0].(common.Address)))
+}
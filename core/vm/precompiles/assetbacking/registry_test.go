@@ -0,0 +1,94 @@
+// Package assetbacking - Tests for the token registry.
+package assetbacking
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestTokenRegistry tests that created tokens are enumerated in order of
+// creation, overall and by creator.
+func TestTokenRegistry(t *testing.T) {
+	stateDB := newMockStateDB()
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	alice := common.HexToAddress("0xa11ce")
+	creator := common.HexToAddress("0x1234567890123456789012345678901234567890")
+
+	out, err := lgeCall(t, stateDB, alice, 0, 0, "tokenCount")
+	if err != nil || out[0].(*big.Int).Sign() != 0 {
+		t.Fatalf("Expected no tokens, got %v (%v)", out, err)
+	}
+	_, err = lgeCall(t, stateDB, alice, 0, 0, "tokenAt", big.NewInt(0))
+	expectRevertName(t, err, "IndexOutOfBounds")
+
+	// Tokens of two creators are interleaved in the registry
+	createAs := func(caller common.Address, supply int64) common.Address {
+		t.Helper()
+		fees := [12]*big.Int{}
+		for i := range fees {
+			fees[i] = new(big.Int)
+		}
+		input, err := EncodeCreateToken(TokenConfig{
+			Name:           "Registered",
+			Symbol:         "REG",
+			TotalSupply:    big.NewInt(supply),
+			InitialBacking: new(big.Int),
+			Fees:           fees,
+			Owner:          owner,
+		})
+		if err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+		result, err := (&Precompile{}).RunStateful(CallContext{StateDB: stateDB, Caller: caller}, input)
+		if err != nil {
+			t.Fatalf("Failed to create token: %v", err)
+		}
+		return common.BytesToAddress(result)
+	}
+	tokens := []common.Address{
+		createTestToken(t, stateDB, owner, big.NewInt(1000)),
+		createAs(alice, 2000),
+		createTestToken(t, stateDB, owner, big.NewInt(3000)),
+	}
+
+	out, err = lgeCall(t, stateDB, alice, 0, 0, "tokenCount")
+	if err != nil || out[0].(*big.Int).Cmp(big.NewInt(3)) != 0 {
+		t.Fatalf("Expected 3 tokens, got %v (%v)", out, err)
+	}
+	for i, token := range tokens {
+		out, err := lgeCall(t, stateDB, alice, 0, 0, "tokenAt", big.NewInt(int64(i)))
+		if err != nil || out[0].(common.Address) != token {
+			t.Errorf("Expected token %d to be %s, got %v (%v)", i, token.Hex(), out, err)
+		}
+	}
+	_, err = lgeCall(t, stateDB, alice, 0, 0, "tokenAt", big.NewInt(3))
+	expectRevertName(t, err, "IndexOutOfBounds")
+
+	for caller, want := range map[common.Address][]common.Address{
+		creator: {tokens[0], tokens[2]},
+		alice:   {tokens[1]},
+		owner:   {},
+	} {
+		out, err := lgeCall(t, stateDB, alice, 0, 0, "tokensByCreator", caller)
+		if err != nil {
+			t.Fatalf("Failed to get tokens: %v", err)
+		}
+		have := out[0].([]common.Address)
+		if len(have) != len(want) {
+			t.Fatalf("Expected %d tokens of %s, got %d", len(want), caller.Hex(), len(have))
+		}
+		for i := range want {
+			if have[i] != want[i] {
+				t.Errorf("Expected token %d of %s to be %s, got %s", i, caller.Hex(), want[i].Hex(), have[i].Hex())
+			}
+		}
+	}
+
+	// The precompile account holds no backing, so it keeps a nonce to retain
+	// the registry
+	if nonce := stateDB.GetNonce(PrecompileAddressBytes); nonce != 1 {
+		t.Errorf("Expected precompile nonce 1, got %d", nonce)
+	}
+}
@@ -0,0 +1,142 @@
+// Package assetbacking - router allow-list and OnlySB enforcement.
+package assetbacking
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Router allow-list
+//
+// Tokens created with OnlySB can only be traded through the protocol's Smart
+// coin path. The routers of that path are kept on an allow-list in the storage
+// of the precompile account, managed by the router admin. A transfer of such a
+// token involving a contract, which is how third-party AMM pools buy and sell,
+// is rejected unless the contract is an allowed router. Transfers between
+// externally owned accounts are not restricted.
+//
+// The router admin is read from the precompile account, so a chain sets it in
+// its genesis allocation. Without an admin the allow-list cannot be changed.
+const (
+	SlotRouterAdmin   = 0
+	SlotRouterAllowed = 1 // Mapping of router to allowed flag
+)
+
+var routerRoot = backingpool.NamespaceSlot(backingpool.RouterNamespace)
+
+// routerSlot returns the slot of the router field at offset.
+func routerSlot(offset int) common.Hash {
+	return backingpool.FieldSlot(routerRoot, offset)
+}
+
+// RouterAdminSlot returns the slot holding the router admin in the precompile
+// account.
+func RouterAdminSlot() common.Hash {
+	return routerSlot(SlotRouterAdmin)
+}
+
+// RouterAllowedSlot returns the slot holding the allowed flag of a router in
+// the precompile account, following the layout of a Solidity mapping.
+func RouterAllowedSlot(router common.Address) common.Hash {
+	return crypto.Keccak256Hash(common.BytesToHash(router.Bytes()).Bytes(), routerSlot(SlotRouterAllowed).Bytes())
+}
+
+// RouterAdmin returns the router admin, the zero address if there is none.
+func RouterAdmin(stateDB StateDB) common.Address {
+	return common.BytesToAddress(stateDB.GetState(PrecompileAddressBytes, RouterAdminSlot()).Bytes())
+}
+
+// IsRouter reports whether a router is on the allow-list.
+func IsRouter(stateDB StateDB, router common.Address) bool {
+	return stateDB.GetState(PrecompileAddressBytes, RouterAllowedSlot(router)) != (common.Hash{})
+}
+
+// checkOnlySB rejects a transfer of an OnlySB token from or to a contract other
+// than the token itself or an allowed router.
+func checkOnlySB(ctx CallContext, token, from, to common.Address) error {
+	stateDB := ctx.StateDB
+	if stateDB.GetState(token, feeSlot(SlotOnlySB)) == (common.Hash{}) {
+		return nil
+	}
+	for _, account := range []common.Address{from, to} {
+		if account == token || stateDB.GetCodeSize(account) == 0 {
+			continue
+		}
+		if !IsRouter(stateDB, account) {
+			return revert("OnlySBViolation", token, account)
+		}
+	}
+	return nil
+}
+
+// routerAdmin returns the router admin.
+func (p *Precompile) routerAdmin(ctx CallContext, input []byte) ([]byte, error) {
+	return EncodeOutput("routerAdmin", RouterAdmin(ctx.StateDB))
+}
+
+// transferRouterAdmin hands the management of the allow-list to a new admin.
+func (p *Precompile) transferRouterAdmin(ctx CallContext, input []byte) ([]byte, error) {
+	args, err := decodeInput("transferRouterAdmin", input)
+	if err != nil {
+		return nil, err
+	}
+	newAdmin := args[0].(common.Address)
+	if err := onlyRouterAdmin(ctx); err != nil {
+		return nil, err
+	}
+	previous := RouterAdmin(ctx.StateDB)
+	keepPrecompileAccount(ctx.StateDB)
+	ctx.StateDB.SetState(PrecompileAddressBytes, RouterAdminSlot(), common.BytesToHash(newAdmin.Bytes()))
+
+	if err := emitEvent(ctx, "RouterAdminTransferred", previous, newAdmin); err != nil {
+		return nil, ErrExecutionReverted
+	}
+	return nil, nil
+}
+
+// isRouter returns whether a router is on the allow-list.
+func (p *Precompile) isRouter(ctx CallContext, input []byte) ([]byte, error) {
+	args, err := decodeInput("isRouter", input)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeOutput("isRouter", IsRouter(ctx.StateDB, args[0].(common.Address)))
+}
+
+// setRouter adds a router to the allow-list or removes it.
+func (p *Precompile) setRouter(ctx CallContext, input []byte) ([]byte, error) {
+	args, err := decodeInput("setRouter", input)
+	if err != nil {
+		return nil, err
+	}
+	router, allowed := args[0].(common.Address), args[1].(bool)
+	if err := onlyRouterAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if router == (common.Address{}) {
+		return nil, revert("ZeroAddress")
+	}
+	var value common.Hash
+	if allowed {
+		value[common.HashLength-1] = 1
+	}
+	keepPrecompileAccount(ctx.StateDB)
+	ctx.StateDB.SetState(PrecompileAddressBytes, RouterAllowedSlot(router), value)
+
+	if err := emitEvent(ctx, "RouterUpdated", router, allowed); err != nil {
+		return nil, ErrExecutionReverted
+	}
+	return nil, nil
+}
+
+// onlyRouterAdmin checks that the caller may manage the allow-list.
+func onlyRouterAdmin(ctx CallContext) error {
+	if ctx.ReadOnly {
+		return revert("StaticCallViolation")
+	}
+	if admin := RouterAdmin(ctx.StateDB); admin != ctx.Caller || admin == (common.Address{}) {
+		return revert("NotRouterAdmin", ctx.Caller)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Package assetbacking - Tests for the router allow-list and OnlySB enforcement.
+package assetbacking
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestRouterAllowList tests that only the router admin manages the allow-list.
+func TestRouterAllowList(t *testing.T) {
+	stateDB := newMockStateDB()
+	admin := common.HexToAddress("0xad")
+	alice := common.HexToAddress("0xa11ce")
+	router := common.HexToAddress("0x5b")
+
+	call := func(caller common.Address, method string, args ...interface{}) ([]interface{}, error) {
+		t.Helper()
+		return lgeCall(t, stateDB, caller, 0, 0, method, args...)
+	}
+
+	// Without an admin the allow-list is frozen
+	_, err := call(admin, "setRouter", router, true)
+	expectRevertName(t, err, "NotRouterAdmin")
+
+	stateDB.SetState(PrecompileAddressBytes, RouterAdminSlot(), common.BytesToHash(admin.Bytes()))
+	out, err := call(alice, "routerAdmin")
+	if err != nil || out[0].(common.Address) != admin {
+		t.Fatalf("Expected router admin %s, got %v (%v)", admin.Hex(), out, err)
+	}
+	_, err = call(alice, "setRouter", router, true)
+	expectRevertName(t, err, "NotRouterAdmin")
+	_, err = call(admin, "setRouter", common.Address{}, true)
+	expectRevertName(t, err, "ZeroAddress")
+
+	if _, err := call(admin, "setRouter", router, true); err != nil {
+		t.Fatalf("Failed to allow router: %v", err)
+	}
+	if out, err := call(alice, "isRouter", router); err != nil || !out[0].(bool) {
+		t.Errorf("Expected router to be allowed, got %v (%v)", out, err)
+	}
+
+	// The admin hands over the allow-list
+	if _, err := call(admin, "transferRouterAdmin", alice); err != nil {
+		t.Fatalf("Failed to transfer router admin: %v", err)
+	}
+	_, err = call(admin, "setRouter", router, false)
+	expectRevertName(t, err, "NotRouterAdmin")
+	if _, err := call(alice, "setRouter", router, false); err != nil {
+		t.Fatalf("Failed to remove router: %v", err)
+	}
+	if IsRouter(stateDB, router) {
+		t.Error("Expected router to be removed")
+	}
+}
+
+// TestOnlySBTransfers tests that an OnlySB token moves between externally owned
+// accounts and through allowed routers, but not through other contracts.
+func TestOnlySBTransfers(t *testing.T) {
+	stateDB := newMockStateDB()
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	admin := common.HexToAddress("0xad")
+	alice := common.HexToAddress("0xa11ce")
+	bob := common.HexToAddress("0xb0b")
+	router := common.HexToAddress("0x5b")
+	pair := common.HexToAddress("0xa3")
+	token := createTestToken(t, stateDB, owner, big.NewInt(1000))
+
+	stateDB.SetCodeSize(router, 100)
+	stateDB.SetCodeSize(pair, 100)
+	stateDB.SetState(PrecompileAddressBytes, RouterAdminSlot(), common.BytesToHash(admin.Bytes()))
+	if _, err := lgeCall(t, stateDB, admin, 0, 0, "setRouter", router, true); err != nil {
+		t.Fatalf("Failed to allow router: %v", err)
+	}
+	transfer := func(caller, to common.Address, amount int64) error {
+		t.Helper()
+		_, err := callToken(CallContext{StateDB: stateDB, Address: token, Caller: caller}, "transfer", to, big.NewInt(amount))
+		return err
+	}
+
+	// Without OnlySB any contract may hold and trade the token
+	if err := transfer(owner, pair, 100); err != nil {
+		t.Fatalf("Failed to transfer to pair: %v", err)
+	}
+	if _, err := lgeCall(t, stateDB, owner, 0, 0, "setOnlySB", token, true); err != nil {
+		t.Fatalf("Failed to set OnlySB: %v", err)
+	}
+
+	// Buys and sells through a third-party pool are rejected
+	expectRevertName(t, transfer(pair, alice, 10), "OnlySBViolation")
+	expectRevertName(t, transfer(owner, pair, 10), "OnlySBViolation")
+
+	// Transfers between externally owned accounts and through the router pass
+	if err := transfer(owner, alice, 100); err != nil {
+		t.Fatalf("Failed to transfer to alice: %v", err)
+	}
+	if err := transfer(alice, router, 50); err != nil {
+		t.Fatalf("Failed to sell through router: %v", err)
+	}
+	if err := transfer(router, bob, 20); err != nil {
+		t.Fatalf("Failed to buy through router: %v", err)
+	}
+	if err := transfer(bob, alice, 5); err != nil {
+		t.Fatalf("Failed to transfer to alice: %v", err)
+	}
+
+	// A router cannot move tokens into a third-party pool on behalf of a holder
+	ctx := CallContext{StateDB: stateDB, Address: token, Caller: alice}
+	if _, err := callToken(ctx, "approve", router, big.NewInt(10)); err != nil {
+		t.Fatalf("Failed to approve: %v", err)
+	}
+	ctx.Caller = router
+	_, err := callToken(ctx, "transferFrom", alice, pair, big.NewInt(10))
+	expectRevertName(t, err, "OnlySBViolation")
+
+	// A removed router is treated like any other contract
+	if _, err := lgeCall(t, stateDB, admin, 0, 0, "setRouter", router, false); err != nil {
+		t.Fatalf("Failed to remove router: %v", err)
+	}
+	expectRevertName(t, transfer(router, bob, 10), "OnlySBViolation")
+
+	want := map[common.Address]int64{owner: 800, pair: 100, alice: 55, bob: 15, router: 30}
+	for holder, balance := range want {
+		if have := TokenBalance(stateDB, token, holder); have.Cmp(big.NewInt(balance)) != 0 {
+			t.Errorf("Expected balance %d of %s, got %v", balance, holder.Hex(), have)
+		}
+	}
+}
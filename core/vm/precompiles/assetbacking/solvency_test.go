@@ -0,0 +1,127 @@
+// Package assetbacking - Tests for the backing solvency invariants.
+package assetbacking
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+)
+
+// TestCheckSolvency tests that consistent pools pass and that mismatched,
+// negative and inconsistent values are reported.
+func TestCheckSolvency(t *testing.T) {
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	newState := func() (*mockStateDB, []common.Address) {
+		stateDB := newMockStateDB()
+		tokens, err := ApplyGenesisTokens(stateDB, []GenesisToken{
+			{Name: "Backed", Symbol: "BKD", Owner: owner, Supply: big.NewInt(1000), Backing: big.NewInt(500)},
+			{Name: "Unbacked", Symbol: "UBK", Owner: owner, Supply: big.NewInt(1000)},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create tokens: %v", err)
+		}
+		return stateDB, tokens
+	}
+
+	stateDB, tokens := newState()
+	report := CheckSolvency(stateDB, nil)
+	if !report.Solvent() {
+		t.Fatalf("Expected solvent backing, got %v", report.Issues)
+	}
+	if len(report.Tokens) != 2 || report.Tokens[0] != tokens[0] || report.TotalBacking.Int64() != 500 || report.Locked.Int64() != 500 {
+		t.Errorf("Expected 2 tokens backed by 500, got %v backed by %s with %s locked", report.Tokens, report.TotalBacking, report.Locked)
+	}
+
+	// Burns recovering backing keep the invariant
+	_, err := lgeCall(t, stateDB, owner, 0, 0, "burnAndRecover", tokens[0], big.NewInt(100))
+	if err != nil {
+		t.Fatalf("Failed to burn: %v", err)
+	}
+	if report := CheckSolvency(stateDB, nil); !report.Solvent() {
+		t.Errorf("Expected solvent backing after burn, got %v", report.Issues)
+	}
+
+	// Pools of legacy tokens, missing from the registry, count towards the backing
+	stateDB, tokens = newState()
+	legacy := common.HexToAddress("0x1e9ac")
+	stateDB.SetState(legacy, backingpool.LegacySlot(legacy, "SmartDeFi-BackingPool", backingpool.SlotTotalBacking), common.BigToHash(big.NewInt(200)))
+	stateDB.SetState(legacy, backingpool.LegacySlot(legacy, "SmartDeFi-BackingPool", backingpool.SlotTotalSupply), common.BigToHash(big.NewInt(1000)))
+	stateDB.balances[PrecompileAddressBytes].Add(stateDB.balances[PrecompileAddressBytes], big.NewInt(200))
+	if report := CheckSolvency(stateDB, nil); report.Solvent() {
+		t.Errorf("Expected legacy backing to be unaccounted without the legacy tokens")
+	}
+	report = CheckSolvency(stateDB, []common.Address{tokens[0], legacy})
+	if !report.Solvent() {
+		t.Fatalf("Expected solvent backing with the legacy tokens, got %v", report.Issues)
+	}
+	if len(report.Tokens) != 3 || report.Tokens[2] != legacy || report.TotalBacking.Int64() != 700 {
+		t.Errorf("Expected 3 tokens backed by 700, got %v backed by %s", report.Tokens, report.TotalBacking)
+	}
+
+	tests := []struct {
+		tamper func(stateDB *mockStateDB, tokens []common.Address)
+		issues []string
+	}{
+		// Smart coin locked outside any pool
+		{
+			tamper: func(stateDB *mockStateDB, tokens []common.Address) {
+				stateDB.balances[PrecompileAddressBytes].Add(stateDB.balances[PrecompileAddressBytes], big.NewInt(1))
+			},
+			issues: []string{"precompile balance 501 differs from total backing 500"},
+		},
+		// Backing underflowed in a pool
+		{
+			tamper: func(stateDB *mockStateDB, tokens []common.Address) {
+				pool := backingpool.GetBackingPool(stateDB, tokens[1])
+				pool.RemoveBacking(big.NewInt(1))
+				pool.TotalBacking.And(pool.TotalBacking, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)))
+				pool.BackingAmounts[0].Set(pool.TotalBacking)
+				backingpool.SetBackingPool(stateDB, pool)
+			},
+			issues: []string{
+				tokens[1].Hex() + ": negative total backing -1",
+				tokens[1].Hex() + ": negative backing amount 0 -1",
+				"precompile balance 500 differs from total backing",
+			},
+		},
+		// More tokens burned than supplied, and amounts out of sync
+		{
+			tamper: func(stateDB *mockStateDB, tokens []common.Address) {
+				pool := backingpool.GetBackingPool(stateDB, tokens[0])
+				pool.BurnTokens(big.NewInt(1001))
+				pool.BackingAmounts[0] = big.NewInt(1)
+				backingpool.SetBackingPool(stateDB, pool)
+			},
+			issues: []string{
+				tokens[0].Hex() + ": negative circulating supply: burned 1001 of 1000",
+				tokens[0].Hex() + ": backing amounts 1 differ from total backing 500",
+			},
+		},
+		// Registered token without a pool
+		{
+			tamper: func(stateDB *mockStateDB, tokens []common.Address) {
+				for _, slot := range backingpool.PoolSlots(1) {
+					stateDB.SetState(tokens[1], slot, common.Hash{})
+				}
+			},
+			issues: []string{tokens[1].Hex() + ": registered token without backing pool"},
+		},
+	}
+	for i, test := range tests {
+		stateDB, _ := newState()
+		test.tamper(stateDB, tokens)
+
+		report := CheckSolvency(stateDB, nil)
+		if len(report.Issues) != len(test.issues) {
+			t.Fatalf("Test %d: expected %d issues, got %v", i, len(test.issues), report.Issues)
+		}
+		for j, want := range test.issues {
+			if have := report.Issues[j].String(); !strings.HasPrefix(have, want) {
+				t.Errorf("Test %d: expected issue %q, got %q", i, want, have)
+			}
+		}
+	}
+}
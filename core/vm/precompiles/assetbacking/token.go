@@ -0,0 +1,311 @@
+// Package assetbacking - native ERC-20 implementation of created tokens.
+package assetbacking
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// TokenDecimals is the number of decimals of every created token.
+	TokenDecimals = 18
+
+	// maxStringLength is the maximum length in bytes of the name and symbol of
+	// a token. Longer strings are rejected at creation, so a longer length read
+	// from storage can only be corrupt.
+	maxStringLength = 1024
+
+	// Gas costs, on top of the state accesses charged by the EVM.
+	GasTokenRead         = 700  // Cost for reading token state
+	GasTokenApprove      = 2000 // Cost for approve
+	GasTokenTransfer     = 3000 // Cost for transfer
+	GasTokenTransferFrom = 4000 // Cost for transferFrom
+)
+
+var (
+	// TokenCode is the code deployed at every created token address. Calls to an
+	// account holding this code are served natively by Token. The leading 0xEF
+	// byte cannot be deployed through CREATE (EIP-3541), so the code cannot be
+	// forged, and the interpreter aborts on it should it ever be executed.
+	TokenCode = []byte{0xef, 'S', 'D', 0x01}
+
+	// Method IDs of the ERC-20 interface.
+	MethodIDName         = crypto.Keccak256([]byte("name()"))[:4]
+	MethodIDSymbol       = crypto.Keccak256([]byte("symbol()"))[:4]
+	MethodIDDecimals     = crypto.Keccak256([]byte("decimals()"))[:4]
+	MethodIDTotalSupply  = crypto.Keccak256([]byte("totalSupply()"))[:4]
+	MethodIDBalanceOf    = crypto.Keccak256([]byte("balanceOf(address)"))[:4]
+	MethodIDTransfer     = crypto.Keccak256([]byte("transfer(address,uint256)"))[:4]
+	MethodIDAllowance    = crypto.Keccak256([]byte("allowance(address,address)"))[:4]
+	MethodIDApprove      = crypto.Keccak256([]byte("approve(address,uint256)"))[:4]
+	MethodIDTransferFrom = crypto.Keccak256([]byte("transferFrom(address,address,uint256)"))[:4]
+
+	// Storage slots of the token metadata.
+	slotTokenName   = crypto.Keccak256Hash([]byte("SmartDeFi-Token-Name"))
+	slotTokenSymbol = crypto.Keccak256Hash([]byte("SmartDeFi-Token-Symbol"))
+	slotTokenOwner  = crypto.Keccak256Hash([]byte("SmartDeFi-Token-Owner"))
+)
+
+// IsToken reports whether code is the code of a created token.
+func IsToken(code []byte) bool {
+	return bytes.Equal(code, TokenCode)
+}
+
+// Token implements the ERC-20 interface of every created token.
+// The token is identified by the called address, so one instance serves all tokens.
+type Token struct{}
+
+// Name returns the contract name.
+func (t *Token) Name() string {
+	return "SmartDeFi Token"
+}
+
+// RequiredGas calculates the gas required for the token operation.
+func (t *Token) RequiredGas(input []byte) uint64 {
+	if len(input) < 4 {
+		return 0
+	}
+
+	switch {
+	case bytes.Equal(input[:4], MethodIDTransfer):
+		return GasTokenTransfer
+	case bytes.Equal(input[:4], MethodIDApprove):
+		return GasTokenApprove
+	case bytes.Equal(input[:4], MethodIDTransferFrom):
+		return GasTokenTransferFrom
+	default:
+		return GasTokenRead
+	}
+}
+
+// Run implements the stateless PrecompiledContract interface.
+// A token cannot operate without state, so a call without a context reverts.
+func (t *Token) Run(input []byte) ([]byte, error) {
+	return nil, ErrExecutionReverted
+}
+
+// RunStateful executes a token call within the given context.
+func (t *Token) RunStateful(ctx CallContext, input []byte) ([]byte, error) {
+	// No token method is payable
+	if ctx.Value != nil && !ctx.Value.IsZero() {
+		return nil, revert("NonPayable", ctx.Value.ToBig())
+	}
+	if len(input) < 4 {
+		return nil, revert("InvalidInput")
+	}
+	method, err := tokenABI.MethodById(input[:4])
+	if err != nil {
+		return nil, revert("InvalidInput")
+	}
+	args, err := method.Inputs.Unpack(input[4:])
+	if err != nil {
+		return nil, revert("InvalidInput")
+	}
+	stateDB, token := ctx.StateDB, ctx.Address
+
+	var output interface{}
+	switch method.Name {
+	case "name":
+		output = getString(stateDB, token, slotTokenName)
+	case "symbol":
+		output = getString(stateDB, token, slotTokenSymbol)
+	case "decimals":
+		output = uint8(TokenDecimals)
+	case "totalSupply":
+		output = TokenTotalSupply(stateDB, token)
+	case "balanceOf":
+		output = TokenBalance(stateDB, token, args[0].(common.Address))
+	case "allowance":
+		output = tokenAllowance(stateDB, token, args[0].(common.Address), args[1].(common.Address))
+	case "transfer":
+		if ctx.ReadOnly {
+			return nil, revert("StaticCallViolation")
+		}
+		if err := transferToken(ctx, ctx.Caller, args[0].(common.Address), args[1].(*big.Int)); err != nil {
+			return nil, err
+		}
+		output = true
+	case "approve":
+		if ctx.ReadOnly {
+			return nil, revert("StaticCallViolation")
+		}
+		spender, value := args[0].(common.Address), args[1].(*big.Int)
+		if spender == (common.Address{}) {
+			return nil, revert("ZeroAddress")
+		}
+		setTokenAllowance(stateDB, token, ctx.Caller, spender, value)
+		emitTokenEvent(ctx, token, "Approval", ctx.Caller, spender, value)
+		output = true
+	case "transferFrom":
+		if ctx.ReadOnly {
+			return nil, revert("StaticCallViolation")
+		}
+		from, to, value := args[0].(common.Address), args[1].(common.Address), args[2].(*big.Int)
+
+		// Spend the allowance, leaving an unlimited approval untouched
+		allowance := tokenAllowance(stateDB, token, from, ctx.Caller)
+		if allowance.Cmp(value) < 0 {
+			return nil, revert("InsufficientAllowance", ctx.Caller, allowance, value)
+		}
+		if allowance.Cmp(math.MaxBig256) != 0 {
+			setTokenAllowance(stateDB, token, from, ctx.Caller, allowance.Sub(allowance, value))
+		}
+		if err := transferToken(ctx, from, to, value); err != nil {
+			return nil, err
+		}
+		output = true
+	}
+	return method.Outputs.Pack(output)
+}
+
+// deployToken installs the token code at the token address and credits the
+// full supply to the owner, or to the token itself as the escrow of its LGE.
+func deployToken(ctx CallContext, token common.Address, config TokenConfig) {
+	stateDB := ctx.StateDB
+
+	stateDB.SetNonce(token, 1, tracing.NonceChangeNewContract)
+	stateDB.SetCode(token, TokenCode, tracing.CodeChangeContractCreation)
+	setString(stateDB, token, slotTokenName, config.Name)
+	setString(stateDB, token, slotTokenSymbol, config.Symbol)
+	stateDB.SetState(token, slotTokenOwner, common.BytesToHash(config.Owner.Bytes()))
+
+	holder := config.Owner
+	if config.EnableLGE {
+		holder = token
+	}
+	setTokenBalance(stateDB, token, holder, config.TotalSupply)
+	emitTokenEvent(ctx, token, "Transfer", common.Address{}, holder, config.TotalSupply)
+}
+
+// transferToken moves value tokens of the called token from one holder to another.
+func transferToken(ctx CallContext, from, to common.Address, value *big.Int) error {
+	stateDB, token := ctx.StateDB, ctx.Address
+
+	if from == (common.Address{}) || to == (common.Address{}) {
+		return revert("ZeroAddress")
+	}
+	if transfersLocked(ctx, token) {
+		return revert("TransfersLocked", token)
+	}
+	if err := checkOnlySB(ctx, token, from, to); err != nil {
+		return err
+	}
+	balance := TokenBalance(stateDB, token, from)
+	if balance.Cmp(value) < 0 {
+		return revert("InsufficientBalance", from, balance, value)
+	}
+	// Debit before reading the recipient so a self-transfer is a no-op
+	setTokenBalance(stateDB, token, from, balance.Sub(balance, value))
+
+	// Deduct the fees, crediting the recipient with the remainder
+	received, err := chargeTransferFees(ctx, from, to, value)
+	if err != nil {
+		return err
+	}
+	setTokenBalance(stateDB, token, to, new(big.Int).Add(TokenBalance(stateDB, token, to), received))
+
+	emitTokenEvent(ctx, token, "Transfer", from, to, received)
+	return nil
+}
+
+// TokenName returns the name of a token.
+func TokenName(stateDB backingpool.StateReader, token common.Address) string {
+	return getString(stateDB, token, slotTokenName)
+}
+
+// TokenSymbol returns the symbol of a token.
+func TokenSymbol(stateDB backingpool.StateReader, token common.Address) string {
+	return getString(stateDB, token, slotTokenSymbol)
+}
+
+// TokenOwner returns the owner of a token.
+func TokenOwner(stateDB backingpool.StateReader, token common.Address) common.Address {
+	return common.BytesToAddress(stateDB.GetState(token, slotTokenOwner).Bytes())
+}
+
+// TokenTotalSupply returns the circulating supply of a token, which excludes
+// all tokens burned for their backing.
+func TokenTotalSupply(stateDB backingpool.StateReader, token common.Address) *big.Int {
+	pool := backingpool.GetBackingPool(stateDB, token)
+	if pool == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Sub(pool.TotalSupply, pool.BurnedSupply)
+}
+
+// TokenBalance returns the token balance of a holder.
+func TokenBalance(stateDB backingpool.StateReader, token, holder common.Address) *big.Int {
+	return stateDB.GetState(token, tokenBalanceSlot(holder)).Big()
+}
+
+func setTokenBalance(stateDB StateDB, token, holder common.Address, balance *big.Int) {
+	stateDB.SetState(token, tokenBalanceSlot(holder), common.BigToHash(balance))
+}
+
+func tokenAllowance(stateDB StateDB, token, owner, spender common.Address) *big.Int {
+	return stateDB.GetState(token, tokenAllowanceSlot(owner, spender)).Big()
+}
+
+func setTokenAllowance(stateDB StateDB, token, owner, spender common.Address, value *big.Int) {
+	stateDB.SetState(token, tokenAllowanceSlot(owner, spender), common.BigToHash(value))
+}
+
+func tokenBalanceSlot(holder common.Address) common.Hash {
+	return crypto.Keccak256Hash(holder.Bytes(), []byte("SmartDeFi-Token-Balance"))
+}
+
+func tokenAllowanceSlot(owner, spender common.Address) common.Hash {
+	return crypto.Keccak256Hash(owner.Bytes(), spender.Bytes(), []byte("SmartDeFi-Token-Allowance"))
+}
+
+// emitTokenEvent emits a Transfer or Approval event of a token.
+func emitTokenEvent(ctx CallContext, token common.Address, event string, from, to common.Address, value *big.Int) {
+	ctx.StateDB.AddLog(&types.Log{
+		Address: token,
+		Topics: []common.Hash{
+			tokenABI.Events[event].ID,
+			common.BytesToHash(from.Bytes()),
+			common.BytesToHash(to.Bytes()),
+		},
+		Data:        common.BigToHash(value).Bytes(),
+		BlockNumber: ctx.BlockNumber,
+	})
+}
+
+// setString stores a string at slot: the slot holds the length and the data
+// follows in 32-byte words starting at keccak256(slot).
+func setString(stateDB StateDB, addr common.Address, slot common.Hash, s string) {
+	stateDB.SetState(addr, slot, common.BigToHash(big.NewInt(int64(len(s)))))
+
+	data := crypto.Keccak256Hash(slot.Bytes()).Big()
+	for i := 0; i < len(s); i += 32 {
+		var word common.Hash
+		copy(word[:], s[i:])
+		stateDB.SetState(addr, common.BigToHash(new(big.Int).Add(data, big.NewInt(int64(i/32)))), word)
+	}
+}
+
+// getString loads a string stored by setString. A length above maxStringLength
+// reads as the empty string, without loading any data.
+func getString(stateDB backingpool.StateReader, addr common.Address, slot common.Hash) string {
+	stored := stateDB.GetState(addr, slot).Big()
+	if !stored.IsUint64() || stored.Uint64() > maxStringLength {
+		return ""
+	}
+	length := stored.Uint64()
+
+	s := make([]byte, 0, length)
+	data := crypto.Keccak256Hash(slot.Bytes()).Big()
+	for i := uint64(0); i < length; i += 32 {
+		word := stateDB.GetState(addr, common.BigToHash(new(big.Int).Add(data, new(big.Int).SetUint64(i/32))))
+		s = append(s, word[:min(32, length-i)]...)
+	}
+	return string(s)
+}
@@ -0,0 +1,240 @@
+// Package assetbacking - Tests for the native ERC-20 implementation of created tokens.
+package assetbacking
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// createTestToken creates a token owned by owner on the given state.
+func createTestToken(t *testing.T, stateDB *mockStateDB, owner common.Address, supply *big.Int) common.Address {
+	t.Helper()
+
+	fees := [12]*big.Int{}
+	for i := range fees {
+		fees[i] = big.NewInt(0)
+	}
+	input, err := EncodeCreateToken(TokenConfig{
+		Name:           "A Token With A Name Longer Than One Word",
+		Symbol:         "LONG",
+		TotalSupply:    supply,
+		InitialBacking: big.NewInt(0),
+		Fees:           fees,
+		Owner:          owner,
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	creator := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	result, err := (&Precompile{}).RunStateful(CallContext{StateDB: stateDB, Caller: creator}, input)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+	return common.BytesToAddress(result)
+}
+
+// callToken calls a method of the token and returns the unpacked outputs.
+func callToken(ctx CallContext, method string, args ...interface{}) ([]interface{}, error) {
+	input, err := tokenABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	result, err := (&Token{}).RunStateful(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return tokenABI.Methods[method].Outputs.Unpack(result)
+}
+
+// TestTokenDeployment tests that a created token is a working ERC-20 holding
+// the full supply at the owner.
+func TestTokenDeployment(t *testing.T) {
+	stateDB := newMockStateDB()
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	supply := big.NewInt(1000000)
+	token := createTestToken(t, stateDB, owner, supply)
+
+	// Verify the token code was deployed
+	if !IsToken(stateDB.GetCode(token)) {
+		t.Fatalf("Expected token code at %s, got %x", token.Hex(), stateDB.GetCode(token))
+	}
+	if stateDB.GetNonce(token) != 1 {
+		t.Errorf("Expected token nonce 1, got %d", stateDB.GetNonce(token))
+	}
+
+	// Verify the metadata and the owner's balance
+	ctx := CallContext{StateDB: stateDB, Address: token, ReadOnly: true}
+	for _, tt := range []struct {
+		method string
+		args   []interface{}
+		want   interface{}
+	}{
+		{"name", nil, "A Token With A Name Longer Than One Word"},
+		{"symbol", nil, "LONG"},
+		{"decimals", nil, uint8(TokenDecimals)},
+		{"totalSupply", nil, supply},
+		{"balanceOf", []interface{}{owner}, supply},
+		{"balanceOf", []interface{}{common.HexToAddress("0x01")}, big.NewInt(0)},
+	} {
+		out, err := callToken(ctx, tt.method, tt.args...)
+		if err != nil {
+			t.Fatalf("Failed to call %s: %v", tt.method, err)
+		}
+		if want, ok := tt.want.(*big.Int); ok {
+			if out[0].(*big.Int).Cmp(want) != 0 {
+				t.Errorf("Expected %s %v, got %v", tt.method, want, out[0])
+			}
+		} else if out[0] != tt.want {
+			t.Errorf("Expected %s %v, got %v", tt.method, tt.want, out[0])
+		}
+	}
+
+	// Verify the mint was announced with a Transfer event of the token
+	var logs []*types.Log
+	for _, log := range stateDB.logs {
+		if log.Address == token {
+			logs = append(logs, log)
+		}
+	}
+	if len(logs) != 1 {
+		t.Fatalf("Expected 1 token log, got %d", len(logs))
+	}
+	log := logs[0]
+	if log.Topics[0] != tokenABI.Events["Transfer"].ID ||
+		log.Topics[1] != (common.Hash{}) || log.Topics[2] != common.BytesToHash(owner.Bytes()) {
+		t.Errorf("Unexpected mint log: %+v", log)
+	}
+}
+
+// TestTokenStringLength tests that names and symbols are bounded: longer ones
+// are rejected at creation, and a corrupt length in storage reads as empty
+// instead of allocating and loading the claimed data.
+func TestTokenStringLength(t *testing.T) {
+	stateDB := newMockStateDB()
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	token := createTestToken(t, stateDB, owner, big.NewInt(1000))
+
+	for _, length := range []*big.Int{
+		big.NewInt(maxStringLength + 1),
+		new(big.Int).Lsh(big.NewInt(1), 64),
+		math.MaxBig256,
+	} {
+		stateDB.SetState(token, slotTokenName, common.BigToHash(length))
+		if name := TokenName(stateDB, token); name != "" {
+			t.Errorf("Expected empty name for length %d, got %q", length, name)
+		}
+	}
+	stateDB.SetState(token, slotTokenName, common.BigToHash(big.NewInt(maxStringLength)))
+	if name := TokenName(stateDB, token); len(name) != maxStringLength {
+		t.Errorf("Expected name of length %d, got %d", maxStringLength, len(name))
+	}
+
+	fees := [12]*big.Int{}
+	for i := range fees {
+		fees[i] = big.NewInt(0)
+	}
+	input, err := EncodeCreateToken(TokenConfig{
+		Name:           string(make([]byte, maxStringLength+1)),
+		Symbol:         "LONG",
+		TotalSupply:    big.NewInt(1000),
+		InitialBacking: big.NewInt(0),
+		Fees:           fees,
+		Owner:          owner,
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	if _, err := (&Precompile{}).RunStateful(CallContext{StateDB: stateDB, Caller: owner}, input); err == nil {
+		t.Error("Expected error when creating a token with an overlong name")
+	}
+}
+
+// TestTokenTransfer tests transfers, approvals and transfers on behalf of
+// another holder.
+func TestTokenTransfer(t *testing.T) {
+	stateDB := newMockStateDB()
+	owner := common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+	alice := common.HexToAddress("0xa11ce")
+	bob := common.HexToAddress("0xb0b")
+	token := createTestToken(t, stateDB, owner, big.NewInt(1000))
+
+	balanceOf := func(holder common.Address) *big.Int {
+		return TokenBalance(stateDB, token, holder)
+	}
+
+	// Transfer from the owner to alice
+	ctx := CallContext{StateDB: stateDB, Address: token, Caller: owner}
+	if _, err := callToken(ctx, "transfer", alice, big.NewInt(300)); err != nil {
+		t.Fatalf("Failed to transfer: %v", err)
+	}
+	if balanceOf(owner).Cmp(big.NewInt(700)) != 0 || balanceOf(alice).Cmp(big.NewInt(300)) != 0 {
+		t.Errorf("Unexpected balances after transfer: owner %v, alice %v", balanceOf(owner), balanceOf(alice))
+	}
+
+	// Transfers exceeding the balance, to the zero address or in a static
+	// context must revert
+	ctx.Caller = alice
+	if _, err := callToken(ctx, "transfer", bob, big.NewInt(301)); err == nil {
+		t.Error("Expected error when transferring more than the balance")
+	}
+	if _, err := callToken(ctx, "transfer", common.Address{}, big.NewInt(1)); err == nil {
+		t.Error("Expected error when transferring to the zero address")
+	}
+	static := ctx
+	static.ReadOnly = true
+	if _, err := callToken(static, "transfer", bob, big.NewInt(1)); err == nil {
+		t.Error("Expected error when transferring in a static context")
+	}
+
+	// A self-transfer leaves the balance unchanged
+	if _, err := callToken(ctx, "transfer", alice, big.NewInt(300)); err != nil {
+		t.Fatalf("Failed to self-transfer: %v", err)
+	}
+	if balanceOf(alice).Cmp(big.NewInt(300)) != 0 {
+		t.Errorf("Expected balance 300 after self-transfer, got %v", balanceOf(alice))
+	}
+
+	// Bob spends part of an allowance granted by alice
+	if _, err := callToken(ctx, "approve", bob, big.NewInt(100)); err != nil {
+		t.Fatalf("Failed to approve: %v", err)
+	}
+	ctx.Caller = bob
+	if _, err := callToken(ctx, "transferFrom", alice, bob, big.NewInt(101)); err == nil {
+		t.Error("Expected error when spending more than the allowance")
+	}
+	if _, err := callToken(ctx, "transferFrom", alice, bob, big.NewInt(60)); err != nil {
+		t.Fatalf("Failed to transferFrom: %v", err)
+	}
+	out, err := callToken(ctx, "allowance", alice, bob)
+	if err != nil {
+		t.Fatalf("Failed to get allowance: %v", err)
+	}
+	if out[0].(*big.Int).Cmp(big.NewInt(40)) != 0 {
+		t.Errorf("Expected allowance 40, got %v", out[0])
+	}
+	if balanceOf(alice).Cmp(big.NewInt(240)) != 0 || balanceOf(bob).Cmp(big.NewInt(60)) != 0 {
+		t.Errorf("Unexpected balances after transferFrom: alice %v, bob %v", balanceOf(alice), balanceOf(bob))
+	}
+
+	// An unlimited allowance is never decreased
+	ctx.Caller = alice
+	if _, err := callToken(ctx, "approve", bob, math.MaxBig256); err != nil {
+		t.Fatalf("Failed to approve: %v", err)
+	}
+	ctx.Caller = bob
+	if _, err := callToken(ctx, "transferFrom", alice, bob, big.NewInt(40)); err != nil {
+		t.Fatalf("Failed to transferFrom: %v", err)
+	}
+	if allowance := tokenAllowance(stateDB, token, alice, bob); allowance.Cmp(math.MaxBig256) != 0 {
+		t.Errorf("Expected unlimited allowance to remain, got %v", allowance)
+	}
+
+	// The supply is unaffected by transfers
+	if supply := TokenTotalSupply(stateDB, token); supply.Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("Expected total supply 1000, got %v", supply)
+	}
+}
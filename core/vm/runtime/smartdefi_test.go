@@ -0,0 +1,318 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
+	"github.com/ethereum/go-ethereum/core/vm/program"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// smartDeFiCaller returns the code of a contract which invokes the asset-backing
+// precompile with the given opcode, input and value (if the opcode carries one),
+// stores the success flag in slot 0 and returns the precompile's return data.
+func smartDeFiCaller(op vm.OpCode, input []byte, value *big.Int) []byte {
+	p := program.New().Mstore(input, 0)
+	switch op {
+	case vm.CALL:
+		p.Call(nil, assetbacking.PrecompileAddressBytes, value, 0, len(input), 0, 0)
+	case vm.CALLCODE:
+		p.CallCode(nil, assetbacking.PrecompileAddressBytes, value, 0, len(input), 0, 0)
+	case vm.DELEGATECALL:
+		p.DelegateCall(nil, assetbacking.PrecompileAddressBytes, 0, len(input), 0, 0)
+	case vm.STATICCALL:
+		p.StaticCall(nil, assetbacking.PrecompileAddressBytes, 0, len(input), 0, 0)
+	}
+	return p.Push(0).Op(vm.SSTORE).
+		Op(vm.RETURNDATASIZE).Push0().Push0().Op(vm.RETURNDATACOPY).
+		Op(vm.RETURNDATASIZE).Push0().Op(vm.RETURN).
+		Bytes()
+}
+
+// TestSmartDeFiPrecompileCallTypes checks the behaviour of the asset-backing
+// precompile when invoked from a contract through each of the call opcodes.
+func TestSmartDeFiPrecompileCallTypes(t *testing.T) {
+	config := *params.MergedTestChainConfig
+	config.SmartDeFiTime = new(uint64)
+	config.OsakaTime = nil
+
+	var (
+		caller  = common.HexToAddress("0xca11e4")
+		funds   = uint256.NewInt(params.Ether)
+		backing = big.NewInt(1000)
+		fees    [12]*big.Int
+	)
+	for i := range fees {
+		fees[i] = new(big.Int)
+	}
+	create, err := assetbacking.EncodeCreateToken(assetbacking.TokenConfig{
+		Name:           "Test Token",
+		Symbol:         "TEST",
+		TotalSupply:    big.NewInt(1_000_000),
+		InitialBacking: backing,
+		Fees:           fees,
+		Owner:          caller,
+	})
+	if err != nil {
+		t.Fatalf("failed to encode input: %v", err)
+	}
+	// setup deploys a caller contract on a fresh state and returns the state.
+	setup := func(code []byte) *state.StateDB {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		statedb.SetCode(caller, code, tracing.CodeChangeUnspecified)
+		statedb.AddBalance(caller, funds, tracing.BalanceChangeUnspecified)
+		return statedb
+	}
+	// success returns the success flag recorded by the caller contract.
+	success := func(statedb *state.StateDB) bool {
+		return statedb.GetState(caller, common.Hash{}) != (common.Hash{})
+	}
+
+	// CALL executes with the calling contract as msg.sender, so the backing is
+	// sent from the contract's own balance.
+	t.Run("CALL", func(t *testing.T) {
+		statedb := setup(smartDeFiCaller(vm.CALL, create, backing))
+		ret, _, err := Call(caller, nil, &Config{ChainConfig: &config, State: statedb})
+		if err != nil {
+			t.Fatalf("call failed: %v", err)
+		}
+		if !success(statedb) {
+			t.Fatal("precompile call failed")
+		}
+		token := common.BytesToAddress(ret)
+		if pool := backingpool.GetBackingPool(statedb, token); pool == nil || pool.TotalBacking.Cmp(backing) != 0 {
+			t.Fatalf("backing pool not created: %+v", pool)
+		}
+		want := new(uint256.Int).Sub(funds, uint256.MustFromBig(backing))
+		if have := statedb.GetBalance(caller); !have.Eq(want) {
+			t.Errorf("caller balance mismatch: have %v, want %v", have, want)
+		}
+		if have := statedb.GetBalance(assetbacking.PrecompileAddressBytes); have.ToBig().Cmp(backing) != 0 {
+			t.Errorf("locked balance mismatch: have %v, want %v", have, backing)
+		}
+
+		// A STATICCALL may query the token created above.
+		for _, name := range []string{"getBacking", "getFloorPrice"} {
+			var input []byte
+			if name == "getBacking" {
+				input, err = assetbacking.EncodeGetBacking(token, big.NewInt(1000))
+			} else {
+				input, err = assetbacking.EncodeGetFloorPrice(token)
+			}
+			if err != nil {
+				t.Fatalf("%s: failed to encode input: %v", name, err)
+			}
+			statedb.SetCode(caller, smartDeFiCaller(vm.STATICCALL, input, nil), tracing.CodeChangeUnspecified)
+			statedb.SetState(caller, common.Hash{}, common.Hash{})
+			if _, _, err := Call(caller, nil, &Config{ChainConfig: &config, State: statedb}); err != nil {
+				t.Fatalf("%s: call failed: %v", name, err)
+			}
+			if !success(statedb) {
+				t.Errorf("%s: static call to view method failed", name)
+			}
+		}
+	})
+
+	// STATICCALL, DELEGATECALL and CALLCODE must all revert a token creation
+	// without modifying any state. Only the static call reaches the precompile,
+	// which returns its custom error as the revert data.
+	for _, op := range []vm.OpCode{vm.STATICCALL, vm.DELEGATECALL, vm.CALLCODE} {
+		t.Run(op.String(), func(t *testing.T) {
+			statedb := setup(smartDeFiCaller(op, create, backing))
+			ret, _, err := Call(caller, nil, &Config{ChainConfig: &config, State: statedb})
+			if err != nil {
+				t.Fatalf("call failed: %v", err)
+			}
+			if success(statedb) {
+				t.Fatal("precompile call succeeded")
+			}
+			if op == vm.STATICCALL {
+				if reason, err := assetbacking.UnpackRevert(ret); err != nil || reason != "StaticCallViolation()" {
+					t.Errorf("revert reason mismatch: have %q (%v), want StaticCallViolation()", reason, err)
+				}
+			} else if len(ret) != 0 {
+				t.Errorf("unexpected revert data: %x", ret)
+			}
+			if have := statedb.GetBalance(caller); !have.Eq(funds) {
+				t.Errorf("caller balance changed: have %v, want %v", have, funds)
+			}
+			if have := statedb.GetBalance(assetbacking.PrecompileAddressBytes); !have.IsZero() {
+				t.Errorf("backing locked: have %v", have)
+			}
+		})
+	}
+}
+
+// TestSmartDeFiToken checks that a created token is served as an ERC-20 at its
+// address, using nothing but the standard token ABI.
+func TestSmartDeFiToken(t *testing.T) {
+	config := *params.MergedTestChainConfig
+	config.SmartDeFiTime = new(uint64)
+	config.OsakaTime = nil
+
+	var (
+		statedb, _ = state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		owner      = common.HexToAddress("0x0e0e0e")
+		holder     = common.HexToAddress("0xb0b")
+		supply     = big.NewInt(1_000_000)
+		fees       [12]*big.Int
+	)
+	for i := range fees {
+		fees[i] = new(big.Int)
+	}
+	create, err := assetbacking.EncodeCreateToken(assetbacking.TokenConfig{
+		Name:           "Test Token",
+		Symbol:         "TEST",
+		TotalSupply:    supply,
+		InitialBacking: new(big.Int),
+		Fees:           fees,
+		Owner:          owner,
+	})
+	if err != nil {
+		t.Fatalf("failed to encode input: %v", err)
+	}
+	cfg := &Config{ChainConfig: &config, State: statedb, Origin: owner}
+	ret, _, err := Call(assetbacking.PrecompileAddressBytes, create, cfg)
+	if err != nil {
+		t.Fatalf("token creation failed: %v", err)
+	}
+	token := common.BytesToAddress(ret)
+	if code := statedb.GetCode(token); !assetbacking.IsToken(code) {
+		t.Fatalf("token code mismatch: have %x, want %x", code, assetbacking.TokenCode)
+	}
+
+	erc20, err := abi.JSON(strings.NewReader(assetbacking.TokenABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := func(method string, args ...interface{}) []interface{} {
+		t.Helper()
+
+		input, err := erc20.Pack(method, args...)
+		if err != nil {
+			t.Fatalf("%s: failed to pack input: %v", method, err)
+		}
+		ret, _, err := Call(token, input, cfg)
+		if err != nil {
+			t.Fatalf("%s: call failed: %v", method, err)
+		}
+		out, err := erc20.Unpack(method, ret)
+		if err != nil {
+			t.Fatalf("%s: failed to unpack output: %v", method, err)
+		}
+		return out
+	}
+	if have := call("balanceOf", owner)[0].(*big.Int); have.Cmp(supply) != 0 {
+		t.Fatalf("owner balance mismatch: have %v, want %v", have, supply)
+	}
+	if have := call("transfer", holder, big.NewInt(1000))[0].(bool); !have {
+		t.Fatal("transfer returned false")
+	}
+	if have := call("balanceOf", holder)[0].(*big.Int); have.Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("holder balance mismatch: have %v, want %v", have, 1000)
+	}
+	if have := call("totalSupply")[0].(*big.Int); have.Cmp(supply) != 0 {
+		t.Errorf("total supply mismatch: have %v, want %v", have, supply)
+	}
+	if have := call("symbol")[0].(string); have != "TEST" {
+		t.Errorf("symbol mismatch: have %q, want %q", have, "TEST")
+	}
+}
+
+// TestSmartDeFiTokenCallTypes checks which calls into a token are served by
+// its native implementation: a STATICCALL may query the token, a DELEGATECALL
+// reverts, and an account delegating to the token does not become a token.
+func TestSmartDeFiTokenCallTypes(t *testing.T) {
+	config := *params.MergedTestChainConfig
+	config.SmartDeFiTime = new(uint64)
+	config.OsakaTime = nil
+
+	var (
+		statedb, _ = state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		owner      = common.HexToAddress("0x0e0e0e")
+		caller     = common.HexToAddress("0xca11e4")
+		delegated  = common.HexToAddress("0xde1e")
+		supply     = big.NewInt(1_000_000)
+		fees       [12]*big.Int
+	)
+	for i := range fees {
+		fees[i] = new(big.Int)
+	}
+	create, err := assetbacking.EncodeCreateToken(assetbacking.TokenConfig{
+		Name:           "Test Token",
+		Symbol:         "TEST",
+		TotalSupply:    supply,
+		InitialBacking: new(big.Int),
+		Fees:           fees,
+		Owner:          owner,
+	})
+	if err != nil {
+		t.Fatalf("failed to encode input: %v", err)
+	}
+	cfg := &Config{ChainConfig: &config, State: statedb, Origin: owner}
+	ret, _, err := Call(assetbacking.PrecompileAddressBytes, create, cfg)
+	if err != nil {
+		t.Fatalf("token creation failed: %v", err)
+	}
+	token := common.BytesToAddress(ret)
+
+	erc20, err := abi.JSON(strings.NewReader(assetbacking.TokenABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input, err := erc20.Pack("balanceOf", owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		op   vm.OpCode
+		want bool
+	}{
+		{vm.STATICCALL, true},
+		{vm.DELEGATECALL, false},
+	} {
+		p := program.New().Mstore(input, 0)
+		if tt.op == vm.STATICCALL {
+			p.StaticCall(nil, token, 0, len(input), 0, 0)
+		} else {
+			p.DelegateCall(nil, token, 0, len(input), 0, 0)
+		}
+		statedb.SetCode(caller, p.Push(0).Op(vm.SSTORE).Bytes(), tracing.CodeChangeUnspecified)
+		if _, _, err := Call(caller, nil, cfg); err != nil {
+			t.Fatalf("%v: call failed: %v", tt.op, err)
+		}
+		if have := statedb.GetState(caller, common.Hash{}) == common.BigToHash(common.Big1); have != tt.want {
+			t.Errorf("%v: success mismatch: have %v, want %v", tt.op, have, tt.want)
+		}
+	}
+	statedb.SetCode(delegated, types.AddressToDelegation(token), tracing.CodeChangeUnspecified)
+	if _, _, err := Call(delegated, input, cfg); err == nil {
+		t.Error("call to an account delegating to a token succeeded")
+	}
+}
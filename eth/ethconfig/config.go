@@ -108,6 +108,8 @@ type Config struct {
 	LogNoHistory         bool   `toml:",omitempty"` // No log search index is maintained.
 	LogExportCheckpoints string // export log index checkpoints to file
 	StateHistory         uint64 `toml:",omitempty"` // The maximum number of blocks from head whose state histories are reserved.
+	FloorPriceIndex      bool   `toml:",omitempty"` // Whether the floor-price history of SmartDeFi tokens is indexed.
+	FloorPriceHistory    uint64 `toml:",omitempty"` // The maximum number of blocks from head whose floor-price checkpoints are reserved.
 
 	// State scheme represents the scheme used to store ethereum states and trie
 	// nodes on top. It can be 'hash', 'path', or none which means use the scheme
@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/misc"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -390,6 +391,9 @@ func (api *API) traceChain(start, end *types.Block, config *TraceConfig, closed
 			if api.backend.ChainConfig().IsPrague(next.Number(), next.Time()) {
 				core.ProcessParentBlockHash(next.ParentHash(), evm)
 			}
+			if api.backend.ChainConfig().IsSmartDeFiTransition(next.Number(), block.Time(), next.Time()) {
+				misc.ApplySmartDeFiHardFork(statedb, api.backend.ChainConfig())
+			}
 			// Clean out any pending release functions of trace state. Note this
 			// step must be done after constructing tracing state, because the
 			// tracing state of block next depends on the parent state and construction
@@ -545,6 +549,9 @@ func (api *API) IntermediateRoots(ctx context.Context, hash common.Hash, config
 	if chainConfig.IsPrague(block.Number(), block.Time()) {
 		core.ProcessParentBlockHash(block.ParentHash(), evm)
 	}
+	if chainConfig.IsSmartDeFiTransition(block.Number(), parent.Time(), block.Time()) {
+		misc.ApplySmartDeFiHardFork(statedb, chainConfig)
+	}
 	for i, tx := range block.Transactions() {
 		if err := ctx.Err(); err != nil {
 			return nil, err
@@ -609,6 +616,9 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 	if api.backend.ChainConfig().IsPrague(block.Number(), block.Time()) {
 		core.ProcessParentBlockHash(block.ParentHash(), evm)
 	}
+	if api.backend.ChainConfig().IsSmartDeFiTransition(block.Number(), parent.Time(), block.Time()) {
+		misc.ApplySmartDeFiHardFork(statedb, api.backend.ChainConfig())
+	}
 
 	// JS tracers have high overhead. In this case run a parallel
 	// process that generates states in one thread and traces txes
@@ -787,6 +797,9 @@ func (api *API) standardTraceBlockToFile(ctx context.Context, block *types.Block
 	if chainConfig.IsPrague(block.Number(), block.Time()) {
 		core.ProcessParentBlockHash(block.ParentHash(), evm)
 	}
+	if chainConfig.IsSmartDeFiTransition(block.Number(), parent.Time(), block.Time()) {
+		misc.ApplySmartDeFiHardFork(statedb, chainConfig)
+	}
 	for i, tx := range block.Transactions() {
 		// Prepare the transaction for un-traced execution
 		msg, _ := core.TransactionToMessage(tx, signer, block.BaseFee())
@@ -0,0 +1,241 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracetest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+)
+
+type backingPoolChanges struct {
+	TotalBacking      *hexutil.Big `json:"totalBacking"`
+	CirculatingSupply *hexutil.Big `json:"circulatingSupply"`
+	FloorPrice        *hexutil.Big `json:"floorPrice"`
+}
+
+type backingPoolInfo struct {
+	Token             common.Address      `json:"token"`
+	TotalBacking      *hexutil.Big        `json:"totalBacking"`
+	CirculatingSupply *hexutil.Big        `json:"circulatingSupply"`
+	FloorPrice        *hexutil.Big        `json:"floorPrice"`
+	Changes           *backingPoolChanges `json:"changes"`
+}
+
+type backingInfo struct {
+	Pools []backingPoolInfo `json:"pools,omitempty"`
+
+	// Block info
+	Number     uint64      `json:"blockNumber"`
+	Hash       common.Hash `json:"hash"`
+	ParentHash common.Hash `json:"parentHash"`
+}
+
+func hexBig(x int64) *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(x))
+}
+
+// Tests that the backing tracer reports every registered pool in each block,
+// with their totals at the end of the block and the changes over it.
+func TestBackingTracer(t *testing.T) {
+	gspec, gen, token := smartDeFiBackingChain(t)
+
+	// The third block leaves the pool untouched
+	out, chain, err := testBackingTracer(t, gspec, func(b *core.BlockGen) {
+		if b.Number().Uint64() <= 2 {
+			gen(b)
+		}
+	}, 3)
+	if err != nil {
+		t.Fatalf("failed to test backing tracer: %v", err)
+	}
+	if len(out) != 4 {
+		t.Fatalf("expected 4 blocks, got %d", len(out))
+	}
+	// Nothing is backed at genesis
+	compareAsJSON(t, backingInfo{Hash: chain.Genesis().Hash()}, out[0])
+
+	block1 := chain.GetBlockByNumber(1)
+	compareAsJSON(t, backingInfo{
+		Pools: []backingPoolInfo{{
+			Token:             token,
+			TotalBacking:      hexBig(1000),
+			CirculatingSupply: hexBig(1_000_000),
+			FloorPrice:        hexBig(1e15),
+			Changes: &backingPoolChanges{
+				TotalBacking:      hexBig(1000),
+				CirculatingSupply: hexBig(1_000_000),
+				FloorPrice:        hexBig(1e15),
+			},
+		}},
+		Number:     1,
+		Hash:       block1.Hash(),
+		ParentHash: block1.ParentHash(),
+	}, out[1])
+
+	// Burning half of the supply recovers half of the backing, keeping the floor
+	block2 := chain.GetBlockByNumber(2)
+	compareAsJSON(t, backingInfo{
+		Pools: []backingPoolInfo{{
+			Token:             token,
+			TotalBacking:      hexBig(500),
+			CirculatingSupply: hexBig(500_000),
+			FloorPrice:        hexBig(1e15),
+			Changes: &backingPoolChanges{
+				TotalBacking:      hexBig(-500),
+				CirculatingSupply: hexBig(-500_000),
+				FloorPrice:        hexBig(0),
+			},
+		}},
+		Number:     2,
+		Hash:       block2.Hash(),
+		ParentHash: block2.ParentHash(),
+	}, out[2])
+
+	// Unchanged pools are reported as well
+	block3 := chain.GetBlockByNumber(3)
+	compareAsJSON(t, backingInfo{
+		Pools: []backingPoolInfo{{
+			Token:             token,
+			TotalBacking:      hexBig(500),
+			CirculatingSupply: hexBig(500_000),
+			FloorPrice:        hexBig(1e15),
+			Changes: &backingPoolChanges{
+				TotalBacking:      hexBig(0),
+				CirculatingSupply: hexBig(0),
+				FloorPrice:        hexBig(0),
+			},
+		}},
+		Number:     3,
+		Hash:       block3.Hash(),
+		ParentHash: block3.ParentHash(),
+	}, out[3])
+}
+
+// Tests that the backing tracer reads the snapshot of all registered pools from
+// the state, when it starts on a block not building on one traced before.
+func TestBackingTracerSnapshot(t *testing.T) {
+	token := common.HexToAddress("0x70ce")
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	backingpool.SetBackingPool(statedb, &backingpool.BackingPool{
+		TokenAddress:   token,
+		TotalBacking:   big.NewInt(1000),
+		TotalSupply:    big.NewInt(1_000_000),
+		BurnedSupply:   big.NewInt(0),
+		BackingAssets:  []common.Address{{}},
+		BackingAmounts: []*big.Int{big.NewInt(1000)},
+	})
+	statedb.SetState(assetbacking.PrecompileAddressBytes, assetbacking.TokenCountSlot(), common.BigToHash(common.Big1))
+	statedb.SetState(assetbacking.PrecompileAddressBytes, assetbacking.TokenAtSlot(0), common.BytesToHash(token.Bytes()))
+
+	traceOutputPath := filepath.ToSlash(t.TempDir())
+	hooks, err := tracers.LiveDirectory.New("backing", json.RawMessage(fmt.Sprintf(`{"path":"%s"}`, traceOutputPath)))
+	if err != nil {
+		t.Fatalf("failed to create backing tracer: %v", err)
+	}
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(10), ParentHash: common.HexToHash("0x01")})
+	hooks.OnBlockStart(tracing.BlockEvent{Block: block})
+	hooks.OnSystemCallStartV2(&tracing.VMContext{StateDB: statedb})
+	hooks.OnBlockEnd(nil)
+	hooks.OnClose()
+
+	data, err := os.ReadFile(path.Join(traceOutputPath, "backing.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	compareAsJSON(t, backingInfo{
+		Pools: []backingPoolInfo{{
+			Token:             token,
+			TotalBacking:      hexBig(1000),
+			CirculatingSupply: hexBig(1_000_000),
+			FloorPrice:        hexBig(1e15),
+			Changes: &backingPoolChanges{
+				TotalBacking:      hexBig(0),
+				CirculatingSupply: hexBig(0),
+				FloorPrice:        hexBig(0),
+			},
+		}},
+		Number:     10,
+		Hash:       block.Hash(),
+		ParentHash: block.ParentHash(),
+	}, json.RawMessage(bytes.TrimSpace(data)))
+}
+
+// testBackingTracer returns the raw output of the backing tracer, as negative
+// changes do not decode into hexutil.Big.
+func testBackingTracer(t *testing.T, genesis *core.Genesis, gen func(b *core.BlockGen), numBlocks int) ([]json.RawMessage, *core.BlockChain, error) {
+	engine := beacon.New(ethash.NewFaker())
+
+	traceOutputPath := filepath.ToSlash(t.TempDir())
+	traceOutputFilename := path.Join(traceOutputPath, "backing.jsonl")
+
+	// Load backing tracer
+	tracer, err := tracers.LiveDirectory.New("backing", json.RawMessage(fmt.Sprintf(`{"path":"%s"}`, traceOutputPath)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create backing tracer: %v", err)
+	}
+
+	options := core.DefaultConfig().WithStateScheme(rawdb.PathScheme)
+	options.VmConfig = vm.Config{Tracer: tracer}
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), genesis, engine, options)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	_, blocks, _ := core.GenerateChainWithGenesis(genesis, engine, numBlocks, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(common.Address{1})
+		gen(b)
+	})
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		return nil, chain, fmt.Errorf("block %d: failed to insert into chain: %v", n, err)
+	}
+
+	file, err := os.OpenFile(traceOutputFilename, os.O_RDONLY, 0666)
+	if err != nil {
+		return nil, chain, fmt.Errorf("failed to open output file: %v", err)
+	}
+	defer file.Close()
+
+	var output []json.RawMessage
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		output = append(output, bytes.Clone(scanner.Bytes()))
+	}
+	return output, chain, nil
+}
@@ -33,8 +33,10 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/params"
@@ -55,9 +57,15 @@ type supplyInfoBurn struct {
 	Misc    *hexutil.Big `json:"misc,omitempty"`
 }
 
+type supplyInfoBacking struct {
+	Locked    *hexutil.Big `json:"locked,omitempty"`
+	Recovered *hexutil.Big `json:"recovered,omitempty"`
+}
+
 type supplyInfo struct {
 	Issuance *supplyInfoIssuance `json:"issuance,omitempty"`
 	Burn     *supplyInfoBurn     `json:"burn,omitempty"`
+	Backing  *supplyInfoBacking  `json:"backing,omitempty"`
 
 	// Block info
 	Number     uint64      `json:"blockNumber"`
@@ -590,6 +598,150 @@ func TestSupplySelfdestructItselfAndRevert(t *testing.T) {
 	compareAsJSON(t, expected, actual)
 }
 
+// Tests that a system call following the last transaction of a block, like the
+// request processing of Prague, does not count the burn of that transaction a
+// second time. System calls are not preceded by a transaction start.
+func TestSupplySystemCallAfterTx(t *testing.T) {
+	var (
+		config = *params.MergedTestChainConfig
+
+		aa   = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		bb   = common.HexToAddress("0x2222222222222222222222222222222222222222")
+		eth1 = new(big.Int).Mul(common.Big1, big.NewInt(params.Ether))
+	)
+	traceOutputPath := filepath.ToSlash(t.TempDir())
+	hooks, err := tracers.LiveDirectory.New("supply", json.RawMessage(fmt.Sprintf(`{"path":"%s"}`, traceOutputPath)))
+	if err != nil {
+		t.Fatalf("failed to create supply tracer: %v", err)
+	}
+	hooks.OnBlockchainInit(&config)
+	hooks.OnBlockStart(tracing.BlockEvent{Block: types.NewBlockWithHeader(&types.Header{Number: common.Big1})})
+
+	// A transaction in which B selfdestructs, burning its balance
+	hooks.OnTxStart(nil, nil, aa)
+	hooks.OnEnter(0, byte(vm.CALL), aa, bb, nil, 100000, common.Big0)
+	hooks.OnEnter(1, byte(vm.SELFDESTRUCT), bb, bb, nil, 0, eth1)
+	hooks.OnExit(1, nil, 0, nil, false)
+	hooks.OnExit(0, nil, 50000, nil, false)
+
+	// A system call at the end of the block
+	hooks.OnEnter(0, byte(vm.CALL), params.SystemAddress, params.WithdrawalQueueAddress, nil, 30000000, common.Big0)
+	hooks.OnExit(0, nil, 10000, nil, false)
+
+	hooks.OnBlockEnd(nil)
+	hooks.OnClose()
+
+	data, err := os.ReadFile(path.Join(traceOutputPath, "supply.jsonl"))
+	if err != nil {
+		t.Fatalf("failed to read output file: %v", err)
+	}
+	var actual supplyInfo
+	if err := json.Unmarshal(data, &actual); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+	compareAsJSON(t, &supplyInfoBurn{Misc: (*hexutil.Big)(eth1)}, actual.Burn)
+}
+
+// Tests that Smart coin locked in and recovered from the SmartDeFi backing is
+// reported apart from the issuance and burn, and that locks within reverted
+// calls are dropped.
+func TestSupplySmartDeFiBacking(t *testing.T) {
+	gspec, gen, _ := smartDeFiBackingChain(t)
+
+	out, _, err := testSupplyTracer(t, gspec, gen, 2)
+	if err != nil {
+		t.Fatalf("failed to test supply tracer: %v", err)
+	}
+	compareAsJSON(t, &supplyInfoBacking{Locked: (*hexutil.Big)(big.NewInt(1000))}, out[1].Backing)
+	compareAsJSON(t, &supplyInfoBacking{Recovered: (*hexutil.Big)(big.NewInt(500))}, out[2].Backing)
+}
+
+// smartDeFiBackingChain returns a genesis with SmartDeFi active and a generator
+// for two blocks on top of it. The first block creates a token with a supply of
+// 1e6 backed by 1000 wei, along with a creation that reverts, and the second
+// burns half of the supply to recover its backing. The token is also returned.
+func smartDeFiBackingChain(t *testing.T) (*core.Genesis, func(b *core.BlockGen), common.Address) {
+	var (
+		config = *params.MergedTestChainConfig
+
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		eth1    = new(big.Int).Mul(common.Big1, big.NewInt(params.Ether))
+		gwei5   = new(big.Int).Mul(big.NewInt(5), big.NewInt(params.GWei))
+		supply  = big.NewInt(1_000_000)
+		backing = big.NewInt(1000)
+
+		gspec = &core.Genesis{
+			Config:  &config,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+			Alloc: types.GenesisAlloc{
+				addr1: {Balance: eth1},
+			},
+		}
+	)
+	config.SmartDeFiTime = new(uint64)
+	config.OsakaTime = nil
+	signer := types.LatestSigner(gspec.Config)
+
+	tokenConfig := func(initialBacking *big.Int) assetbacking.TokenConfig {
+		fees := [12]*big.Int{}
+		for i := range fees {
+			fees[i] = new(big.Int)
+		}
+		return assetbacking.TokenConfig{
+			Name:           "Backed",
+			Symbol:         "BKD",
+			TotalSupply:    supply,
+			InitialBacking: initialBacking,
+			Fees:           fees,
+			Owner:          addr1,
+		}
+	}
+	createInput := func(initialBacking *big.Int) []byte {
+		input, err := assetbacking.EncodeCreateToken(tokenConfig(initialBacking))
+		if err != nil {
+			t.Fatalf("failed to encode input: %v", err)
+		}
+		return input
+	}
+	// The token is the first created by addr1, which salts its address
+	token, err := assetbacking.TokenAddress(addr1, assetbacking.DefaultTokenSalt(0), tokenConfig(backing))
+	if err != nil {
+		t.Fatalf("failed to derive token address: %v", err)
+	}
+	burnInput, err := assetbacking.EncodeBurnAndRecover(token, new(big.Int).Div(supply, common.Big2))
+	if err != nil {
+		t.Fatalf("failed to encode input: %v", err)
+	}
+	precompile := assetbacking.PrecompileAddressBytes
+
+	gen := func(b *core.BlockGen) {
+		b.SetPoS()
+
+		send := func(value *big.Int, input []byte) {
+			tx, _ := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+				ChainID:   gspec.Config.ChainID,
+				Nonce:     b.TxNonce(addr1),
+				To:        &precompile,
+				Value:     value,
+				Gas:       1_000_000,
+				GasFeeCap: gwei5,
+				GasTipCap: big.NewInt(2),
+				Data:      input,
+			}), signer, key1)
+			b.AddTx(tx)
+		}
+		if b.Number().Uint64() == 1 {
+			send(backing, createInput(backing))
+			// The value does not match the backing, so the call reverts
+			send(big.NewInt(5), createInput(big.NewInt(7)))
+		} else {
+			send(new(big.Int), burnInput)
+		}
+	}
+	return gspec, gen, token
+}
+
 func testSupplyTracer(t *testing.T, genesis *core.Genesis, gen func(b *core.BlockGen), numBlocks int) ([]supplyInfo, *core.BlockChain, error) {
 	engine := beacon.New(ethash.NewFaker())
 
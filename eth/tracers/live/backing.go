@@ -0,0 +1,318 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package live
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/log"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+func init() {
+	tracers.LiveDirectory.Register("backing", newBackingTracer)
+}
+
+// backingPoolFields are the pool fields tracked by the backing tracer, by the
+// slot they occupy in every token account.
+var backingPoolFields = map[common.Hash]int{
+	backingpool.PoolSlot(backingpool.SlotTotalBacking): backingpool.SlotTotalBacking,
+	backingpool.PoolSlot(backingpool.SlotTotalSupply):  backingpool.SlotTotalSupply,
+	backingpool.PoolSlot(backingpool.SlotBurnedSupply): backingpool.SlotBurnedSupply,
+}
+
+type backingPoolChanges struct {
+	TotalBacking      *hexutil.Big `json:"totalBacking"`
+	CirculatingSupply *hexutil.Big `json:"circulatingSupply"`
+	FloorPrice        *hexutil.Big `json:"floorPrice"`
+}
+
+type backingPoolInfo struct {
+	Token             common.Address      `json:"token"`
+	TotalBacking      *hexutil.Big        `json:"totalBacking"`
+	CirculatingSupply *hexutil.Big        `json:"circulatingSupply"`
+	FloorPrice        *hexutil.Big        `json:"floorPrice"`
+	Changes           *backingPoolChanges `json:"changes"`
+}
+
+type backingInfo struct {
+	Pools []backingPoolInfo `json:"pools,omitempty"`
+
+	// Block info
+	Number     uint64      `json:"blockNumber"`
+	Hash       common.Hash `json:"hash"`
+	ParentHash common.Hash `json:"parentHash"`
+}
+
+// backingPoolStart holds the values the tracked fields of a pool had at the
+// start of the block, for the fields changed in the block.
+type backingPoolStart map[int]common.Hash
+
+// backingTracer records, for every block, a snapshot of all SmartDeFi backing
+// pools in the token registry: their backing, circulating supply and floor
+// price at the end of the block, and how much these changed over the block.
+//
+// The registry is read in full once, from the state of the first block traced,
+// and kept up to date from the pools changed in each block. Pool fields live at
+// the same namespaced slots in every token account, so the changed pools are
+// found through their storage changes. Their values at the end of the block are
+// read from the state, which also accounts for reverted calls. The snapshot is
+// read again after a block not building on the previous one.
+type backingTracer struct {
+	info   backingInfo
+	env    *tracing.VMContext
+	start  map[common.Address]backingPoolStart // Pools changed in the current block
+	end    map[common.Address]*backingpool.BackingPool
+	logger *lumberjack.Logger
+
+	pools map[common.Address]*backingpool.BackingPool // Pools at the end of head, nil if unknown
+	head  common.Hash                                 // Last block traced
+}
+
+type backingTracerConfig struct {
+	Path    string `json:"path"`    // Path to the directory where the tracer logs will be stored
+	MaxSize int    `json:"maxSize"` // MaxSize is the maximum size in megabytes of the tracer log file before it gets rotated. It defaults to 100 megabytes.
+}
+
+func newBackingTracer(cfg json.RawMessage) (*tracing.Hooks, error) {
+	var config backingTracerConfig
+	if err := json.Unmarshal(cfg, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %v", err)
+	}
+	if config.Path == "" {
+		return nil, errors.New("backing tracer output path is required")
+	}
+
+	// Store traces in a rotating file
+	logger := &lumberjack.Logger{
+		Filename: filepath.Join(config.Path, "backing.jsonl"),
+	}
+	if config.MaxSize > 0 {
+		logger.MaxSize = config.MaxSize
+	}
+
+	t := &backingTracer{
+		logger: logger,
+	}
+	t.reset()
+	return &tracing.Hooks{
+		OnBlockStart:        t.onBlockStart,
+		OnBlockEnd:          t.onBlockEnd,
+		OnGenesisBlock:      t.onGenesisBlock,
+		OnTxStart:           t.onTxStart,
+		OnTxEnd:             t.onTxEnd,
+		OnSystemCallStartV2: t.onSystemCallStart,
+		OnStorageChange:     t.onStorageChange,
+		OnClose:             t.onClose,
+	}, nil
+}
+
+func (t *backingTracer) reset() {
+	t.info = backingInfo{}
+	t.env = nil
+	t.start = make(map[common.Address]backingPoolStart)
+	t.end = make(map[common.Address]*backingpool.BackingPool)
+}
+
+func (t *backingTracer) onBlockStart(ev tracing.BlockEvent) {
+	t.reset()
+	if ev.Block.ParentHash() != t.head {
+		t.pools = nil
+	}
+
+	t.info.Number = ev.Block.NumberU64()
+	t.info.Hash = ev.Block.Hash()
+	t.info.ParentHash = ev.Block.ParentHash()
+}
+
+func (t *backingTracer) onBlockEnd(err error) {
+	// A failed block leaves no state behind
+	if err != nil {
+		return
+	}
+	pools := make(map[common.Address]*backingpool.BackingPool, len(t.pools)+len(t.end))
+	for token, pool := range t.pools {
+		pools[token] = pool
+	}
+	for token, pool := range t.end {
+		if pool != nil {
+			pools[token] = pool
+		}
+	}
+	tokens := make([]common.Address, 0, len(pools))
+	for token := range pools {
+		tokens = append(tokens, token)
+	}
+	slices.SortFunc(tokens, func(a, b common.Address) int { return bytes.Compare(a[:], b[:]) })
+
+	for _, token := range tokens {
+		t.info.Pools = append(t.info.Pools, newBackingPoolInfo(pools[token], t.start[token]))
+	}
+	t.write(t.info)
+
+	// A block executing no transaction nor system call leaves the pools unknown
+	// if they were before, as there is no state to read them from
+	if t.pools != nil || len(t.end) > 0 {
+		t.pools = pools
+	}
+	t.head = t.info.Hash
+}
+
+func (t *backingTracer) onGenesisBlock(b *types.Block, alloc types.GenesisAlloc) {
+	t.onBlockStart(tracing.BlockEvent{Block: b})
+
+	// Pools allocated in genesis change from nothing
+	for addr, account := range alloc {
+		if !assetbacking.IsToken(account.Code) {
+			continue
+		}
+		start := make(backingPoolStart)
+		for _, field := range backingPoolFields {
+			start[field] = common.Hash{}
+		}
+		t.start[addr] = start
+		t.end[addr] = backingpool.GetBackingPool(genesisStorage(alloc), addr)
+	}
+	t.pools = make(map[common.Address]*backingpool.BackingPool)
+	t.onBlockEnd(nil)
+}
+
+func (t *backingTracer) onTxStart(env *tracing.VMContext, tx *types.Transaction, from common.Address) {
+	t.setEnv(env)
+}
+
+func (t *backingTracer) onSystemCallStart(env *tracing.VMContext) {
+	t.setEnv(env)
+}
+
+// setEnv sets the environment of the current transaction or system call. The
+// first one of a block runs before any change to the pools, so the snapshot is
+// read from it if it is not known.
+func (t *backingTracer) setEnv(env *tracing.VMContext) {
+	t.env = env
+	if t.pools != nil {
+		return
+	}
+	t.pools = make(map[common.Address]*backingpool.BackingPool)
+	for i, count := uint64(0), assetbacking.TokenCount(env.StateDB); i < count; i++ {
+		token := assetbacking.TokenAt(env.StateDB, i)
+		if pool := backingpool.GetBackingPool(env.StateDB, token); pool != nil {
+			t.pools[token] = pool
+		}
+	}
+}
+
+func (t *backingTracer) onTxEnd(receipt *types.Receipt, err error) {
+	if t.env == nil {
+		return
+	}
+	// Read the pools changed so far, as the state stays in use after the last
+	// transaction of the block
+	for token := range t.start {
+		if !assetbacking.IsToken(t.env.StateDB.GetCode(token)) {
+			continue
+		}
+		t.end[token] = backingpool.GetBackingPool(t.env.StateDB, token)
+	}
+}
+
+func (t *backingTracer) onStorageChange(addr common.Address, slot common.Hash, prev, new common.Hash) {
+	field, ok := backingPoolFields[slot]
+	if !ok {
+		return
+	}
+	start := t.start[addr]
+	if start == nil {
+		start = make(backingPoolStart)
+		t.start[addr] = start
+	}
+	if _, ok := start[field]; !ok {
+		start[field] = prev
+	}
+}
+
+func (t *backingTracer) onClose() {
+	if err := t.logger.Close(); err != nil {
+		log.Warn("failed to close backing tracer log file", "error", err)
+	}
+}
+
+func (t *backingTracer) write(info backingInfo) {
+	out, _ := json.Marshal(info)
+	if _, err := t.logger.Write(out); err != nil {
+		log.Warn("failed to write to backing tracer log file", "error", err)
+	}
+	if _, err := t.logger.Write([]byte{'\n'}); err != nil {
+		log.Warn("failed to write to backing tracer log file", "error", err)
+	}
+}
+
+// newBackingPoolInfo reports a pool at the end of the block, along with its
+// changes since the values of start.
+func newBackingPoolInfo(pool *backingpool.BackingPool, start backingPoolStart) backingPoolInfo {
+	// Fields not changed in the block kept their value
+	before := &backingpool.BackingPool{
+		TotalBacking: pool.TotalBacking,
+		TotalSupply:  pool.TotalSupply,
+		BurnedSupply: pool.BurnedSupply,
+	}
+	if value, ok := start[backingpool.SlotTotalBacking]; ok {
+		before.TotalBacking = value.Big()
+	}
+	if value, ok := start[backingpool.SlotTotalSupply]; ok {
+		before.TotalSupply = value.Big()
+	}
+	if value, ok := start[backingpool.SlotBurnedSupply]; ok {
+		before.BurnedSupply = value.Big()
+	}
+	var (
+		circulating       = new(big.Int).Sub(pool.TotalSupply, pool.BurnedSupply)
+		circulatingBefore = new(big.Int).Sub(before.TotalSupply, before.BurnedSupply)
+		floorPrice        = pool.CalculateFloorPrice()
+	)
+	return backingPoolInfo{
+		Token:             pool.TokenAddress,
+		TotalBacking:      (*hexutil.Big)(pool.TotalBacking),
+		CirculatingSupply: (*hexutil.Big)(circulating),
+		FloorPrice:        (*hexutil.Big)(floorPrice),
+		Changes: &backingPoolChanges{
+			TotalBacking:      (*hexutil.Big)(new(big.Int).Sub(pool.TotalBacking, before.TotalBacking)),
+			CirculatingSupply: (*hexutil.Big)(new(big.Int).Sub(circulating, circulatingBefore)),
+			FloorPrice:        (*hexutil.Big)(new(big.Int).Sub(floorPrice, before.CalculateFloorPrice())),
+		},
+	}
+}
+
+// genesisStorage reads the storage of a genesis allocation.
+type genesisStorage types.GenesisAlloc
+
+func (g genesisStorage) GetState(addr common.Address, slot common.Hash) common.Hash {
+	return g[addr].Storage[slot]
+}
@@ -65,9 +65,23 @@ type supplyInfoBurnMarshaling struct {
 	Misc    *hexutil.Big
 }
 
+// supplyInfoBacking tracks the Smart coin moving into and out of the backing
+// held by the SmartDeFi asset-backing precompile. The coins remain in supply.
+type supplyInfoBacking struct {
+	Locked    *big.Int `json:"locked,omitempty"`
+	Recovered *big.Int `json:"recovered,omitempty"`
+}
+
+//go:generate go run github.com/fjl/gencodec -type supplyInfoBacking -field-override supplyInfoBackingMarshaling -out gen_supplyinfobacking.go
+type supplyInfoBackingMarshaling struct {
+	Locked    *hexutil.Big
+	Recovered *hexutil.Big
+}
+
 type supplyInfo struct {
 	Issuance *supplyInfoIssuance `json:"issuance,omitempty"`
 	Burn     *supplyInfoBurn     `json:"burn,omitempty"`
+	Backing  *supplyInfoBacking  `json:"backing,omitempty"`
 
 	// Block info
 	Number     uint64      `json:"blockNumber"`
@@ -76,8 +90,9 @@ type supplyInfo struct {
 }
 
 type supplyTxCallstack struct {
-	calls []supplyTxCallstack
-	burn  *big.Int
+	calls   []supplyTxCallstack
+	burn    *big.Int
+	backing *supplyInfoBacking
 }
 
 type supplyTracer struct {
@@ -138,6 +153,7 @@ func newSupplyInfo() supplyInfo {
 			Blob:    big.NewInt(0),
 			Misc:    big.NewInt(0),
 		},
+		Backing: newSupplyInfoBacking(),
 
 		Number:     0,
 		Hash:       common.Hash{},
@@ -194,9 +210,34 @@ func (s *supplyTracer) onGenesisBlock(b *types.Block, alloc types.GenesisAlloc)
 	s.write(s.delta)
 }
 
+func newSupplyInfoBacking() *supplyInfoBacking {
+	return &supplyInfoBacking{
+		Locked:    big.NewInt(0),
+		Recovered: big.NewInt(0),
+	}
+}
+
 func (s *supplyTracer) onBalanceChange(a common.Address, prevBalance, newBalance *big.Int, reason tracing.BalanceChangeReason) {
 	diff := new(big.Int).Sub(newBalance, prevBalance)
 
+	// Backing movements are reported on both accounts involved, so only the
+	// credited side is counted. They happen within calls and are dropped
+	// together with reverted ones.
+	var backing *big.Int
+	if len(s.txCallstack) > 0 && diff.Sign() > 0 {
+		call := &s.txCallstack[len(s.txCallstack)-1]
+		switch reason {
+		case tracing.BalanceChangeBackingLocked:
+			backing = call.backing.Locked
+		case tracing.BalanceChangeBackingRecovered:
+			backing = call.backing.Recovered
+		}
+	}
+	if backing != nil {
+		backing.Add(backing, diff)
+		return
+	}
+
 	// NOTE: don't handle "BalanceIncreaseGenesisBalance" because it is handled in OnGenesisBlock
 	switch reason {
 	case tracing.BalanceIncreaseRewardMineBlock, tracing.BalanceIncreaseRewardMineUncle:
@@ -222,6 +263,9 @@ func (s *supplyTracer) internalTxsHandler(call *supplyTxCallstack) {
 	if call.burn != nil {
 		s.delta.Burn.Misc.Add(s.delta.Burn.Misc, call.burn)
 	}
+	// Handle backing movements
+	s.delta.Backing.Locked.Add(s.delta.Backing.Locked, call.backing.Locked)
+	s.delta.Backing.Recovered.Add(s.delta.Backing.Recovered, call.backing.Recovered)
 
 	// Recursively handle internal calls
 	for _, call := range call.calls {
@@ -232,7 +276,8 @@ func (s *supplyTracer) internalTxsHandler(call *supplyTxCallstack) {
 
 func (s *supplyTracer) onEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
 	call := supplyTxCallstack{
-		calls: make([]supplyTxCallstack, 0),
+		calls:   make([]supplyTxCallstack, 0),
+		backing: newSupplyInfoBacking(),
 	}
 
 	// This is a special case of burned amount which has to be handled here
@@ -251,6 +296,9 @@ func (s *supplyTracer) onExit(depth int, output []byte, gasUsed uint64, err erro
 		if !reverted {
 			s.internalTxsHandler(&s.txCallstack[0])
 		}
+		// System calls are not preceded by a transaction start, so drop the
+		// finished call to not handle it again with the next one
+		s.txCallstack = s.txCallstack[:0]
 		return
 	}
 
@@ -317,6 +365,18 @@ func (s *supplyTracer) write(data any) {
 		supply.Burn = nil
 	}
 
+	if supply.Backing.Locked.Sign() == 0 {
+		supply.Backing.Locked = nil
+	}
+
+	if supply.Backing.Recovered.Sign() == 0 {
+		supply.Backing.Recovered = nil
+	}
+
+	if supply.Backing.Locked == nil && supply.Backing.Recovered == nil {
+		supply.Backing = nil
+	}
+
 	out, _ := json.Marshal(supply)
 	if _, err := s.logger.Write(out); err != nil {
 		log.Warn("failed to write to supply tracer log file", "error", err)
@@ -101,9 +101,10 @@ func newPrestateTracer(ctx *tracers.Context, cfg json.RawMessage, chainConfig *p
 	}
 	return &tracers.Tracer{
 		Hooks: &tracing.Hooks{
-			OnTxStart: t.OnTxStart,
-			OnTxEnd:   t.OnTxEnd,
-			OnOpcode:  t.OnOpcode,
+			OnTxStart:       t.OnTxStart,
+			OnTxEnd:         t.OnTxEnd,
+			OnOpcode:        t.OnOpcode,
+			OnBalanceChange: t.OnBalanceChange,
 		},
 		GetResult: t.GetResult,
 		Stop:      t.Stop,
@@ -172,6 +173,28 @@ func (t *prestateTracer) OnOpcode(pc uint64, opcode byte, gas, cost uint64, scop
 	}
 }
 
+// OnBalanceChange adds the accounts whose Smart coin the SmartDeFi asset-backing
+// precompile locks or releases to the prestate. The precompile moves balances
+// without executing any opcode, and the change has already been applied when
+// the hook runs, so the balance is taken from the hook.
+func (t *prestateTracer) OnBalanceChange(addr common.Address, prevBalance, newBalance *big.Int, reason tracing.BalanceChangeReason) {
+	if t.interrupt.Load() {
+		return
+	}
+	switch reason {
+	case tracing.BalanceChangeBackingLocked, tracing.BalanceChangeBackingRecovered:
+	default:
+		return
+	}
+	if _, ok := t.pre[addr]; ok {
+		return
+	}
+	t.lookupAccount(addr)
+	acc := t.pre[addr]
+	acc.Balance = new(big.Int).Set(prevBalance)
+	acc.empty = !acc.exists()
+}
+
 func (t *prestateTracer) OnTxStart(env *tracing.VMContext, tx *types.Transaction, from common.Address) {
 	t.env = env
 	if tx.To() == nil {
@@ -0,0 +1,250 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethclient
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// SmartDeFiPool is the backing pool of a SmartDeFi token.
+type SmartDeFiPool struct {
+	Token             common.Address
+	Name              string
+	Symbol            string
+	Owner             common.Address
+	BackingAsset      common.Address
+	TotalBacking      *big.Int
+	TotalSupply       *big.Int
+	BurnedSupply      *big.Int
+	CirculatingSupply *big.Int
+	FloorPrice        *big.Int // Wei of backing per 1e18 token units
+	BackingAssets     []common.Address
+	BackingAmounts    []*big.Int
+}
+
+// SmartDeFiFeeSide holds the fees applying to one side of a trade of a SmartDeFi
+// token, in units of 1/1000 of the transferred amount.
+type SmartDeFiFeeSide struct {
+	Backing   *big.Int
+	Liquidity *big.Int
+	Treasury  *big.Int
+}
+
+// SmartDeFiFees is the fee schedule of a SmartDeFi token.
+type SmartDeFiFees struct {
+	Buy    SmartDeFiFeeSide
+	Sell   SmartDeFiFeeSide
+	OnlySB bool
+}
+
+// SmartDeFiTokenList is a page of the created SmartDeFi tokens.
+type SmartDeFiTokenList struct {
+	Tokens []common.Address
+	Next   *uint64 // Cursor of the next page, nil after the last token
+}
+
+// SmartDeFiCheckpoint is the backing pool of a SmartDeFi token at the end of a
+// block in which it changed.
+type SmartDeFiCheckpoint struct {
+	BlockNumber       uint64
+	BlockHash         common.Hash
+	TotalBacking      *big.Int
+	CirculatingSupply *big.Int
+	FloorPrice        *big.Int // Wei of backing per 1e18 token units
+}
+
+// SmartDeFiFloorPriceHistory is a page of the checkpoints of a SmartDeFi token.
+type SmartDeFiFloorPriceHistory struct {
+	Checkpoints []SmartDeFiCheckpoint
+	Next        *uint64 // Block to continue from, nil after the last checkpoint in range
+}
+
+// SmartDeFiPoolAt returns the backing pool of a SmartDeFi token. The block number
+// can be nil, in which case the pool is taken from the latest known block.
+// ethereum.NotFound is returned if the address is not a SmartDeFi token.
+func (ec *Client) SmartDeFiPoolAt(ctx context.Context, token common.Address, blockNumber *big.Int) (*SmartDeFiPool, error) {
+	type rpcPool struct {
+		Token             common.Address   `json:"token"`
+		Name              string           `json:"name"`
+		Symbol            string           `json:"symbol"`
+		Owner             common.Address   `json:"owner"`
+		BackingAsset      common.Address   `json:"backingAsset"`
+		TotalBacking      *hexutil.Big     `json:"totalBacking"`
+		TotalSupply       *hexutil.Big     `json:"totalSupply"`
+		BurnedSupply      *hexutil.Big     `json:"burnedSupply"`
+		CirculatingSupply *hexutil.Big     `json:"circulatingSupply"`
+		FloorPrice        *hexutil.Big     `json:"floorPrice"`
+		BackingAssets     []common.Address `json:"backingAssets"`
+		BackingAmounts    []*hexutil.Big   `json:"backingAmounts"`
+	}
+	var res *rpcPool
+	if err := ec.c.CallContext(ctx, &res, "smartdefi_getPool", token, toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, ethereum.NotFound
+	}
+	amounts := make([]*big.Int, len(res.BackingAmounts))
+	for i, amount := range res.BackingAmounts {
+		amounts[i] = (*big.Int)(amount)
+	}
+	return &SmartDeFiPool{
+		Token:             res.Token,
+		Name:              res.Name,
+		Symbol:            res.Symbol,
+		Owner:             res.Owner,
+		BackingAsset:      res.BackingAsset,
+		TotalBacking:      (*big.Int)(res.TotalBacking),
+		TotalSupply:       (*big.Int)(res.TotalSupply),
+		BurnedSupply:      (*big.Int)(res.BurnedSupply),
+		CirculatingSupply: (*big.Int)(res.CirculatingSupply),
+		FloorPrice:        (*big.Int)(res.FloorPrice),
+		BackingAssets:     res.BackingAssets,
+		BackingAmounts:    amounts,
+	}, nil
+}
+
+// SmartDeFiFloorPriceAt returns the floor price of a SmartDeFi token, in wei of
+// backing per 1e18 token units. The block number can be nil, in which case the
+// price is taken from the latest known block.
+func (ec *Client) SmartDeFiFloorPriceAt(ctx context.Context, token common.Address, blockNumber *big.Int) (*big.Int, error) {
+	var res *hexutil.Big
+	if err := ec.c.CallContext(ctx, &res, "smartdefi_getFloorPrice", token, toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, ethereum.NotFound
+	}
+	return (*big.Int)(res), nil
+}
+
+// SmartDeFiBackingForAmountAt returns the backing recovered by burning amount of
+// a SmartDeFi token. The block number can be nil, in which case the backing is
+// taken from the latest known block.
+func (ec *Client) SmartDeFiBackingForAmountAt(ctx context.Context, token common.Address, amount *big.Int, blockNumber *big.Int) (*big.Int, error) {
+	var res *hexutil.Big
+	if err := ec.c.CallContext(ctx, &res, "smartdefi_getBackingForAmount", token, (*hexutil.Big)(amount), toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, ethereum.NotFound
+	}
+	return (*big.Int)(res), nil
+}
+
+// SmartDeFiFeesAt returns the fee schedule of a SmartDeFi token. The block number
+// can be nil, in which case the fees are taken from the latest known block.
+func (ec *Client) SmartDeFiFeesAt(ctx context.Context, token common.Address, blockNumber *big.Int) (*SmartDeFiFees, error) {
+	type rpcFeeSide struct {
+		Backing   *hexutil.Big `json:"backing"`
+		Liquidity *hexutil.Big `json:"liquidity"`
+		Treasury  *hexutil.Big `json:"treasury"`
+	}
+	type rpcFees struct {
+		Buy    rpcFeeSide `json:"buy"`
+		Sell   rpcFeeSide `json:"sell"`
+		OnlySB bool       `json:"onlySB"`
+	}
+	var res *rpcFees
+	if err := ec.c.CallContext(ctx, &res, "smartdefi_getFees", token, toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, ethereum.NotFound
+	}
+	side := func(s rpcFeeSide) SmartDeFiFeeSide {
+		return SmartDeFiFeeSide{
+			Backing:   (*big.Int)(s.Backing),
+			Liquidity: (*big.Int)(s.Liquidity),
+			Treasury:  (*big.Int)(s.Treasury),
+		}
+	}
+	return &SmartDeFiFees{
+		Buy:    side(res.Buy),
+		Sell:   side(res.Sell),
+		OnlySB: res.OnlySB,
+	}, nil
+}
+
+// SmartDeFiTokensAt returns up to limit created SmartDeFi tokens in order of
+// creation, starting at the index cursor. The server returns at most 1000
+// tokens per call. The block number can be nil, in which case the tokens are
+// taken from the latest known block.
+func (ec *Client) SmartDeFiTokensAt(ctx context.Context, cursor uint64, limit uint64, blockNumber *big.Int) (*SmartDeFiTokenList, error) {
+	var res struct {
+		Tokens []common.Address `json:"tokens"`
+		Next   *hexutil.Uint64  `json:"next"`
+	}
+	if err := ec.c.CallContext(ctx, &res, "smartdefi_listTokens", hexutil.Uint64(cursor), hexutil.Uint64(limit), toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	list := &SmartDeFiTokenList{Tokens: res.Tokens}
+	if res.Next != nil {
+		next := uint64(*res.Next)
+		list.Next = &next
+	}
+	return list, nil
+}
+
+// SmartDeFiFloorPriceHistory returns the checkpoints of a SmartDeFi token in the
+// blocks between fromBlock and toBlock, both inclusive. A nil fromBlock starts
+// at the earliest indexed block and a nil toBlock ends at the latest block. The
+// server returns at most 1000 checkpoints per call and requires the floor-price
+// index to be enabled.
+func (ec *Client) SmartDeFiFloorPriceHistory(ctx context.Context, token common.Address, fromBlock, toBlock *big.Int) (*SmartDeFiFloorPriceHistory, error) {
+	type rpcCheckpoint struct {
+		BlockNumber       hexutil.Uint64 `json:"blockNumber"`
+		BlockHash         common.Hash    `json:"blockHash"`
+		TotalBacking      *hexutil.Big   `json:"totalBacking"`
+		CirculatingSupply *hexutil.Big   `json:"circulatingSupply"`
+		FloorPrice        *hexutil.Big   `json:"floorPrice"`
+	}
+	var res struct {
+		Checkpoints []rpcCheckpoint `json:"checkpoints"`
+		Next        *hexutil.Uint64 `json:"next"`
+	}
+	var from, to interface{}
+	if fromBlock != nil {
+		from = toBlockNumArg(fromBlock)
+	}
+	if toBlock != nil {
+		to = toBlockNumArg(toBlock)
+	}
+	if err := ec.c.CallContext(ctx, &res, "smartdefi_getFloorPriceHistory", token, from, to); err != nil {
+		return nil, err
+	}
+	history := &SmartDeFiFloorPriceHistory{Checkpoints: make([]SmartDeFiCheckpoint, len(res.Checkpoints))}
+	for i, checkpoint := range res.Checkpoints {
+		history.Checkpoints[i] = SmartDeFiCheckpoint{
+			BlockNumber:       uint64(checkpoint.BlockNumber),
+			BlockHash:         checkpoint.BlockHash,
+			TotalBacking:      (*big.Int)(checkpoint.TotalBacking),
+			CirculatingSupply: (*big.Int)(checkpoint.CirculatingSupply),
+			FloorPrice:        (*big.Int)(checkpoint.FloorPrice),
+		}
+	}
+	if res.Next != nil {
+		next := uint64(*res.Next)
+		history.Next = &next
+	}
+	return history, nil
+}
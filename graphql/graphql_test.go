@@ -34,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
@@ -430,6 +431,72 @@ func TestWithdrawals(t *testing.T) {
 	}
 }
 
+// TestGraphQLSmartTokens tests that SmartDeFi tokens and their backing pools
+// are resolved from the state of the queried block.
+func TestGraphQLSmartTokens(t *testing.T) {
+	var (
+		owner  = common.HexToAddress("0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e")
+		config = *params.AllEthashProtocolChanges
+	)
+	config.SmartDeFiTime = new(uint64)
+	genesis := &core.Genesis{
+		Config:     &config,
+		GasLimit:   11500000,
+		Difficulty: common.Big1,
+		SmartDeFi: []assetbacking.GenesisToken{
+			{Name: "Backed", Symbol: "BKD", Owner: owner, Supply: big.NewInt(1_000_000), Backing: big.NewInt(1000),
+				Fees: [12]uint64{10, 20, 30, 0, 0, 0, 40, 50, 60, 0, 0, 0}},
+			{Name: "Unbacked", Symbol: "UBK", Owner: owner, Supply: big.NewInt(500), OnlySB: true},
+		},
+	}
+	stack := createNode(t)
+	defer stack.Close()
+	handler, _ := newGQLService(t, stack, false, genesis, 1, func(i int, gen *core.BlockGen) {})
+	// start node
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+
+	for i, tt := range []struct {
+		body string
+		want string
+	}{
+		{
+			body: "{smartTokens(first: 1) { tokens { name symbol owner floorPrice } totalCount endCursor hasNextPage } }",
+			want: `{"smartTokens":{"tokens":[{"name":"Backed","symbol":"BKD","owner":"0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e","floorPrice":"0x38d7ea4c68000"}],"totalCount":"0x2","endCursor":"0x0","hasNextPage":true}}`,
+		},
+		{
+			body: "{smartTokens(after: 0, block: 0) { tokens { symbol fees { buy { backing liquidity treasury } sell { backing } onlySB } } endCursor hasNextPage } }",
+			want: `{"smartTokens":{"tokens":[{"symbol":"UBK","fees":{"buy":{"backing":"0x0","liquidity":"0x0","treasury":"0x0"},"sell":{"backing":"0x0"},"onlySB":true}}],"endCursor":"0x1","hasNextPage":false}}`,
+		},
+		{
+			body: "{smartTokens(first: 1) { tokens { fees { buy { backing liquidity treasury } sell { backing liquidity treasury } } } } }",
+			want: `{"smartTokens":{"tokens":[{"fees":{"buy":{"backing":"0xa","liquidity":"0x14","treasury":"0x1e"},"sell":{"backing":"0x28","liquidity":"0x32","treasury":"0x3c"}}}]}}`,
+		},
+		{
+			body: "{smartTokens(first: 1) { tokens { account { backingPool { totalBacking totalSupply burnedSupply circulatingSupply backingAmounts backingForAmount(amount: \"0x3e8\") } } } } }",
+			want: `{"smartTokens":{"tokens":[{"account":{"backingPool":{"totalBacking":"0x3e8","totalSupply":"0xf4240","burnedSupply":"0x0","circulatingSupply":"0xf4240","backingAmounts":["0x3e8"],"backingForAmount":"0x1"}}}]}}`,
+		},
+		// Accounts which are not tokens have neither token nor pool.
+		{
+			body: `{block(number: 1) { account(address: "0x0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e") { smartToken { name } backingPool { floorPrice } } } }`,
+			want: `{"block":{"account":{"smartToken":null,"backingPool":null}}}`,
+		},
+	} {
+		res := handler.Schema.Exec(context.Background(), tt.body, "", map[string]interface{}{})
+		if res.Errors != nil {
+			t.Fatalf("failed to execute query for testcase #%d: %v", i, res.Errors)
+		}
+		have, err := json.Marshal(res.Data)
+		if err != nil {
+			t.Fatalf("failed to encode graphql response for testcase #%d: %s", i, err)
+		}
+		if string(have) != tt.want {
+			t.Errorf("response unmatch for testcase #%d.\nhave:\n%s\nwant:\n%s", i, have, tt.want)
+		}
+	}
+}
+
 // TestGraphQLMaxDepth ensures that queries exceeding the configured maximum depth
 // are rejected to prevent resource exhaustion from deeply nested operations.
 func TestGraphQLMaxDepth(t *testing.T) {
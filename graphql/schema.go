@@ -54,6 +54,99 @@ const schema string = `
         # Storage provides access to the storage of a contract account, indexed
         # by its 32 byte slot identifier.
         storage(slot: Bytes32!): Bytes32!
+        # SmartToken is the SmartDeFi token at this address, or null if the
+        # account is not a token created by the asset-backing precompile.
+        smartToken: SmartToken
+        # BackingPool is the backing pool of the SmartDeFi token at this
+        # address, or null if the account is not a token.
+        backingPool: BackingPool
+    }
+
+    # SmartToken is a token created by the SmartDeFi asset-backing precompile,
+    # at a particular block.
+    type SmartToken {
+        # Address is the address of the token.
+        address: Address!
+        # Name is the name of the token.
+        name: String!
+        # Symbol is the ticker symbol of the token.
+        symbol: String!
+        # Owner is the owner of the token, or the zero address once ownership
+        # has been renounced.
+        owner: Address!
+        # BalanceOf is the token balance of the given holder.
+        balanceOf(holder: Address!): BigInt!
+        # Account is the account of the token contract.
+        account: Account!
+        # BackingPool is the pool holding the backing of the token.
+        backingPool: BackingPool!
+        # FloorPrice is the backing per 1e18 base units of circulating supply,
+        # in wei.
+        floorPrice: BigInt!
+        # Fees is the fee schedule of the token.
+        fees: SmartTokenFees!
+    }
+
+    # BackingPool is the backing pool of a SmartDeFi token at a particular block.
+    type BackingPool {
+        # Token is the token backed by the pool.
+        token: SmartToken!
+        # BackingAsset is the primary asset backing the token.
+        backingAsset: Address!
+        # TotalBacking is the backing held by the pool, in wei.
+        totalBacking: BigInt!
+        # TotalSupply is the number of tokens ever minted.
+        totalSupply: BigInt!
+        # BurnedSupply is the number of tokens burned to recover backing.
+        burnedSupply: BigInt!
+        # CirculatingSupply is the total supply less the burned supply.
+        circulatingSupply: BigInt!
+        # FloorPrice is the backing per 1e18 base units of circulating supply,
+        # in wei.
+        floorPrice: BigInt!
+        # BackingForAmount is the backing recovered by burning the given amount
+        # of tokens.
+        backingForAmount(amount: BigInt!): BigInt!
+        # BackingAssets lists the assets backing the token.
+        backingAssets: [Address!]!
+        # BackingAmounts lists the amount held of each backing asset, in the
+        # order of backingAssets.
+        backingAmounts: [BigInt!]!
+    }
+
+    # SmartTokenFees is the fee schedule of a SmartDeFi token.
+    type SmartTokenFees {
+        # Buy is the fees charged on buys.
+        buy: SmartTokenFeeSide!
+        # Sell is the fees charged on sells.
+        sell: SmartTokenFeeSide!
+        # OnlySB is true if the token can only be traded through the Smart coin
+        # path, and not through third-party contracts.
+        onlySB: Boolean!
+    }
+
+    # SmartTokenFeeSide is the fees charged on one side of the trades of a
+    # SmartDeFi token, in thousandths of the transferred amount.
+    type SmartTokenFeeSide {
+        # Backing is the fee added to the backing pool.
+        backing: BigInt!
+        # Liquidity is the fee added to liquidity.
+        liquidity: BigInt!
+        # Treasury is the fee paid to the treasury.
+        treasury: BigInt!
+    }
+
+    # SmartTokenList is a page of the SmartDeFi tokens, in order of creation.
+    type SmartTokenList {
+        # Tokens is the tokens of the page.
+        tokens: [SmartToken!]!
+        # TotalCount is the number of tokens created up to the queried block.
+        totalCount: Long!
+        # EndCursor is the cursor of the last token of the page, to pass as
+        # after to fetch the next page. It is null if the page is empty.
+        endCursor: Long
+        # HasNextPage is true if more tokens follow this page.
+        hasNextPage: Boolean!
     }
 
     # Log is an Ethereum event log.
@@ -382,6 +475,10 @@ const schema string = `
         syncing: SyncState
         # ChainID returns the current chain ID for transaction replay protection.
         chainID: BigInt!
+        # SmartTokens returns up to first SmartDeFi tokens created after the
+        # cursor after, in order of creation, at the given block. First defaults
+        # to and is capped at 1000, the block defaults to the most recent one.
+        smartTokens(first: Long, after: Long, block: Long): SmartTokenList!
     }
 
     type Mutation {
@@ -0,0 +1,329 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxSmartTokens is the maximum number of tokens returned by one smartTokens
+// query.
+const maxSmartTokens = 1000
+
+// tokenState is the state SmartDeFi tokens are read from, resolved once and
+// shared by the tokens queried at the same block.
+type tokenState struct {
+	r             *Resolver
+	blockNrOrHash rpc.BlockNumberOrHash
+	mu            sync.Mutex
+	// mu protects following resources
+	state *state.StateDB
+}
+
+// read calls fn with the StateDB object, fetching it on first use. Fields are
+// resolved concurrently and the StateDB is not safe for concurrent use, so fn
+// runs under the lock.
+func (s *tokenState) read(ctx context.Context, fn func(state *state.StateDB)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == nil {
+		state, _, err := s.r.backend.StateAndHeaderByNumberOrHash(ctx, s.blockNrOrHash)
+		if err != nil {
+			return err
+		}
+		s.state = state
+	}
+	fn(s.state)
+	return nil
+}
+
+// SmartToken represents a token created by the SmartDeFi asset-backing
+// precompile, at a particular block.
+type SmartToken struct {
+	address common.Address
+	state   *tokenState
+}
+
+// newSmartToken returns the token at address in the given state, or nil if the
+// account is not a SmartDeFi token.
+func newSmartToken(r *Resolver, state *state.StateDB, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) *SmartToken {
+	if !assetbacking.IsToken(state.GetCode(address)) {
+		return nil
+	}
+	return &SmartToken{address: address, state: &tokenState{r: r, blockNrOrHash: blockNrOrHash, state: state}}
+}
+
+func (t *SmartToken) Address(ctx context.Context) (common.Address, error) {
+	return t.address, nil
+}
+
+func (t *SmartToken) Name(ctx context.Context) (string, error) {
+	var name string
+	err := t.state.read(ctx, func(state *state.StateDB) {
+		name = assetbacking.TokenName(state, t.address)
+	})
+	return name, err
+}
+
+func (t *SmartToken) Symbol(ctx context.Context) (string, error) {
+	var symbol string
+	err := t.state.read(ctx, func(state *state.StateDB) {
+		symbol = assetbacking.TokenSymbol(state, t.address)
+	})
+	return symbol, err
+}
+
+func (t *SmartToken) Owner(ctx context.Context) (common.Address, error) {
+	var owner common.Address
+	err := t.state.read(ctx, func(state *state.StateDB) {
+		owner = assetbacking.TokenOwner(state, t.address)
+	})
+	return owner, err
+}
+
+func (t *SmartToken) BalanceOf(ctx context.Context, args struct{ Holder common.Address }) (hexutil.Big, error) {
+	var balance *big.Int
+	err := t.state.read(ctx, func(state *state.StateDB) {
+		balance = assetbacking.TokenBalance(state, t.address, args.Holder)
+	})
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	return hexutil.Big(*balance), nil
+}
+
+func (t *SmartToken) Account(ctx context.Context) *Account {
+	return &Account{
+		r:             t.state.r,
+		address:       t.address,
+		blockNrOrHash: t.state.blockNrOrHash,
+	}
+}
+
+func (t *SmartToken) BackingPool(ctx context.Context) (*BackingPool, error) {
+	var pool *backingpool.BackingPool
+	err := t.state.read(ctx, func(state *state.StateDB) {
+		pool = backingpool.GetBackingPool(state, t.address)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if pool == nil {
+		return nil, fmt.Errorf("failed to load backing pool %x", t.address)
+	}
+	return &BackingPool{token: t, pool: pool}, nil
+}
+
+func (t *SmartToken) FloorPrice(ctx context.Context) (hexutil.Big, error) {
+	pool, err := t.BackingPool(ctx)
+	if err != nil {
+		return hexutil.Big{}, err
+	}
+	return pool.FloorPrice(ctx), nil
+}
+
+func (t *SmartToken) Fees(ctx context.Context) (*SmartTokenFees, error) {
+	fees := new(SmartTokenFees)
+	err := t.state.read(ctx, func(state *state.StateDB) {
+		fees.fees, fees.onlySB = assetbacking.TokenFees(state, t.address)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fees, nil
+}
+
+// BackingPool represents the backing pool of a SmartDeFi token, as read from
+// the state of the block the token was queried at.
+type BackingPool struct {
+	token *SmartToken
+	pool  *backingpool.BackingPool
+}
+
+func (p *BackingPool) Token(ctx context.Context) *SmartToken {
+	return p.token
+}
+
+func (p *BackingPool) BackingAsset(ctx context.Context) common.Address {
+	return p.pool.BackingAsset
+}
+
+func (p *BackingPool) TotalBacking(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*p.pool.TotalBacking)
+}
+
+func (p *BackingPool) TotalSupply(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*p.pool.TotalSupply)
+}
+
+func (p *BackingPool) BurnedSupply(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*p.pool.BurnedSupply)
+}
+
+func (p *BackingPool) CirculatingSupply(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*new(big.Int).Sub(p.pool.TotalSupply, p.pool.BurnedSupply))
+}
+
+func (p *BackingPool) FloorPrice(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*p.pool.CalculateFloorPrice())
+}
+
+func (p *BackingPool) BackingForAmount(ctx context.Context, args struct{ Amount hexutil.Big }) hexutil.Big {
+	return hexutil.Big(*p.pool.CalculateBackingForAmount(args.Amount.ToInt()))
+}
+
+func (p *BackingPool) BackingAssets(ctx context.Context) []common.Address {
+	return p.pool.BackingAssets
+}
+
+func (p *BackingPool) BackingAmounts(ctx context.Context) []hexutil.Big {
+	amounts := make([]hexutil.Big, len(p.pool.BackingAmounts))
+	for i, amount := range p.pool.BackingAmounts {
+		amounts[i] = hexutil.Big(*amount)
+	}
+	return amounts
+}
+
+// SmartTokenFees represents the fee schedule of a SmartDeFi token.
+type SmartTokenFees struct {
+	fees   [12]*big.Int
+	onlySB bool
+}
+
+func (f *SmartTokenFees) Buy(ctx context.Context) *SmartTokenFeeSide {
+	return &SmartTokenFeeSide{fees: f.fees[:assetbacking.FeeSellOffset]}
+}
+
+func (f *SmartTokenFees) Sell(ctx context.Context) *SmartTokenFeeSide {
+	return &SmartTokenFeeSide{fees: f.fees[assetbacking.FeeSellOffset:]}
+}
+
+func (f *SmartTokenFees) OnlySB(ctx context.Context) bool {
+	return f.onlySB
+}
+
+// SmartTokenFeeSide represents the fees charged on one side of the trades of a
+// SmartDeFi token, in thousandths of the transferred amount.
+type SmartTokenFeeSide struct {
+	fees []*big.Int
+}
+
+func (s *SmartTokenFeeSide) Backing(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*s.fees[assetbacking.FeeBacking])
+}
+
+func (s *SmartTokenFeeSide) Liquidity(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*s.fees[assetbacking.FeeLiquidity])
+}
+
+func (s *SmartTokenFeeSide) Treasury(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*s.fees[assetbacking.FeeTreasury])
+}
+
+// SmartTokenList represents a page of the SmartDeFi tokens, in order of
+// creation.
+type SmartTokenList struct {
+	tokens      []*SmartToken
+	totalCount  uint64
+	endCursor   *hexutil.Uint64
+	hasNextPage bool
+}
+
+func (l *SmartTokenList) Tokens(ctx context.Context) []*SmartToken {
+	return l.tokens
+}
+
+func (l *SmartTokenList) TotalCount(ctx context.Context) hexutil.Uint64 {
+	return hexutil.Uint64(l.totalCount)
+}
+
+func (l *SmartTokenList) EndCursor(ctx context.Context) *hexutil.Uint64 {
+	return l.endCursor
+}
+
+func (l *SmartTokenList) HasNextPage(ctx context.Context) bool {
+	return l.hasNextPage
+}
+
+func (a *Account) SmartToken(ctx context.Context) (*SmartToken, error) {
+	state, err := a.getState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newSmartToken(a.r, state, a.address, a.blockNrOrHash), nil
+}
+
+func (a *Account) BackingPool(ctx context.Context) (*BackingPool, error) {
+	token, err := a.SmartToken(ctx)
+	if token == nil || err != nil {
+		return nil, err
+	}
+	return token.BackingPool(ctx)
+}
+
+// SmartTokens returns up to first tokens created by the SmartDeFi precompile
+// after the cursor after, in order of creation. The cursor of a token is its
+// index in the token registry. If first is not supplied or above 1000, 1000
+// tokens are returned; the block defaults to the latest one.
+func (r *Resolver) SmartTokens(ctx context.Context, args struct {
+	First *Long
+	After *Long
+	Block *Long
+}) (*SmartTokenList, error) {
+	blockNrOrHash := BlockNumberArgs{Block: args.Block}.NumberOrLatest()
+	state, _, err := r.backend.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	limit := uint64(maxSmartTokens)
+	if args.First != nil {
+		if *args.First < 0 {
+			return nil, errors.New("first must not be negative")
+		}
+		limit = min(limit, uint64(*args.First))
+	}
+	var start uint64
+	if args.After != nil && *args.After >= 0 {
+		start = uint64(*args.After) + 1
+	}
+	list := &SmartTokenList{
+		tokens:     []*SmartToken{},
+		totalCount: assetbacking.TokenCount(state),
+	}
+	// The tokens share the state the registry was read from
+	shared := &tokenState{r: r, blockNrOrHash: blockNrOrHash, state: state}
+	for i := start; i < list.totalCount && uint64(len(list.tokens)) < limit; i++ {
+		token := assetbacking.TokenAt(state, i)
+		list.tokens = append(list.tokens, &SmartToken{address: token, state: shared})
+
+		cursor := hexutil.Uint64(i)
+		list.endCursor = &cursor
+	}
+	list.hasNextPage = start+uint64(len(list.tokens)) < list.totalCount
+	return list, nil
+}
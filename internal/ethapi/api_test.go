@@ -49,6 +49,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -458,6 +459,7 @@ func fakeBlockHash(txh common.Hash) common.Hash {
 func newTestBackend(t *testing.T, n int, gspec *core.Genesis, engine consensus.Engine, generator func(i int, b *core.BlockGen)) *testBackend {
 	options := core.DefaultConfig().WithArchive(true)
 	options.TxLookupLimit = 0 // index all txs
+	options.FloorPriceIndex = true
 
 	accman, acc := newTestAccountManager(t)
 	gspec.Alloc[acc.Address] = types.Account{Balance: big.NewInt(params.Ether)}
@@ -991,6 +993,40 @@ func TestEstimateGas(t *testing.T) {
 	}
 }
 
+// TestNewRevertError checks that revert errors decode both Solidity revert
+// reasons and the custom errors of the SmartDeFi precompile.
+func TestNewRevertError(t *testing.T) {
+	precompileABI, err := abi.JSON(strings.NewReader(assetbacking.PrecompileABI))
+	if err != nil {
+		t.Fatalf("failed to parse precompile ABI: %v", err)
+	}
+	token := common.HexToAddress("0x9999999999999999999999999999999999999999")
+	poolNotFound := precompileABI.Errors["PoolNotFound"]
+	packed, err := poolNotFound.Inputs.Pack(token)
+	if err != nil {
+		t.Fatalf("failed to pack error: %v", err)
+	}
+	reasonString, _ := (abi.Arguments{{Type: abi.Type{T: abi.StringTy}}}).Pack("insufficient funds")
+
+	for i, tc := range []struct {
+		revert []byte
+		want   string
+	}{
+		{append(crypto.Keccak256([]byte("Error(string)"))[:4], reasonString...), "execution reverted: insufficient funds"},
+		{append(poolNotFound.ID[:4], packed...), "execution reverted: PoolNotFound(" + token.Hex() + ")"},
+		{[]byte{0xde, 0xad, 0xbe, 0xef}, "execution reverted"},
+		{nil, "execution reverted"},
+	} {
+		err := newRevertError(tc.revert)
+		if err.Error() != tc.want {
+			t.Errorf("test %d: error mismatch, want %q, have %q", i, tc.want, err.Error())
+		}
+		if have := err.ErrorData(); have != hexutil.Encode(tc.revert) {
+			t.Errorf("test %d: data mismatch, want %v, have %v", i, hexutil.Encode(tc.revert), have)
+		}
+	}
+}
+
 func TestCall(t *testing.T) {
 	t.Parallel()
 
@@ -124,6 +124,9 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 		}, {
 			Namespace: "eth",
 			Service:   NewEthereumAccountAPI(apiBackend.AccountManager()),
+		}, {
+			Namespace: "smartdefi",
+			Service:   NewSmartDeFiAPI(apiBackend),
 		},
 	}
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
 )
 
 // revertError is an API error that encompasses an EVM revert with JSON error
@@ -55,6 +56,11 @@ func newRevertError(revert []byte) *revertError {
 	err := vm.ErrExecutionReverted
 
 	reason, errUnpack := abi.UnpackRevert(revert)
+	if errUnpack != nil {
+		// Not a Solidity revert reason, try the custom errors of the
+		// SmartDeFi precompile
+		reason, errUnpack = assetbacking.UnpackRevert(revert)
+	}
 	if errUnpack == nil {
 		err = fmt.Errorf("%w: %v", vm.ErrExecutionReverted, reason)
 	}
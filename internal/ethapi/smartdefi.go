@@ -0,0 +1,297 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/history"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxSmartDeFiTokens is the maximum number of tokens returned by one call to
+// smartdefi_listTokens.
+const maxSmartDeFiTokens = 1000
+
+// maxSmartDeFiCheckpoints is the maximum number of checkpoints returned by one
+// call to smartdefi_getFloorPriceHistory.
+const maxSmartDeFiCheckpoints = 1000
+
+var (
+	errFloorPriceIndexDisabled = errors.New("floor-price index is not enabled")
+	errInvalidBlockRange       = errors.New("invalid block range")
+)
+
+// SmartDeFiAPI provides an API to inspect the tokens created by the SmartDeFi
+// asset-backing precompile and their backing pools.
+type SmartDeFiAPI struct {
+	b Backend
+}
+
+// NewSmartDeFiAPI creates a new SmartDeFi API.
+func NewSmartDeFiAPI(b Backend) *SmartDeFiAPI {
+	return &SmartDeFiAPI{b}
+}
+
+// SmartDeFiPool is the backing pool of a token, as returned by
+// smartdefi_getPool.
+type SmartDeFiPool struct {
+	Token             common.Address   `json:"token"`
+	Name              string           `json:"name"`
+	Symbol            string           `json:"symbol"`
+	Owner             common.Address   `json:"owner"`
+	BackingAsset      common.Address   `json:"backingAsset"`
+	TotalBacking      *hexutil.Big     `json:"totalBacking"`
+	TotalSupply       *hexutil.Big     `json:"totalSupply"`
+	BurnedSupply      *hexutil.Big     `json:"burnedSupply"`
+	CirculatingSupply *hexutil.Big     `json:"circulatingSupply"`
+	FloorPrice        *hexutil.Big     `json:"floorPrice"`
+	BackingAssets     []common.Address `json:"backingAssets"`
+	BackingAmounts    []*hexutil.Big   `json:"backingAmounts"`
+}
+
+// SmartDeFiFeeSide holds the fees applying to one side of a trade, in units of
+// 1/1000 of the transferred amount.
+type SmartDeFiFeeSide struct {
+	Backing   *hexutil.Big `json:"backing"`
+	Liquidity *hexutil.Big `json:"liquidity"`
+	Treasury  *hexutil.Big `json:"treasury"`
+}
+
+// SmartDeFiFees is the fee schedule of a token, as returned by
+// smartdefi_getFees.
+type SmartDeFiFees struct {
+	Buy    SmartDeFiFeeSide `json:"buy"`
+	Sell   SmartDeFiFeeSide `json:"sell"`
+	OnlySB bool             `json:"onlySB"`
+}
+
+// SmartDeFiTokenList is a page of the created tokens, as returned by
+// smartdefi_listTokens.
+type SmartDeFiTokenList struct {
+	Tokens []common.Address `json:"tokens"`
+	Next   *hexutil.Uint64  `json:"next"` // Cursor of the next page, nil after the last token
+}
+
+// SmartDeFiCheckpoint is the backing pool of a token at the end of a block in
+// which it changed, as returned by smartdefi_getFloorPriceHistory.
+type SmartDeFiCheckpoint struct {
+	BlockNumber       hexutil.Uint64 `json:"blockNumber"`
+	BlockHash         common.Hash    `json:"blockHash"`
+	TotalBacking      *hexutil.Big   `json:"totalBacking"`
+	CirculatingSupply *hexutil.Big   `json:"circulatingSupply"`
+	FloorPrice        *hexutil.Big   `json:"floorPrice"`
+}
+
+// SmartDeFiFloorPriceHistory is a page of the checkpoints of a token, as
+// returned by smartdefi_getFloorPriceHistory.
+type SmartDeFiFloorPriceHistory struct {
+	Checkpoints []SmartDeFiCheckpoint `json:"checkpoints"`
+	Next        *hexutil.Uint64       `json:"next"` // Block to continue from, nil after the last checkpoint in range
+}
+
+// backingPool returns the state at the given block and the backing pool of token
+// in it. The pool is nil if the account is not a SmartDeFi token. The legacy
+// tokens of the chain config carry no token code, so they are recognized by
+// their address instead.
+func (api *SmartDeFiAPI) backingPool(ctx context.Context, token common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *backingpool.BackingPool, error) {
+	state, _, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, nil, err
+	}
+	if !assetbacking.IsToken(state.GetCode(token)) && !slices.Contains(api.b.ChainConfig().SmartDeFiLegacyTokens, token) {
+		return state, nil, state.Error()
+	}
+	return state, backingpool.GetBackingPool(state, token), state.Error()
+}
+
+// GetPool returns the backing pool of a token at the given block, or nil if the
+// address is not a SmartDeFi token.
+func (api *SmartDeFiAPI) GetPool(ctx context.Context, token common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*SmartDeFiPool, error) {
+	state, pool, err := api.backingPool(ctx, token, blockNrOrHash)
+	if pool == nil || err != nil {
+		return nil, err
+	}
+	amounts := make([]*hexutil.Big, len(pool.BackingAmounts))
+	for i, amount := range pool.BackingAmounts {
+		amounts[i] = (*hexutil.Big)(amount)
+	}
+	return &SmartDeFiPool{
+		Token:             token,
+		Name:              assetbacking.TokenName(state, token),
+		Symbol:            assetbacking.TokenSymbol(state, token),
+		Owner:             assetbacking.TokenOwner(state, token),
+		BackingAsset:      pool.BackingAsset,
+		TotalBacking:      (*hexutil.Big)(pool.TotalBacking),
+		TotalSupply:       (*hexutil.Big)(pool.TotalSupply),
+		BurnedSupply:      (*hexutil.Big)(pool.BurnedSupply),
+		CirculatingSupply: (*hexutil.Big)(new(big.Int).Sub(pool.TotalSupply, pool.BurnedSupply)),
+		FloorPrice:        (*hexutil.Big)(pool.CalculateFloorPrice()),
+		BackingAssets:     pool.BackingAssets,
+		BackingAmounts:    amounts,
+	}, state.Error()
+}
+
+// GetFloorPrice returns the floor price of a token at the given block, in wei of
+// backing per 1e18 token units, or nil if the address is not a SmartDeFi token.
+func (api *SmartDeFiAPI) GetFloorPrice(ctx context.Context, token common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	_, pool, err := api.backingPool(ctx, token, blockNrOrHash)
+	if pool == nil || err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(pool.CalculateFloorPrice()), nil
+}
+
+// GetBackingForAmount returns the backing recovered by burning amount of a token
+// at the given block, or nil if the address is not a SmartDeFi token.
+func (api *SmartDeFiAPI) GetBackingForAmount(ctx context.Context, token common.Address, amount hexutil.Big, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	_, pool, err := api.backingPool(ctx, token, blockNrOrHash)
+	if pool == nil || err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(pool.CalculateBackingForAmount(amount.ToInt())), nil
+}
+
+// GetFees returns the fee schedule of a token at the given block, or nil if the
+// address is not a SmartDeFi token.
+func (api *SmartDeFiAPI) GetFees(ctx context.Context, token common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*SmartDeFiFees, error) {
+	state, pool, err := api.backingPool(ctx, token, blockNrOrHash)
+	if pool == nil || err != nil {
+		return nil, err
+	}
+	fees, onlySB := assetbacking.TokenFees(state, token)
+	side := func(offset int) SmartDeFiFeeSide {
+		return SmartDeFiFeeSide{
+			Backing:   (*hexutil.Big)(fees[offset+assetbacking.FeeBacking]),
+			Liquidity: (*hexutil.Big)(fees[offset+assetbacking.FeeLiquidity]),
+			Treasury:  (*hexutil.Big)(fees[offset+assetbacking.FeeTreasury]),
+		}
+	}
+	return &SmartDeFiFees{
+		Buy:    side(0),
+		Sell:   side(assetbacking.FeeSellOffset),
+		OnlySB: onlySB,
+	}, state.Error()
+}
+
+// ListTokens returns up to limit created tokens in order of creation, starting
+// at the index cursor. A limit of zero or above 1000 returns 1000 tokens. The
+// block defaults to the latest one.
+func (api *SmartDeFiAPI) ListTokens(ctx context.Context, cursor hexutil.Uint64, limit hexutil.Uint64, blockNrOrHash *rpc.BlockNumberOrHash) (*SmartDeFiTokenList, error) {
+	if blockNrOrHash == nil {
+		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &latest
+	}
+	if limit == 0 || limit > maxSmartDeFiTokens {
+		limit = maxSmartDeFiTokens
+	}
+	state, _, err := api.b.StateAndHeaderByNumberOrHash(ctx, *blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	list := &SmartDeFiTokenList{Tokens: []common.Address{}}
+
+	count := assetbacking.TokenCount(state)
+	if uint64(cursor) >= count {
+		return list, state.Error()
+	}
+	end := uint64(cursor) + min(uint64(limit), count-uint64(cursor))
+	for i := uint64(cursor); i < end; i++ {
+		list.Tokens = append(list.Tokens, assetbacking.TokenAt(state, i))
+	}
+	if end < count {
+		next := hexutil.Uint64(end)
+		list.Next = &next
+	}
+	return list, state.Error()
+}
+
+// GetFloorPriceHistory returns the checkpoints of a token in the canonical blocks
+// between fromBlock and toBlock, both inclusive and defaulting to the earliest
+// indexed and the latest block. A checkpoint is recorded for every block in
+// which the pool of the token changed. At most 1000 checkpoints are returned
+// per call, the rest can be retrieved starting from the returned block.
+//
+// The history is only available if the floor-price index is enabled, for the
+// blocks retained by it.
+func (api *SmartDeFiAPI) GetFloorPriceHistory(ctx context.Context, token common.Address, fromBlock, toBlock *rpc.BlockNumber) (*SmartDeFiFloorPriceHistory, error) {
+	tail := rawdb.ReadFloorPriceIndexTail(api.b.ChainDb())
+	if tail == nil {
+		return nil, errFloorPriceIndexDisabled
+	}
+	resolve := func(number *rpc.BlockNumber, fallback uint64) (uint64, error) {
+		if number == nil {
+			return fallback, nil
+		}
+		if *number >= 0 {
+			return uint64(*number), nil
+		}
+		header, err := api.b.HeaderByNumber(ctx, *number)
+		if header == nil || err != nil {
+			return 0, err
+		}
+		return header.Number.Uint64(), nil
+	}
+	head := api.b.CurrentHeader().Number.Uint64()
+	from, err := resolve(fromBlock, *tail)
+	if err != nil {
+		return nil, err
+	}
+	to, err := resolve(toBlock, head)
+	if err != nil {
+		return nil, err
+	}
+	if from < *tail {
+		return nil, &history.PrunedHistoryError{}
+	}
+	if from > to {
+		return nil, errInvalidBlockRange
+	}
+	to = min(to, head)
+
+	result := &SmartDeFiFloorPriceHistory{Checkpoints: []SmartDeFiCheckpoint{}}
+	checkpoints := rawdb.ReadFloorPriceCheckpoints(api.b.ChainDb(), token, from, to, maxSmartDeFiCheckpoints+1)
+	if len(checkpoints) > maxSmartDeFiCheckpoints {
+		next := hexutil.Uint64(checkpoints[maxSmartDeFiCheckpoints].Number)
+		result.Next = &next
+		checkpoints = checkpoints[:maxSmartDeFiCheckpoints]
+	}
+	for _, checkpoint := range checkpoints {
+		pool := &backingpool.BackingPool{
+			TotalBacking: checkpoint.TotalBacking,
+			TotalSupply:  checkpoint.CirculatingSupply,
+			BurnedSupply: new(big.Int),
+		}
+		result.Checkpoints = append(result.Checkpoints, SmartDeFiCheckpoint{
+			BlockNumber:       hexutil.Uint64(checkpoint.Number),
+			BlockHash:         checkpoint.Hash,
+			TotalBacking:      (*hexutil.Big)(checkpoint.TotalBacking),
+			CirculatingSupply: (*hexutil.Big)(checkpoint.CirculatingSupply),
+			FloorPrice:        (*hexutil.Big)(pool.CalculateFloorPrice()),
+		})
+	}
+	return result, nil
+}
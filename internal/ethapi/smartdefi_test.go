@@ -0,0 +1,319 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state/backingpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm/precompiles/assetbacking"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// newSmartDeFiTestBackend returns a backend whose chain creates a token backed
+// by 1000 wei with a supply of 1e6 and an unbacked one in block 1, and burns
+// half of the supply of the first to recover its backing in block 2. The
+// creator and the tokens are also returned.
+func newSmartDeFiTestBackend(t *testing.T) (*testBackend, common.Address, []common.Address) {
+	var (
+		acc     = newTestAccount()
+		config  = *params.MergedTestChainConfig
+		genesis = &core.Genesis{
+			Config: &config,
+			Alloc: types.GenesisAlloc{
+				acc.addr: {Balance: big.NewInt(params.Ether)},
+			},
+		}
+		supply     = big.NewInt(1_000_000)
+		precompile = assetbacking.PrecompileAddressBytes
+	)
+	config.SmartDeFiTime = new(uint64)
+	config.OsakaTime = nil
+	signer := types.LatestSigner(genesis.Config)
+
+	fees := [12]*big.Int{}
+	for i := range fees {
+		fees[i] = new(big.Int)
+	}
+	fees[assetbacking.FeeBacking] = big.NewInt(10)
+	fees[assetbacking.FeeLiquidity] = big.NewInt(20)
+	fees[assetbacking.FeeTreasury] = big.NewInt(30)
+	fees[assetbacking.FeeSellOffset+assetbacking.FeeBacking] = big.NewInt(40)
+	fees[assetbacking.FeeSellOffset+assetbacking.FeeLiquidity] = big.NewInt(50)
+	fees[assetbacking.FeeSellOffset+assetbacking.FeeTreasury] = big.NewInt(60)
+
+	tokenConfig := func(name, symbol string, backing *big.Int) assetbacking.TokenConfig {
+		return assetbacking.TokenConfig{
+			Name:           name,
+			Symbol:         symbol,
+			TotalSupply:    supply,
+			InitialBacking: backing,
+			Fees:           fees,
+			Owner:          acc.addr,
+		}
+	}
+	// Token addresses are salted with the number of tokens created before
+	var tokens []common.Address
+	for i, config := range []assetbacking.TokenConfig{
+		tokenConfig("Backed", "BKD", big.NewInt(1000)),
+		tokenConfig("Unbacked", "UBK", new(big.Int)),
+	} {
+		token, err := assetbacking.TokenAddress(acc.addr, assetbacking.DefaultTokenSalt(uint64(i)), config)
+		if err != nil {
+			t.Fatalf("failed to derive token address: %v", err)
+		}
+		tokens = append(tokens, token)
+	}
+	backend := newTestBackend(t, 2, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) {
+		b.SetPoS()
+
+		send := func(value *big.Int, input []byte, err error) {
+			if err != nil {
+				t.Fatalf("failed to encode input: %v", err)
+			}
+			tx, _ := types.SignTx(types.NewTx(&types.DynamicFeeTx{
+				ChainID:   genesis.Config.ChainID,
+				Nonce:     b.TxNonce(acc.addr),
+				To:        &precompile,
+				Value:     value,
+				Gas:       1_000_000,
+				GasFeeCap: b.BaseFee(),
+				Data:      input,
+			}), signer, acc.key)
+			b.AddTx(tx)
+		}
+		create := func(name, symbol string, backing *big.Int) {
+			input, err := assetbacking.EncodeCreateToken(tokenConfig(name, symbol, backing))
+			send(backing, input, err)
+		}
+		switch i {
+		case 0:
+			create("Backed", "BKD", big.NewInt(1000))
+			create("Unbacked", "UBK", new(big.Int))
+		case 1:
+			input, err := assetbacking.EncodeBurnAndRecover(tokens[0], new(big.Int).Div(supply, big.NewInt(2)))
+			send(new(big.Int), input, err)
+		}
+	})
+	return backend, acc.addr, tokens
+}
+
+func TestSmartDeFiAPI(t *testing.T) {
+	t.Parallel()
+
+	var (
+		backend, owner, tokens = newSmartDeFiTestBackend(t)
+		api                    = NewSmartDeFiAPI(backend)
+		ctx                    = context.Background()
+		genesis                = rpc.BlockNumberOrHashWithNumber(0)
+		created                = rpc.BlockNumberOrHashWithNumber(1)
+		latest                 = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		token                  = tokens[0]
+	)
+	hexBig := func(x int64) *hexutil.Big {
+		return (*hexutil.Big)(big.NewInt(x))
+	}
+	expectJSON := func(name string, have, want interface{}) {
+		t.Helper()
+		haveJSON, _ := json.Marshal(have)
+		wantJSON, _ := json.Marshal(want)
+		if string(haveJSON) != string(wantJSON) {
+			t.Errorf("%s: have %s, want %s", name, haveJSON, wantJSON)
+		}
+	}
+
+	// Pools are read at the requested block
+	pool, err := api.GetPool(ctx, token, genesis)
+	if err != nil || pool != nil {
+		t.Errorf("expected no pool before creation, have %v (%v)", pool, err)
+	}
+	pool, err = api.GetPool(ctx, token, created)
+	if err != nil {
+		t.Fatalf("failed to get pool: %v", err)
+	}
+	expectJSON("pool at creation", pool, &SmartDeFiPool{
+		Token:             token,
+		Name:              "Backed",
+		Symbol:            "BKD",
+		Owner:             owner,
+		TotalBacking:      hexBig(1000),
+		TotalSupply:       hexBig(1_000_000),
+		BurnedSupply:      hexBig(0),
+		CirculatingSupply: hexBig(1_000_000),
+		FloorPrice:        hexBig(1e15),
+		BackingAssets:     []common.Address{{}},
+		BackingAmounts:    []*hexutil.Big{hexBig(1000)},
+	})
+	pool, err = api.GetPool(ctx, token, latest)
+	if err != nil {
+		t.Fatalf("failed to get pool: %v", err)
+	}
+	expectJSON("pool after burn", pool, &SmartDeFiPool{
+		Token:             token,
+		Name:              "Backed",
+		Symbol:            "BKD",
+		Owner:             owner,
+		TotalBacking:      hexBig(500),
+		TotalSupply:       hexBig(1_000_000),
+		BurnedSupply:      hexBig(500_000),
+		CirculatingSupply: hexBig(500_000),
+		FloorPrice:        hexBig(1e15),
+		BackingAssets:     []common.Address{{}},
+		BackingAmounts:    []*hexutil.Big{hexBig(500)},
+	})
+
+	price, err := api.GetFloorPrice(ctx, token, latest)
+	if err != nil {
+		t.Fatalf("failed to get floor price: %v", err)
+	}
+	expectJSON("floor price", price, hexBig(1e15))
+
+	backing, err := api.GetBackingForAmount(ctx, token, *hexBig(10_000), latest)
+	if err != nil {
+		t.Fatalf("failed to get backing: %v", err)
+	}
+	expectJSON("backing for amount", backing, hexBig(10))
+
+	fees, err := api.GetFees(ctx, token, latest)
+	if err != nil {
+		t.Fatalf("failed to get fees: %v", err)
+	}
+	expectJSON("fees", fees, &SmartDeFiFees{
+		Buy:  SmartDeFiFeeSide{Backing: hexBig(10), Liquidity: hexBig(20), Treasury: hexBig(30)},
+		Sell: SmartDeFiFeeSide{Backing: hexBig(40), Liquidity: hexBig(50), Treasury: hexBig(60)},
+	})
+
+	// Accounts other than tokens have no pool
+	for _, addr := range []common.Address{owner, assetbacking.PrecompileAddressBytes} {
+		if pool, err := api.GetPool(ctx, addr, latest); err != nil || pool != nil {
+			t.Errorf("expected no pool for %v, have %v (%v)", addr, pool, err)
+		}
+		if price, err := api.GetFloorPrice(ctx, addr, latest); err != nil || price != nil {
+			t.Errorf("expected no floor price for %v, have %v (%v)", addr, price, err)
+		}
+		if fees, err := api.GetFees(ctx, addr, latest); err != nil || fees != nil {
+			t.Errorf("expected no fees for %v, have %v (%v)", addr, fees, err)
+		}
+	}
+
+	// Tokens are listed in order of creation, in pages
+	next := hexutil.Uint64(1)
+	for _, test := range []struct {
+		cursor, limit hexutil.Uint64
+		block         *rpc.BlockNumberOrHash
+		want          *SmartDeFiTokenList
+	}{
+		{0, 0, nil, &SmartDeFiTokenList{Tokens: tokens}},
+		{0, 0, &genesis, &SmartDeFiTokenList{Tokens: []common.Address{}}},
+		{0, 1, &created, &SmartDeFiTokenList{Tokens: tokens[:1], Next: &next}},
+		{1, 1, nil, &SmartDeFiTokenList{Tokens: tokens[1:]}},
+		{2, 1, nil, &SmartDeFiTokenList{Tokens: []common.Address{}}},
+		{^hexutil.Uint64(0), 0, nil, &SmartDeFiTokenList{Tokens: []common.Address{}}},
+	} {
+		list, err := api.ListTokens(ctx, test.cursor, test.limit, test.block)
+		if err != nil {
+			t.Fatalf("failed to list tokens: %v", err)
+		}
+		expectJSON(fmt.Sprintf("tokens from %d, limit %d", test.cursor, test.limit), list, test.want)
+	}
+
+	// The floor-price history holds a checkpoint for every block changing a pool
+	blockNumber := func(n rpc.BlockNumber) *rpc.BlockNumber { return &n }
+	checkpoint := func(number uint64, backing, supply, price int64) SmartDeFiCheckpoint {
+		return SmartDeFiCheckpoint{
+			BlockNumber:       hexutil.Uint64(number),
+			BlockHash:         backend.chain.GetHeaderByNumber(number).Hash(),
+			TotalBacking:      hexBig(backing),
+			CirculatingSupply: hexBig(supply),
+			FloorPrice:        hexBig(price),
+		}
+	}
+	for _, test := range []struct {
+		token    common.Address
+		from, to *rpc.BlockNumber
+		want     []SmartDeFiCheckpoint
+	}{
+		{token, nil, nil, []SmartDeFiCheckpoint{checkpoint(1, 1000, 1_000_000, 1e15), checkpoint(2, 500, 500_000, 1e15)}},
+		{token, blockNumber(2), nil, []SmartDeFiCheckpoint{checkpoint(2, 500, 500_000, 1e15)}},
+		{token, nil, blockNumber(1), []SmartDeFiCheckpoint{checkpoint(1, 1000, 1_000_000, 1e15)}},
+		{token, blockNumber(rpc.LatestBlockNumber), nil, []SmartDeFiCheckpoint{checkpoint(2, 500, 500_000, 1e15)}},
+		{tokens[1], nil, nil, []SmartDeFiCheckpoint{checkpoint(1, 0, 1_000_000, 0)}},
+		{owner, nil, nil, []SmartDeFiCheckpoint{}},
+	} {
+		history, err := api.GetFloorPriceHistory(ctx, test.token, test.from, test.to)
+		if err != nil {
+			t.Fatalf("failed to get floor-price history: %v", err)
+		}
+		expectJSON(fmt.Sprintf("floor-price history of %v from %v to %v", test.token, test.from, test.to), history, &SmartDeFiFloorPriceHistory{Checkpoints: test.want})
+	}
+	if _, err := api.GetFloorPriceHistory(ctx, token, blockNumber(2), blockNumber(1)); err == nil {
+		t.Error("expected error for inverted block range")
+	}
+}
+
+// Tests that the pools of legacy tokens, which carry no token code, are served.
+func TestSmartDeFiAPILegacyToken(t *testing.T) {
+	t.Parallel()
+
+	var (
+		legacy  = common.HexToAddress("0x1e9ac7")
+		slots   = backingpool.LegacyPoolSlots(legacy, 0)
+		config  = *params.MergedTestChainConfig
+		genesis = &core.Genesis{
+			Config: &config,
+			Alloc: types.GenesisAlloc{
+				legacy: {
+					Balance: new(big.Int),
+					Storage: map[common.Hash]common.Hash{
+						slots[backingpool.SlotTotalBacking]: common.BigToHash(big.NewInt(500)),
+						slots[backingpool.SlotTotalSupply]:  common.BigToHash(big.NewInt(1000)),
+					},
+				},
+			},
+		}
+	)
+	config.SmartDeFiTime = new(uint64)
+	config.SmartDeFiLegacyTokens = []common.Address{legacy}
+	config.OsakaTime = nil
+
+	backend := newTestBackend(t, 1, genesis, beacon.New(ethash.NewFaker()), func(i int, b *core.BlockGen) { b.SetPoS() })
+	api := NewSmartDeFiAPI(backend)
+	latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+
+	pool, err := api.GetPool(context.Background(), legacy, latest)
+	if err != nil || pool == nil {
+		t.Fatalf("expected legacy pool, have %v (%v)", pool, err)
+	}
+	if pool.TotalBacking.ToInt().Int64() != 500 || pool.TotalSupply.ToInt().Int64() != 1000 {
+		t.Errorf("legacy pool mismatch: have backing %v, supply %v, want 500, 1000", pool.TotalBacking, pool.TotalSupply)
+	}
+	price, err := api.GetFloorPrice(context.Background(), legacy, latest)
+	if err != nil || price == nil || price.ToInt().Cmp(big.NewInt(5e17)) != 0 {
+		t.Errorf("legacy floor price mismatch: have %v (%v), want %v", price, err, 5e17)
+	}
+}
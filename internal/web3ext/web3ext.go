@@ -27,6 +27,8 @@ var Modules = map[string]string{
 	"rpc":    RpcJs,
 	"txpool": TxpoolJs,
 	"dev":    DevJs,
+
+	"smartdefi": SmartdefiJs,
 }
 
 const CliqueJs = `
@@ -731,3 +733,48 @@ web3._extend({
 	],
 });
 `
+
+const SmartdefiJs = `
+web3._extend({
+	property: 'smartdefi',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'getPool',
+			call: 'smartdefi_getPool',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getFloorPrice',
+			call: 'smartdefi_getFloorPrice',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getBackingForAmount',
+			call: 'smartdefi_getBackingForAmount',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.utils.toHex, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getFees',
+			call: 'smartdefi_getFees',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'listTokens',
+			call: 'smartdefi_listTokens',
+			params: 3,
+			inputFormatter: [web3._extend.utils.toHex, web3._extend.utils.toHex, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getFloorPriceHistory',
+			call: 'smartdefi_getFloorPriceHistory',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+	]
+});
+`
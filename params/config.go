@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"slices"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/params/forks"
@@ -468,6 +469,17 @@ type ChainConfig struct {
 	AmsterdamTime *uint64 `json:"amsterdamTime,omitempty"` // Amsterdam switch time (nil = no fork, 0 = already on amsterdam)
 	VerkleTime    *uint64 `json:"verkleTime,omitempty"`    // Verkle switch time (nil = no fork, 0 = already on verkle)
 
+	// SmartDeFiTime activates the SmartDeFi asset-backing precompile. It is
+	// not part of the Ethereum fork sequence and may be scheduled alongside any
+	// post-merge fork. Chains leaving it nil never install the precompile.
+	SmartDeFiTime *uint64 `json:"smartDeFiTime,omitempty"` // SmartDeFi switch time (nil = no fork, 0 = already on smartdefi)
+
+	// SmartDeFiLegacyTokens lists the tokens created by the asset-backing
+	// precompile before its storage namespaces, on chains which ran it before
+	// the SmartDeFi fork was scheduled. Their storage is migrated once, at the
+	// start of the first block of the fork.
+	SmartDeFiLegacyTokens []common.Address `json:"smartDeFiLegacyTokens,omitempty"`
+
 	// TerminalTotalDifficulty is the amount of total difficulty reached by
 	// the network that triggers the consensus upgrade.
 	TerminalTotalDifficulty *big.Int `json:"terminalTotalDifficulty,omitempty"`
@@ -598,6 +610,9 @@ func (c *ChainConfig) String() string {
 	if c.VerkleTime != nil {
 		result += fmt.Sprintf(", VerkleTime: %v", *c.VerkleTime)
 	}
+	if c.SmartDeFiTime != nil {
+		result += fmt.Sprintf(", SmartDeFiTime: %v", *c.SmartDeFiTime)
+	}
 	result += "}"
 	return result
 }
@@ -693,6 +708,9 @@ func (c *ChainConfig) Description() string {
 	if c.VerkleTime != nil {
 		banner += fmt.Sprintf(" - Verkle:                      @%-10v blob: (%s)\n", *c.VerkleTime, c.BlobScheduleConfig.Verkle)
 	}
+	if c.SmartDeFiTime != nil {
+		banner += fmt.Sprintf(" - SmartDeFi:                   @%-10v\n", *c.SmartDeFiTime)
+	}
 	banner += fmt.Sprintf("\nAll fork specifications can be found at https://ethereum.github.io/execution-specs/src/ethereum/forks/\n")
 	return banner
 }
@@ -871,6 +889,17 @@ func (c *ChainConfig) IsVerkle(num *big.Int, time uint64) bool {
 	return c.IsLondon(num) && isTimestampForked(c.VerkleTime, time)
 }
 
+// IsSmartDeFi returns whether time is either equal to the SmartDeFi fork time or greater.
+func (c *ChainConfig) IsSmartDeFi(num *big.Int, time uint64) bool {
+	return c.IsLondon(num) && isTimestampForked(c.SmartDeFiTime, time)
+}
+
+// IsSmartDeFiTransition returns whether the block at time, whose parent was
+// produced at parentTime, is the first block of the SmartDeFi fork.
+func (c *ChainConfig) IsSmartDeFiTransition(num *big.Int, parentTime, time uint64) bool {
+	return c.IsSmartDeFi(num, time) && !isTimestampForked(c.SmartDeFiTime, parentTime)
+}
+
 // IsVerkleGenesis checks whether the verkle fork is activated at the genesis block.
 //
 // Verkle mode is considered enabled if the verkle fork time is configured,
@@ -1125,6 +1154,12 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkTimestampIncompatible(c.AmsterdamTime, newcfg.AmsterdamTime, headTimestamp) {
 		return newTimestampCompatError("Amsterdam fork timestamp", c.AmsterdamTime, newcfg.AmsterdamTime)
 	}
+	if isForkTimestampIncompatible(c.SmartDeFiTime, newcfg.SmartDeFiTime, headTimestamp) {
+		return newTimestampCompatError("SmartDeFi fork timestamp", c.SmartDeFiTime, newcfg.SmartDeFiTime)
+	}
+	if !slices.Equal(c.SmartDeFiLegacyTokens, newcfg.SmartDeFiLegacyTokens) && isTimestampForked(c.SmartDeFiTime, headTimestamp) {
+		return newTimestampCompatError("SmartDeFi legacy tokens", c.SmartDeFiTime, newcfg.SmartDeFiTime)
+	}
 	return nil
 }
 
@@ -1382,6 +1417,7 @@ type Rules struct {
 	IsBerlin, IsLondon                                      bool
 	IsMerge, IsShanghai, IsCancun, IsPrague, IsOsaka        bool
 	IsAmsterdam, IsVerkle                                   bool
+	IsSmartDeFi                                             bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -1414,5 +1450,6 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool, timestamp uint64) Rules
 		IsAmsterdam:      isMerge && c.IsAmsterdam(num, timestamp),
 		IsVerkle:         isVerkle,
 		IsEIP4762:        isVerkle,
+		IsSmartDeFi:      isMerge && c.IsSmartDeFi(num, timestamp),
 	}
 }
@@ -17,12 +17,14 @@
 package params
 
 import (
+	"encoding/json"
 	"math"
 	"math/big"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/require"
 )
 
@@ -110,6 +112,51 @@ func TestCheckCompatible(t *testing.T) {
 				RewindToTime: 9,
 			},
 		},
+		{
+			stored:        &ChainConfig{SmartDeFiTime: newUint64(10)},
+			new:           &ChainConfig{SmartDeFiTime: newUint64(20)},
+			headTimestamp: 9,
+			wantErr:       nil,
+		},
+		{
+			stored:        &ChainConfig{SmartDeFiTime: newUint64(10)},
+			new:           &ChainConfig{SmartDeFiTime: newUint64(20)},
+			headTimestamp: 25,
+			wantErr: &ConfigCompatError{
+				What:         "SmartDeFi fork timestamp",
+				StoredTime:   newUint64(10),
+				NewTime:      newUint64(20),
+				RewindToTime: 9,
+			},
+		},
+		{
+			stored:        &ChainConfig{SmartDeFiTime: newUint64(10)},
+			new:           &ChainConfig{},
+			headTimestamp: 25,
+			wantErr: &ConfigCompatError{
+				What:         "SmartDeFi fork timestamp",
+				StoredTime:   newUint64(10),
+				NewTime:      nil,
+				RewindToTime: 9,
+			},
+		},
+		{
+			stored:        &ChainConfig{SmartDeFiTime: newUint64(10)},
+			new:           &ChainConfig{SmartDeFiTime: newUint64(10), SmartDeFiLegacyTokens: []common.Address{{0x1}}},
+			headTimestamp: 9,
+			wantErr:       nil,
+		},
+		{
+			stored:        &ChainConfig{SmartDeFiTime: newUint64(10)},
+			new:           &ChainConfig{SmartDeFiTime: newUint64(10), SmartDeFiLegacyTokens: []common.Address{{0x1}}},
+			headTimestamp: 25,
+			wantErr: &ConfigCompatError{
+				What:         "SmartDeFi legacy tokens",
+				StoredTime:   newUint64(10),
+				NewTime:      newUint64(10),
+				RewindToTime: 9,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -139,6 +186,47 @@ func TestConfigRules(t *testing.T) {
 	}
 }
 
+func TestSmartDeFiConfig(t *testing.T) {
+	var c ChainConfig
+	if err := json.Unmarshal([]byte(`{"chainId": 1, "londonBlock": 0, "smartDeFiTime": 500}`), &c); err != nil {
+		t.Fatalf("failed to decode config: %v", err)
+	}
+	if c.SmartDeFiTime == nil || *c.SmartDeFiTime != 500 {
+		t.Fatalf("smartDeFiTime mismatch: have %v, want 500", c.SmartDeFiTime)
+	}
+	if r := c.Rules(big.NewInt(0), true, 499); r.IsSmartDeFi {
+		t.Errorf("expected 499 to not be smartdefi")
+	}
+	if r := c.Rules(big.NewInt(0), true, 500); !r.IsSmartDeFi {
+		t.Errorf("expected 500 to be smartdefi")
+	}
+	if r := c.Rules(big.NewInt(0), false, 500); r.IsSmartDeFi {
+		t.Errorf("expected pre-merge rules to not be smartdefi")
+	}
+	if r := AllEthashProtocolChanges.Rules(big.NewInt(0), true, math.MaxInt64); r.IsSmartDeFi {
+		t.Errorf("expected config without smartDeFiTime to not be smartdefi")
+	}
+	if !c.IsSmartDeFiTransition(big.NewInt(1), 499, 500) {
+		t.Errorf("expected 500 after 499 to be the smartdefi transition")
+	}
+	if c.IsSmartDeFiTransition(big.NewInt(2), 500, 510) {
+		t.Errorf("expected 510 after 500 to not be the smartdefi transition")
+	}
+}
+
+// TestSmartDeFiOsaka checks that SmartDeFi can be scheduled before, at and after
+// Osaka.
+func TestSmartDeFiOsaka(t *testing.T) {
+	for _, time := range []uint64{0, 10, 20} {
+		config := *MergedTestChainConfig
+		config.OsakaTime = newUint64(10)
+		config.SmartDeFiTime = newUint64(time)
+		if err := config.CheckConfigForkOrder(); err != nil {
+			t.Errorf("smartDeFiTime %d: unexpected error: %v", time, err)
+		}
+	}
+}
+
 func TestTimestampCompatError(t *testing.T) {
 	require.Equal(t, new(ConfigCompatError).Error(), "")
 